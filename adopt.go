@@ -0,0 +1,36 @@
+package ifrit
+
+import (
+	"os"
+	"sync"
+)
+
+/*
+Adopt returns a Runner for work which is already running in the background,
+such as goroutines started by a third party library, so that it can be
+Invoked as a Process and take part in groups.
+
+The Runner is ready immediately.  The first signal it receives calls stop, on
+its own goroutine, and the Runner exits with the error received from done, or
+nil if done is closed.  Since the work only runs once, the Runner should only
+be run once.
+*/
+func Adopt(stop func(), done <-chan error) Runner {
+	var stopOnce sync.Once
+
+	return RunFunc(func(signals <-chan os.Signal, ready chan<- struct{}) error {
+		close(ready)
+
+		for {
+			select {
+			case <-signals:
+				stopOnce.Do(func() {
+					go stop()
+				})
+
+			case err := <-done:
+				return err
+			}
+		}
+	})
+}
@@ -0,0 +1,61 @@
+package ifrit_test
+
+import (
+	"errors"
+	"os"
+	"sync/atomic"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/tedsuo/ifrit"
+)
+
+var _ = Describe("Adopt", func() {
+	var stops int32
+	var quit chan struct{}
+	var done chan error
+
+	BeforeEach(func() {
+		stops = 0
+		quit = make(chan struct{})
+		done = make(chan error, 1)
+
+		go func() {
+			<-quit
+			done <- errors.New("stopped")
+		}()
+	})
+
+	stop := func() {
+		if atomic.AddInt32(&stops, 1) == 1 {
+			close(quit)
+		}
+	}
+
+	It("stops the adopted work when signaled", func() {
+		process := ifrit.Invoke(ifrit.Adopt(stop, done))
+		Ω(process.Ready()).Should(BeClosed())
+		Consistently(process.Wait()).ShouldNot(Receive())
+
+		process.Signal(os.Interrupt)
+		process.Signal(os.Kill)
+		Eventually(process.Wait()).Should(Receive(MatchError("stopped")))
+		Ω(atomic.LoadInt32(&stops)).Should(BeEquivalentTo(1))
+	})
+
+	It("exits when the adopted work finishes on its own", func() {
+		process := ifrit.Invoke(ifrit.Adopt(stop, done))
+		close(quit)
+
+		Eventually(process.Wait()).Should(Receive(MatchError("stopped")))
+		Ω(atomic.LoadInt32(&stops)).Should(BeZero())
+	})
+
+	It("exits cleanly when done is closed", func() {
+		closed := make(chan error)
+		process := ifrit.Invoke(ifrit.Adopt(func() { close(closed) }, closed))
+
+		process.Signal(os.Interrupt)
+		Eventually(process.Wait()).Should(Receive(BeNil()))
+	})
+})
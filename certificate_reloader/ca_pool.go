@@ -0,0 +1,121 @@
+package certificate_reloader
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"os"
+	"sync"
+	"time"
+)
+
+// ErrNoCertificates is returned when a CA file contains no PEM encoded certificates.
+var ErrNoCertificates = errors.New("certificate_reloader: no certificates found in CA file")
+
+// A CAPool serves a pool of the CA certificates in a PEM file, and reloads it on demand or whenever the file changes.
+type CAPool struct {
+	caFile string
+
+	lock    sync.RWMutex
+	pool    *x509.CertPool
+	modTime time.Time
+}
+
+// NewCAPool returns a CAPool for caFile, or an error if the file cannot be loaded.
+func NewCAPool(caFile string) (*CAPool, error) {
+	p := &CAPool{caFile: caFile}
+	return p, p.Reload()
+}
+
+// Reload loads the CA file.  The previous pool remains in use if loading fails.
+func (p *CAPool) Reload() error {
+	info, err := os.Stat(p.caFile)
+	if err != nil {
+		return err
+	}
+
+	pem, err := os.ReadFile(p.caFile)
+	if err != nil {
+		return err
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return ErrNoCertificates
+	}
+
+	p.lock.Lock()
+	p.pool = pool
+	p.modTime = info.ModTime()
+	p.lock.Unlock()
+	return nil
+}
+
+// Pool returns the most recently loaded pool.
+func (p *CAPool) Pool() *x509.CertPool {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	return p.pool
+}
+
+// ClientTLSConfig returns a copy of base, or an empty configuration if base is nil, which requires clients to present
+// a certificate signed by one of the pool's CAs.  Each handshake uses the most recently loaded pool.  If base has a
+// GetConfigForClient, the configs it returns are made to require client certificates in the same way.
+func (p *CAPool) ClientTLSConfig(base *tls.Config) *tls.Config {
+	var tlsConfig *tls.Config
+	if base != nil {
+		tlsConfig = base.Clone()
+	} else {
+		tlsConfig = &tls.Config{}
+	}
+	tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	tlsConfig.ClientCAs = p.Pool()
+
+	getConfigForClient := tlsConfig.GetConfigForClient
+	template := tlsConfig.Clone()
+	template.GetConfigForClient = nil
+	tlsConfig.GetConfigForClient = func(hello *tls.ClientHelloInfo) (*tls.Config, error) {
+		config := template
+		if getConfigForClient != nil {
+			clientConfig, err := getConfigForClient(hello)
+			if err != nil {
+				return nil, err
+			}
+			if clientConfig != nil {
+				config = clientConfig
+			}
+		}
+
+		config = config.Clone()
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+		config.ClientCAs = p.Pool()
+		return config, nil
+	}
+	return tlsConfig
+}
+
+// Watch polls the CA file every interval, reloading it when it has been modified, until done is closed.
+func (p *CAPool) Watch(interval time.Duration, done <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			info, err := os.Stat(p.caFile)
+			if err != nil {
+				continue
+			}
+
+			p.lock.RLock()
+			changed := !info.ModTime().Equal(p.modTime)
+			p.lock.RUnlock()
+
+			if changed {
+				p.Reload()
+			}
+		case <-done:
+			return
+		}
+	}
+}
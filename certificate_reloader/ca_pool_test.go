@@ -0,0 +1,130 @@
+package certificate_reloader_test
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"path"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/tedsuo/ifrit/certificate_reloader"
+)
+
+var _ = Describe("CAPool", func() {
+	var (
+		tmpdir string
+		caFile string
+		pool   *certificate_reloader.CAPool
+	)
+
+	// trusts reports whether certPool verifies the self-signed certificate in certFile
+	trusts := func(certPool *x509.CertPool, certFile string) bool {
+		contents, err := ioutil.ReadFile(certFile)
+		Expect(err).NotTo(HaveOccurred())
+		block, _ := pem.Decode(contents)
+		cert, err := x509.ParseCertificate(block.Bytes)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = cert.Verify(x509.VerifyOptions{Roots: certPool})
+		return err == nil
+	}
+
+	BeforeEach(func() {
+		var err error
+		tmpdir, err = ioutil.TempDir("", "ca-pool-test")
+		Expect(err).NotTo(HaveOccurred())
+
+		writePair(path.Join(tmpdir, "first.crt"), path.Join(tmpdir, "first.key"), "first")
+		writePair(path.Join(tmpdir, "second.crt"), path.Join(tmpdir, "second.key"), "second")
+
+		caFile = path.Join(tmpdir, "ca.crt")
+		first, err := ioutil.ReadFile(path.Join(tmpdir, "first.crt"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ioutil.WriteFile(caFile, first, 0600)).To(Succeed())
+
+		pool, err = certificate_reloader.NewCAPool(caFile)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tmpdir)
+	})
+
+	It("trusts the certificates in the file", func() {
+		Expect(trusts(pool.Pool(), path.Join(tmpdir, "first.crt"))).To(BeTrue())
+		Expect(trusts(pool.Pool(), path.Join(tmpdir, "second.crt"))).To(BeFalse())
+	})
+
+	It("fails when the file contains no certificates", func() {
+		Expect(ioutil.WriteFile(caFile, []byte("garbage"), 0600)).To(Succeed())
+		_, err := certificate_reloader.NewCAPool(caFile)
+		Expect(err).To(Equal(certificate_reloader.ErrNoCertificates))
+	})
+
+	Describe("Reload", func() {
+		It("keeps the previous pool when the file is invalid", func() {
+			Expect(ioutil.WriteFile(caFile, []byte("garbage"), 0600)).To(Succeed())
+			Expect(pool.Reload()).NotTo(Succeed())
+			Expect(trusts(pool.Pool(), path.Join(tmpdir, "first.crt"))).To(BeTrue())
+		})
+	})
+
+	Describe("Watch", func() {
+		var done chan struct{}
+
+		BeforeEach(func() {
+			done = make(chan struct{})
+			go pool.Watch(10*time.Millisecond, done)
+		})
+
+		AfterEach(func() {
+			close(done)
+		})
+
+		It("reloads the file when it changes", func() {
+			second, err := ioutil.ReadFile(path.Join(tmpdir, "second.crt"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ioutil.WriteFile(caFile, second, 0600)).To(Succeed())
+			future := time.Now().Add(time.Minute)
+			Expect(os.Chtimes(caFile, future, future)).To(Succeed())
+
+			Eventually(func() bool { return trusts(pool.Pool(), path.Join(tmpdir, "second.crt")) }).Should(BeTrue())
+		})
+	})
+
+	Describe("ClientTLSConfig", func() {
+		It("requires client certificates signed by the pool", func() {
+			base := &tls.Config{MinVersion: tls.VersionTLS12}
+
+			tlsConfig := pool.ClientTLSConfig(base)
+			Expect(tlsConfig.ClientAuth).To(Equal(tls.RequireAndVerifyClientCert))
+			Expect(tlsConfig.MinVersion).To(Equal(uint16(tls.VersionTLS12)))
+			Expect(base.ClientAuth).To(Equal(tls.NoClientCert))
+
+			handshakeConfig, err := tlsConfig.GetConfigForClient(&tls.ClientHelloInfo{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(handshakeConfig.ClientCAs).To(BeIdenticalTo(pool.Pool()))
+			Expect(handshakeConfig.MinVersion).To(Equal(uint16(tls.VersionTLS12)))
+		})
+
+		It("requires client certificates of the configs base's GetConfigForClient returns", func() {
+			perClient := &tls.Config{ServerName: "per-client"}
+			base := &tls.Config{
+				GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+					return perClient, nil
+				},
+			}
+
+			handshakeConfig, err := pool.ClientTLSConfig(base).GetConfigForClient(&tls.ClientHelloInfo{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(handshakeConfig.ServerName).To(Equal("per-client"))
+			Expect(handshakeConfig.ClientAuth).To(Equal(tls.RequireAndVerifyClientCert))
+			Expect(handshakeConfig.ClientCAs).To(BeIdenticalTo(pool.Pool()))
+			Expect(perClient.ClientCAs).To(BeNil())
+		})
+	})
+})
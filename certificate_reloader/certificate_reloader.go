@@ -0,0 +1,113 @@
+/*
+The certificate_reloader package serves a TLS certificate loaded from a cert/key
+file pair, and reloads it when the files change, so that long-lived servers can
+rotate their certificates without restarting.  It reloads pools of CA
+certificates, used to verify clients, in the same way.
+*/
+package certificate_reloader
+
+import (
+	"crypto/tls"
+	"os"
+	"sync"
+	"time"
+)
+
+// A Reloader serves a certificate loaded from a cert/key file pair, and reloads it on demand or whenever either file
+// changes.
+type Reloader struct {
+	certFile string
+	keyFile  string
+
+	lock        sync.RWMutex
+	certificate *tls.Certificate
+	modTimes    [2]time.Time
+}
+
+// New returns a Reloader for the certFile/keyFile pair, or an error if the pair cannot be loaded.
+func New(certFile, keyFile string) (*Reloader, error) {
+	r := &Reloader{
+		certFile: certFile,
+		keyFile:  keyFile,
+	}
+	return r, r.Reload()
+}
+
+// Reload loads the cert/key file pair.  The previous certificate remains in use if loading fails.
+func (r *Reloader) Reload() error {
+	modTimes, err := r.stat()
+	if err != nil {
+		return err
+	}
+
+	certificate, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return err
+	}
+
+	r.lock.Lock()
+	r.certificate = &certificate
+	r.modTimes = modTimes
+	r.lock.Unlock()
+	return nil
+}
+
+// GetCertificate implements tls.Config.GetCertificate.
+func (r *Reloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	return r.certificate, nil
+}
+
+// TLSConfig returns a copy of base, or an empty configuration if base is nil, which serves the reloader's
+// certificate in place of any certificates in base.
+func (r *Reloader) TLSConfig(base *tls.Config) *tls.Config {
+	var tlsConfig *tls.Config
+	if base != nil {
+		tlsConfig = base.Clone()
+	} else {
+		tlsConfig = &tls.Config{}
+	}
+	tlsConfig.Certificates = nil
+	tlsConfig.GetCertificate = r.GetCertificate
+	return tlsConfig
+}
+
+// Watch polls the cert/key file pair every interval, reloading it when either file has been modified, until done is
+// closed.
+func (r *Reloader) Watch(interval time.Duration, done <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			modTimes, err := r.stat()
+			if err != nil {
+				continue
+			}
+
+			r.lock.RLock()
+			changed := modTimes != r.modTimes
+			r.lock.RUnlock()
+
+			if changed {
+				r.Reload()
+			}
+		case <-done:
+			return
+		}
+	}
+}
+
+func (r *Reloader) stat() ([2]time.Time, error) {
+	var modTimes [2]time.Time
+	for i, file := range []string{r.certFile, r.keyFile} {
+		info, err := os.Stat(file)
+		if err != nil {
+			return modTimes, err
+		}
+		modTimes[i] = info.ModTime()
+	}
+	return modTimes, nil
+}
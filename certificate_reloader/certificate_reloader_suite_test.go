@@ -0,0 +1,13 @@
+package certificate_reloader_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestCertificateReloader(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Certificate Reloader Suite")
+}
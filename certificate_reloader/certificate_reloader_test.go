@@ -0,0 +1,141 @@
+package certificate_reloader_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/tedsuo/ifrit/certificate_reloader"
+)
+
+var _ = Describe("Reloader", func() {
+	var (
+		tmpdir   string
+		certFile string
+		keyFile  string
+		reloader *certificate_reloader.Reloader
+	)
+
+	servedCommonName := func() string {
+		cert, err := reloader.GetCertificate(&tls.ClientHelloInfo{})
+		Expect(err).NotTo(HaveOccurred())
+		parsed, err := x509.ParseCertificate(cert.Certificate[0])
+		Expect(err).NotTo(HaveOccurred())
+		return parsed.Subject.CommonName
+	}
+
+	BeforeEach(func() {
+		var err error
+		tmpdir, err = ioutil.TempDir("", "certificate-reloader-test")
+		Expect(err).NotTo(HaveOccurred())
+
+		certFile = path.Join(tmpdir, "cert.crt")
+		keyFile = path.Join(tmpdir, "cert.key")
+		writePair(certFile, keyFile, "first")
+
+		reloader, err = certificate_reloader.New(certFile, keyFile)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tmpdir)
+	})
+
+	It("serves the certificate in the files", func() {
+		Expect(servedCommonName()).To(Equal("first"))
+	})
+
+	It("fails when the files cannot be loaded", func() {
+		_, err := certificate_reloader.New(path.Join(tmpdir, "missing.crt"), keyFile)
+		Expect(err).To(HaveOccurred())
+	})
+
+	Describe("Reload", func() {
+		It("serves the new certificate", func() {
+			writePair(certFile, keyFile, "second")
+			Expect(reloader.Reload()).To(Succeed())
+			Expect(servedCommonName()).To(Equal("second"))
+		})
+
+		It("keeps the previous certificate when the files are invalid", func() {
+			Expect(ioutil.WriteFile(certFile, []byte("garbage"), 0600)).To(Succeed())
+			Expect(reloader.Reload()).NotTo(Succeed())
+			Expect(servedCommonName()).To(Equal("first"))
+		})
+	})
+
+	Describe("Watch", func() {
+		var done chan struct{}
+
+		BeforeEach(func() {
+			done = make(chan struct{})
+			go reloader.Watch(10*time.Millisecond, done)
+		})
+
+		AfterEach(func() {
+			close(done)
+		})
+
+		It("reloads the files when they change", func() {
+			writePair(certFile, keyFile, "second")
+			future := time.Now().Add(time.Minute)
+			Expect(os.Chtimes(certFile, future, future)).To(Succeed())
+
+			Eventually(servedCommonName).Should(Equal("second"))
+		})
+	})
+
+	Describe("TLSConfig", func() {
+		It("replaces the certificates of the base configuration", func() {
+			base := &tls.Config{
+				Certificates: []tls.Certificate{{}},
+				MinVersion:   tls.VersionTLS12,
+			}
+
+			tlsConfig := reloader.TLSConfig(base)
+			Expect(tlsConfig.Certificates).To(BeEmpty())
+			Expect(tlsConfig.GetCertificate).NotTo(BeNil())
+			Expect(tlsConfig.MinVersion).To(Equal(uint16(tls.VersionTLS12)))
+			Expect(base.Certificates).To(HaveLen(1))
+		})
+
+		It("creates a configuration when the base is nil", func() {
+			tlsConfig := reloader.TLSConfig(nil)
+			Expect(tlsConfig.GetCertificate).NotTo(BeNil())
+		})
+	})
+})
+
+// writePair writes a self-signed certificate for commonName, and its key, in PEM format.
+func writePair(certFile, keyFile, commonName string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).NotTo(HaveOccurred())
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	Expect(err).NotTo(HaveOccurred())
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	Expect(err).NotTo(HaveOccurred())
+
+	err = ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600)
+	Expect(err).NotTo(HaveOccurred())
+	err = ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600)
+	Expect(err).NotTo(HaveOccurred())
+}
@@ -0,0 +1,172 @@
+// Package cmd_runner runs commands as ifrit Runners.
+package cmd_runner
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"time"
+
+	"github.com/tedsuo/ifrit"
+)
+
+// An ExitError is returned by the runner when the command exits unsuccessfully.  Code is the command's exit status,
+// or -1 if it was terminated by a signal, in which case Signal is that signal.
+type ExitError struct {
+	Path   string
+	Code   int
+	Signal os.Signal
+	Err    *exec.ExitError
+}
+
+func (e *ExitError) Error() string {
+	if e.Signal != nil {
+		return fmt.Sprintf("%s was terminated by signal: %s", e.Path, e.Signal)
+	}
+	return fmt.Sprintf("%s exited with status %d", e.Path, e.Code)
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// A ReadyTimeoutError is returned by the runner when the command did not pass its readiness checks within the
+// timeout given to WithReadyTimeout.  The command is killed.
+type ReadyTimeoutError struct {
+	Path    string
+	Timeout time.Duration
+}
+
+func (e ReadyTimeoutError) Error() string {
+	return fmt.Sprintf("%s was not ready within %s", e.Path, e.Timeout)
+}
+
+type cmdRunner struct {
+	cmd          *exec.Cmd
+	translations map[os.Signal]os.Signal
+	processGroup bool
+	readyPattern *regexp.Regexp
+	readyAddress string
+	readyTimeout time.Duration
+}
+
+// New returns a Runner which starts cmd, and forwards the signals it receives to the command.  The runner is ready
+// once the command has started and passed the readiness checks given by WithReadyPattern and WithReadyAddress, and
+// exits once the command does, returning an ExitError if it was unsuccessful.  Since an exec.Cmd can only be run
+// once, so can the runner.
+func New(cmd *exec.Cmd, opts ...Option) ifrit.Runner {
+	r := &cmdRunner{cmd: cmd}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+func (r *cmdRunner) Run(signals <-chan os.Signal, ready chan<- struct{}) error {
+	if r.processGroup {
+		setProcessGroup(r.cmd)
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+
+	var checks []<-chan struct{}
+	if r.readyPattern != nil {
+		matcher := newLineMatcher(r.readyPattern)
+		if r.cmd.Stdout != nil {
+			r.cmd.Stdout = teeWriter{r.cmd.Stdout, matcher}
+		} else {
+			r.cmd.Stdout = matcher
+		}
+		checks = append(checks, matcher.matched)
+	}
+	if r.readyAddress != "" {
+		checks = append(checks, dialUntilListening(r.readyAddress, done))
+	}
+
+	err := r.cmd.Start()
+	if err != nil {
+		return err
+	}
+
+	exited := make(chan error, 1)
+	go func() {
+		exited <- r.cmd.Wait()
+	}()
+
+	passed := allClosed(checks, done)
+
+	var readyTimeout <-chan time.Time
+	if r.readyTimeout > 0 {
+		timer := time.NewTimer(r.readyTimeout)
+		defer timer.Stop()
+		readyTimeout = timer.C
+	}
+
+	for {
+		select {
+		case <-passed:
+			passed = nil
+			readyTimeout = nil
+			close(ready)
+
+		case <-readyTimeout:
+			r.signal(os.Kill)
+			r.exit(<-exited)
+			return ReadyTimeoutError{Path: r.cmd.Path, Timeout: r.readyTimeout}
+
+		case signal := <-signals:
+			if translated, found := r.translations[signal]; found {
+				signal = translated
+			}
+			r.signal(signal)
+
+		case err := <-exited:
+			return r.exit(err)
+		}
+	}
+}
+
+func (r *cmdRunner) signal(signal os.Signal) {
+	if r.processGroup {
+		signalProcessGroup(r.cmd.Process, signal)
+		return
+	}
+	r.cmd.Process.Signal(signal)
+}
+
+func (r *cmdRunner) exit(err error) error {
+	if r.processGroup {
+		signalProcessGroup(r.cmd.Process, os.Kill)
+	}
+
+	exitErr, ok := err.(*exec.ExitError)
+	if !ok {
+		return err
+	}
+
+	return &ExitError{
+		Path:   r.cmd.Path,
+		Code:   exitErr.ExitCode(),
+		Signal: exitSignal(exitErr.ProcessState),
+		Err:    exitErr,
+	}
+}
+
+func allClosed(channels []<-chan struct{}, done <-chan struct{}) <-chan struct{} {
+	closed := make(chan struct{})
+
+	go func() {
+		for _, c := range channels {
+			select {
+			case <-c:
+			case <-done:
+				return
+			}
+		}
+		close(closed)
+	}()
+
+	return closed
+}
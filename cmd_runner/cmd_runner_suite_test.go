@@ -0,0 +1,13 @@
+package cmd_runner_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestCmdRunner(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Cmd Runner Suite")
+}
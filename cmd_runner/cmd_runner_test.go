@@ -0,0 +1,201 @@
+package cmd_runner_test
+
+import (
+	"errors"
+	"net"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+	"github.com/tedsuo/ifrit"
+	"github.com/tedsuo/ifrit/cmd_runner"
+)
+
+var _ = Describe("CmdRunner", func() {
+	shell := func(script string) *exec.Cmd {
+		return exec.Command("/bin/sh", "-c", script)
+	}
+
+	// sh only runs traps between commands, so scripts sleep in short steps
+	const loop = "while true; do sleep 0.05; done"
+
+	It("exits cleanly when the command succeeds", func() {
+		process := ifrit.Invoke(cmd_runner.New(shell("exit 0")))
+		Eventually(process.Wait()).Should(Receive(BeNil()))
+	})
+
+	It("returns an ExitError with the exit status when the command fails", func() {
+		process := ifrit.Invoke(cmd_runner.New(shell("exit 3")))
+
+		var err error
+		Eventually(process.Wait()).Should(Receive(&err))
+		Ω(err).Should(MatchError("/bin/sh exited with status 3"))
+
+		var exitErr *cmd_runner.ExitError
+		Ω(errors.As(err, &exitErr)).Should(BeTrue())
+		Ω(exitErr.Code).Should(Equal(3))
+		Ω(exitErr.Signal).Should(BeNil())
+
+		var execErr *exec.ExitError
+		Ω(errors.As(err, &execErr)).Should(BeTrue())
+	})
+
+	It("returns the error when the command cannot be started", func() {
+		process := ifrit.Invoke(cmd_runner.New(exec.Command("/does/not/exist")))
+		Eventually(process.Wait()).Should(Receive(HaveOccurred()))
+		Ω(process.Ready()).ShouldNot(BeClosed())
+	})
+
+	It("forwards signals to the command", func() {
+		output := gbytes.NewBuffer()
+		cmd := shell("trap 'exit 7' TERM; echo ready; " + loop)
+		cmd.Stdout = output
+
+		process := ifrit.Invoke(cmd_runner.New(cmd, cmd_runner.WithReadyPattern(regexp.MustCompile("^ready$"))))
+		Ω(output).Should(gbytes.Say("ready"))
+
+		process.Signal(syscall.SIGTERM)
+		Eventually(process.Wait()).Should(Receive(MatchError("/bin/sh exited with status 7")))
+	})
+
+	It("reports the signal which terminated the command", func() {
+		process := ifrit.Invoke(cmd_runner.New(exec.Command("sleep", "10")))
+		process.Signal(os.Kill)
+
+		var err error
+		Eventually(process.Wait()).Should(Receive(&err))
+		var exitErr *cmd_runner.ExitError
+		Ω(errors.As(err, &exitErr)).Should(BeTrue())
+		Ω(exitErr.Code).Should(Equal(-1))
+		Ω(exitErr.Signal).Should(Equal(syscall.SIGKILL))
+		Ω(err.Error()).Should(HaveSuffix("was terminated by signal: killed"))
+	})
+
+	It("translates signals", func() {
+		runner := cmd_runner.New(
+			shell("trap 'exit 9' QUIT; echo ready; "+loop),
+			cmd_runner.WithReadyPattern(regexp.MustCompile("ready")),
+			cmd_runner.WithSignalTranslation(map[os.Signal]os.Signal{syscall.SIGTERM: syscall.SIGQUIT}),
+		)
+		process := ifrit.Invoke(runner)
+		process.Signal(syscall.SIGTERM)
+
+		var err error
+		Eventually(process.Wait()).Should(Receive(&err))
+		Ω(err).Should(MatchError("/bin/sh exited with status 9"))
+	})
+
+	Describe("readiness", func() {
+		It("waits for a line matching the ready pattern", func() {
+			runner := cmd_runner.New(
+				shell("echo starting; sleep 0.2; printf 'listen'; printf 'ing\\n'; "+loop),
+				cmd_runner.WithReadyPattern(regexp.MustCompile("^listening$")),
+			)
+			process := ifrit.Background(runner)
+			Consistently(process.Ready(), 100*time.Millisecond).ShouldNot(BeClosed())
+			Eventually(process.Ready()).Should(BeClosed())
+
+			process.Signal(os.Kill)
+			Eventually(process.Wait()).Should(Receive())
+		})
+
+		It("matches the end of lines longer than it keeps", func() {
+			runner := cmd_runner.New(
+				shell("head -c 200000 /dev/zero | tr '\\0' x; echo ready; "+loop),
+				cmd_runner.WithReadyPattern(regexp.MustCompile("xready$")),
+			)
+			process := ifrit.Background(runner)
+			Eventually(process.Ready()).Should(BeClosed())
+
+			process.Signal(os.Kill)
+			Eventually(process.Wait()).Should(Receive())
+		})
+
+		It("waits for the ready address to accept connections", func() {
+			listener, err := net.Listen("tcp", "127.0.0.1:0")
+			Ω(err).ShouldNot(HaveOccurred())
+			address := listener.Addr().String()
+			listener.Close()
+
+			runner := cmd_runner.New(exec.Command("sleep", "10"), cmd_runner.WithReadyAddress(address))
+			process := ifrit.Background(runner)
+			Consistently(process.Ready(), 100*time.Millisecond).ShouldNot(BeClosed())
+
+			listener, err = net.Listen("tcp", address)
+			Ω(err).ShouldNot(HaveOccurred())
+			defer listener.Close()
+			Eventually(process.Ready()).Should(BeClosed())
+
+			process.Signal(os.Kill)
+			Eventually(process.Wait()).Should(Receive())
+		})
+
+		It("kills a command which is not ready in time", func() {
+			cmd := exec.Command("sleep", "10")
+			runner := cmd_runner.New(
+				cmd,
+				cmd_runner.WithReadyPattern(regexp.MustCompile("never")),
+				cmd_runner.WithReadyTimeout(100*time.Millisecond),
+			)
+			process := ifrit.Invoke(runner)
+
+			var err error
+			Eventually(process.Wait()).Should(Receive(&err))
+			Ω(err).Should(Equal(cmd_runner.ReadyTimeoutError{Path: cmd.Path, Timeout: 100 * time.Millisecond}))
+		})
+	})
+
+	Describe("process groups", func() {
+		childPid := func(output *gbytes.Buffer) int {
+			Eventually(output).Should(gbytes.Say(`\d+\n`))
+			pid, err := strconv.Atoi(strings.TrimSpace(string(output.Contents())))
+			Ω(err).ShouldNot(HaveOccurred())
+			return pid
+		}
+
+		// orphaned processes may linger as zombies until they are reaped, which counts as dead
+		alive := func(pid int) func() bool {
+			return func() bool {
+				stat, err := os.ReadFile("/proc/" + strconv.Itoa(pid) + "/stat")
+				if err != nil {
+					return syscall.Kill(pid, 0) == nil
+				}
+				fields := strings.Fields(string(stat[strings.LastIndexByte(string(stat), ')')+1:]))
+				return fields[0] != "Z"
+			}
+		}
+
+		It("signals every process in the group", func() {
+			output := gbytes.NewBuffer()
+			cmd := shell("sleep 10 & echo $!; wait")
+			cmd.Stdout = output
+
+			process := ifrit.Invoke(cmd_runner.New(cmd, cmd_runner.WithProcessGroup()))
+			pid := childPid(output)
+			Ω(alive(pid)()).Should(BeTrue())
+
+			process.Signal(syscall.SIGTERM)
+			Eventually(process.Wait()).Should(Receive(HaveOccurred()))
+			Eventually(alive(pid)).Should(BeFalse())
+		})
+
+		It("kills processes left in the group when the command exits", func() {
+			output := gbytes.NewBuffer()
+			cmd := shell("sleep 10 >/dev/null & echo $!")
+			cmd.Stdout = output
+
+			process := ifrit.Invoke(cmd_runner.New(cmd, cmd_runner.WithProcessGroup()))
+			pid := childPid(output)
+
+			Eventually(process.Wait()).Should(Receive(BeNil()))
+			Eventually(alive(pid)).Should(BeFalse())
+		})
+	})
+})
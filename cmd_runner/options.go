@@ -0,0 +1,51 @@
+package cmd_runner
+
+import (
+	"os"
+	"regexp"
+	"time"
+)
+
+// An Option configures optional behavior of the runner returned by New.
+type Option func(*cmdRunner)
+
+// WithSignalTranslation delivers each signal in translations to the command as the signal it maps to, for commands
+// which expect a different signal to the one the runner is sent, such as a server which shuts down gracefully on
+// SIGQUIT rather than SIGTERM.  Other signals are delivered unchanged.
+func WithSignalTranslation(translations map[os.Signal]os.Signal) Option {
+	return func(r *cmdRunner) {
+		r.translations = translations
+	}
+}
+
+// WithProcessGroup starts the command in a process group of its own, and delivers signals to the whole group rather
+// than only the command, so that processes the command starts are stopped along with it.  When the command exits,
+// any processes remaining in the group are killed.
+func WithProcessGroup() Option {
+	return func(r *cmdRunner) {
+		r.processGroup = true
+	}
+}
+
+// WithReadyPattern delays readiness until a line the command writes to its standard output matches pattern.  The
+// output is still written to the command's Stdout, if it has one.  Only the last 64 KiB of longer lines are matched.
+func WithReadyPattern(pattern *regexp.Regexp) Option {
+	return func(r *cmdRunner) {
+		r.readyPattern = pattern
+	}
+}
+
+// WithReadyAddress delays readiness until a TCP connection to address succeeds.
+func WithReadyAddress(address string) Option {
+	return func(r *cmdRunner) {
+		r.readyAddress = address
+	}
+}
+
+// WithReadyTimeout kills the command if it has not passed its readiness checks within timeout, and the runner exits
+// with a ReadyTimeoutError.  By default the runner waits indefinitely.
+func WithReadyTimeout(timeout time.Duration) Option {
+	return func(r *cmdRunner) {
+		r.readyTimeout = timeout
+	}
+}
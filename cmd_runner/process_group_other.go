@@ -0,0 +1,18 @@
+//go:build !unix
+
+package cmd_runner
+
+import (
+	"os"
+	"os/exec"
+)
+
+func setProcessGroup(cmd *exec.Cmd) {}
+
+func signalProcessGroup(process *os.Process, signal os.Signal) {
+	process.Signal(signal)
+}
+
+func exitSignal(state *os.ProcessState) os.Signal {
+	return nil
+}
@@ -0,0 +1,33 @@
+//go:build unix
+
+package cmd_runner
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+func setProcessGroup(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Setpgid = true
+}
+
+func signalProcessGroup(process *os.Process, signal os.Signal) {
+	sig, ok := signal.(syscall.Signal)
+	if !ok {
+		process.Signal(signal)
+		return
+	}
+	syscall.Kill(-process.Pid, sig)
+}
+
+func exitSignal(state *os.ProcessState) os.Signal {
+	status, ok := state.Sys().(syscall.WaitStatus)
+	if !ok || !status.Signaled() {
+		return nil
+	}
+	return status.Signal()
+}
@@ -0,0 +1,94 @@
+package cmd_runner
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// maxPartialLine bounds how much of a line without a newline a lineMatcher keeps; the oldest bytes of longer lines
+// are dropped.
+const maxPartialLine = 64 * 1024
+
+type lineMatcher struct {
+	pattern *regexp.Regexp
+	matched chan struct{}
+
+	lock    sync.Mutex
+	partial []byte
+	found   bool
+}
+
+func newLineMatcher(pattern *regexp.Regexp) *lineMatcher {
+	return &lineMatcher{
+		pattern: pattern,
+		matched: make(chan struct{}),
+	}
+}
+
+func (m *lineMatcher) Write(p []byte) (int, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if m.found {
+		return len(p), nil
+	}
+
+	m.partial = append(m.partial, p...)
+	for {
+		i := bytes.IndexByte(m.partial, '\n')
+		if i < 0 {
+			if len(m.partial) > maxPartialLine {
+				m.partial = append([]byte(nil), m.partial[len(m.partial)-maxPartialLine:]...)
+			}
+			return len(p), nil
+		}
+
+		line := m.partial[:i]
+		m.partial = m.partial[i+1:]
+		if m.pattern.Match(line) {
+			m.found = true
+			m.partial = nil
+			close(m.matched)
+			return len(p), nil
+		}
+	}
+}
+
+type teeWriter struct {
+	writer  io.Writer
+	matcher *lineMatcher
+}
+
+func (t teeWriter) Write(p []byte) (int, error) {
+	t.matcher.Write(p)
+	return t.writer.Write(p)
+}
+
+const dialInterval = 50 * time.Millisecond
+
+func dialUntilListening(address string, done <-chan struct{}) <-chan struct{} {
+	listening := make(chan struct{})
+
+	go func() {
+		for {
+			conn, err := net.DialTimeout("tcp", address, dialInterval)
+			if err == nil {
+				conn.Close()
+				close(listening)
+				return
+			}
+
+			select {
+			case <-time.After(dialInterval):
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return listening
+}
@@ -0,0 +1,85 @@
+package ifrit
+
+import (
+	"context"
+	"fmt"
+	"os"
+)
+
+/*
+A RunnerContext is the context-based equivalent of a Runner.  Rather than
+receiving shutdown signals, it exits within a finite amount of time once ctx is
+done.  Its other responsibilities are those of a Runner.
+*/
+type RunnerContext interface {
+	Run(ctx context.Context, ready chan<- struct{}) error
+}
+
+/*
+The RunContextFunc type is an adapter to allow the use of ordinary functions as
+RunnerContexts.
+*/
+type RunContextFunc func(ctx context.Context, ready chan<- struct{}) error
+
+func (r RunContextFunc) Run(ctx context.Context, ready chan<- struct{}) error {
+	return r(ctx, ready)
+}
+
+/*
+A SignalCause is the cause, as reported by context.Cause, of the cancellation of
+a RunnerContext's context by the signal its Runner received.
+*/
+type SignalCause struct {
+	Signal os.Signal
+}
+
+func (c SignalCause) Error() string {
+	return fmt.Sprintf("received signal: %s", c.Signal)
+}
+
+/*
+FromRunnerContext returns a Runner which runs r, and cancels its context when
+the Runner receives its first signal.  Subsequent signals are ignored.
+*/
+func FromRunnerContext(r RunnerContext) Runner {
+	return RunFunc(func(signals <-chan os.Signal, ready chan<- struct{}) error {
+		ctx, cancel := context.WithCancelCause(context.Background())
+		defer cancel(nil)
+
+		errs := make(chan error, 1)
+		go func() {
+			errs <- r.Run(ctx, ready)
+		}()
+
+		for {
+			select {
+			case err := <-errs:
+				return err
+			case signal := <-signals:
+				cancel(SignalCause{Signal: signal})
+			}
+		}
+	})
+}
+
+/*
+ToRunnerContext returns a RunnerContext which runs r, and sends it signal once
+the context is done.
+*/
+func ToRunnerContext(r Runner, signal os.Signal) RunnerContext {
+	return RunContextFunc(func(ctx context.Context, ready chan<- struct{}) error {
+		signals := make(chan os.Signal, 1)
+		errs := make(chan error, 1)
+		go func() {
+			errs <- r.Run(signals, ready)
+		}()
+
+		select {
+		case err := <-errs:
+			return err
+		case <-ctx.Done():
+			signals <- signal
+			return <-errs
+		}
+	})
+}
@@ -0,0 +1,83 @@
+package ifrit_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"syscall"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/tedsuo/ifrit"
+	"github.com/tedsuo/ifrit/test_helpers"
+)
+
+var _ = Describe("RunnerContext", func() {
+	Describe("FromRunnerContext", func() {
+		var (
+			causes  chan error
+			process ifrit.Process
+		)
+
+		BeforeEach(func() {
+			causes = make(chan error, 1)
+			causes := causes
+			runner := ifrit.RunContextFunc(func(ctx context.Context, ready chan<- struct{}) error {
+				close(ready)
+				<-ctx.Done()
+				causes <- context.Cause(ctx)
+				return ctx.Err()
+			})
+			process = ifrit.Invoke(ifrit.FromRunnerContext(runner))
+		})
+
+		It("becomes ready when the runner does", func() {
+			Ω(process.Ready()).Should(BeClosed())
+			process.Signal(os.Interrupt)
+		})
+
+		It("cancels the context when signaled, with the signal as its cause", func() {
+			Consistently(process.Wait()).ShouldNot(Receive())
+
+			process.Signal(syscall.SIGTERM)
+			Eventually(process.Wait()).Should(Receive(Equal(context.Canceled)))
+			Ω(causes).Should(Receive(Equal(ifrit.SignalCause{Signal: syscall.SIGTERM})))
+		})
+
+		It("returns the runner's error when it exits by itself", func() {
+			exitErr := errors.New("done")
+			runner := ifrit.RunContextFunc(func(ctx context.Context, ready chan<- struct{}) error {
+				return exitErr
+			})
+			Ω(<-ifrit.Invoke(ifrit.FromRunnerContext(runner)).Wait()).Should(Equal(exitErr))
+			process.Signal(os.Interrupt)
+		})
+	})
+
+	Describe("ToRunnerContext", func() {
+		It("signals the runner once the context is done", func() {
+			recorder := test_helpers.NewSignalRecorder(syscall.SIGTERM)
+			runner := ifrit.ToRunnerContext(recorder, syscall.SIGTERM)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			ready := make(chan struct{})
+			errs := make(chan error, 1)
+			go func() {
+				errs <- runner.Run(ctx, ready)
+			}()
+
+			Eventually(ready).Should(BeClosed())
+			Consistently(errs).ShouldNot(Receive())
+
+			cancel()
+			Eventually(errs).Should(Receive(BeNil()))
+			Ω(recorder.ReceivedSignals()).Should(Equal([]os.Signal{syscall.SIGTERM}))
+		})
+
+		It("returns the runner's error when it exits by itself", func() {
+			runner := ifrit.ToRunnerContext(test_helpers.NoReadyRunner, os.Interrupt)
+			err := runner.Run(context.Background(), make(chan struct{}))
+			Ω(err).Should(Equal(test_helpers.NoReadyExitedNormally))
+		})
+	})
+})
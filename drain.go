@@ -0,0 +1,78 @@
+package ifrit
+
+import (
+	"os"
+	"time"
+)
+
+type drainSignal struct{}
+
+func (drainSignal) String() string { return "drain" }
+func (drainSignal) Signal()        {}
+
+/*
+Drain is a signal asking a Runner to stop taking new work, but to finish the
+work it has, and then exit.  It is distinct from the signals asking a Runner to
+stop now, so that Runners which support draining can shut down in two phases.
+Drain is never delivered by the operating system.
+
+Runners which do not understand Drain can be wrapped with DrainAsStop.
+*/
+var Drain os.Signal = drainSignal{}
+
+/*
+DrainAsStop returns a Runner which runs r, delivering Drain to it as
+stopSignal, for Runners which do not support draining.  Other signals are
+delivered unchanged.
+*/
+func DrainAsStop(r Runner, stopSignal os.Signal) Runner {
+	return RunFunc(func(signals <-chan os.Signal, ready chan<- struct{}) error {
+		innerSignals := make(chan os.Signal)
+		errs := make(chan error, 1)
+		go func() {
+			errs <- r.Run(innerSignals, ready)
+		}()
+
+		for {
+			select {
+			case signal := <-signals:
+				if signal == Drain {
+					signal = stopSignal
+				}
+				select {
+				case innerSignals <- signal:
+				case err := <-errs:
+					return err
+				}
+
+			case err := <-errs:
+				return err
+			}
+		}
+	})
+}
+
+/*
+DrainAndStop sends p Drain, and returns the error it exits with.  If p has not
+exited within grace, it is stopped with StopAndWait and timeout.
+*/
+func (s Stopper) DrainAndStop(p Process, grace, timeout time.Duration) error {
+	p.Signal(Drain)
+
+	timer := time.NewTimer(grace)
+	defer timer.Stop()
+
+	select {
+	case err := <-p.Wait():
+		return err
+	case <-timer.C:
+		return s.StopAndWait(p, timeout)
+	}
+}
+
+/*
+DrainAndStop drains and stops p using DefaultStopper, see Stopper.DrainAndStop.
+*/
+func DrainAndStop(p Process, grace, timeout time.Duration) error {
+	return DefaultStopper.DrainAndStop(p, grace, timeout)
+}
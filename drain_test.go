@@ -0,0 +1,60 @@
+package ifrit_test
+
+import (
+	"errors"
+	"os"
+	"syscall"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/tedsuo/ifrit"
+	"github.com/tedsuo/ifrit/test_helpers"
+)
+
+var _ = Describe("Drain", func() {
+	It("is called drain", func() {
+		Ω(ifrit.Drain.String()).Should(Equal("drain"))
+	})
+
+	Describe("DrainAsStop", func() {
+		It("delivers Drain as the stop signal", func() {
+			recorder := test_helpers.NewSignalRecorder(syscall.SIGTERM)
+			p := ifrit.Invoke(ifrit.DrainAsStop(recorder, syscall.SIGTERM))
+
+			p.Signal(syscall.SIGHUP)
+			Eventually(recorder.ReceivedSignals).Should(Equal([]os.Signal{syscall.SIGHUP}))
+
+			p.Signal(ifrit.Drain)
+			Eventually(p.Wait()).Should(Receive(BeNil()))
+			Ω(recorder.ReceivedSignals()).Should(Equal([]os.Signal{syscall.SIGHUP, syscall.SIGTERM}))
+		})
+	})
+
+	Describe("DrainAndStop", func() {
+		drained := errors.New("drained")
+
+		It("returns once the process finishes draining", func() {
+			draining := ifrit.RunFunc(func(signals <-chan os.Signal, ready chan<- struct{}) error {
+				close(ready)
+				for signal := range signals {
+					if signal == ifrit.Drain {
+						return drained
+					}
+				}
+				return nil
+			})
+
+			p := ifrit.Invoke(draining)
+			Ω(ifrit.DrainAndStop(p, time.Second, time.Second)).Should(Equal(drained))
+		})
+
+		It("stops the process once the grace period elapses", func() {
+			recorder := test_helpers.NewSignalRecorder(syscall.SIGTERM)
+			p := ifrit.Invoke(recorder)
+
+			Ω(ifrit.DrainAndStop(p, 50*time.Millisecond, time.Second)).Should(Succeed())
+			Ω(recorder.ReceivedSignals()).Should(Equal([]os.Signal{ifrit.Drain, syscall.SIGTERM}))
+		})
+	})
+})
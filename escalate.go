@@ -0,0 +1,140 @@
+package ifrit
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+	"time"
+
+	"github.com/tedsuo/ifrit/internal/signalqueue"
+)
+
+/*
+An Escalation describes how a Runner returned by Escalate insists that the
+Runner it wraps terminates.  Once the wrapped Runner has been sent one of
+TerminationSignals, it is given Deadline to exit before it is sent KillSignal.
+If ForceReturnAfter is non-zero and the Runner has still not exited once it
+elapses, the Runner is abandoned: its goroutine is left running, and Escalate's
+Runner returns without it.
+*/
+type Escalation struct {
+	Deadline           time.Duration
+	KillSignal         os.Signal // optional; defaults to os.Kill
+	ForceReturnAfter   time.Duration
+	TerminationSignals []os.Signal // optional; defaults to os.Interrupt and syscall.SIGTERM
+}
+
+/*
+A TerminationOutcome describes how a Runner returned by Escalate terminated.
+*/
+type TerminationOutcome int
+
+const (
+	TerminatedGracefully TerminationOutcome = iota // the Runner exited within the deadline
+	TerminatedByKill                               // the Runner exited after it was sent the kill signal
+	TerminationAbandoned                           // the Runner did not exit, and was abandoned
+)
+
+func (o TerminationOutcome) String() string {
+	switch o {
+	case TerminatedGracefully:
+		return "gracefully"
+	case TerminatedByKill:
+		return "by kill"
+	case TerminationAbandoned:
+		return "abandoned"
+	default:
+		return fmt.Sprintf("TerminationOutcome(%d)", int(o))
+	}
+}
+
+/*
+A TerminationError is returned by a Runner returned by Escalate when the Runner
+it wraps did not exit within the deadline.  Err is the error the Runner exited
+with, or nil if it was abandoned.
+*/
+type TerminationError struct {
+	Outcome  TerminationOutcome
+	Deadline time.Duration
+	Err      error
+}
+
+func (e TerminationError) Error() string {
+	if e.Outcome == TerminationAbandoned {
+		return fmt.Sprintf("runner did not terminate within %s and was abandoned", e.Deadline)
+	}
+	return fmt.Sprintf("runner did not terminate within %s and was terminated %s", e.Deadline, e.Outcome)
+}
+
+func (e TerminationError) Unwrap() error {
+	return e.Err
+}
+
+/*
+Escalate returns a Runner which runs r, forwarding every signal to it, and
+escalates its termination as described by e.  If r exits within the deadline
+its error is returned as is; otherwise a TerminationError reports how it
+terminated.
+*/
+func Escalate(r Runner, e Escalation) Runner {
+	killSignal := e.KillSignal
+	if killSignal == nil {
+		killSignal = os.Kill
+	}
+	terminationSignals := e.TerminationSignals
+	if terminationSignals == nil {
+		terminationSignals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+	}
+
+	isTermination := func(signal os.Signal) bool {
+		for _, s := range terminationSignals {
+			if s == signal {
+				return true
+			}
+		}
+		return false
+	}
+
+	return RunFunc(func(signals <-chan os.Signal, ready chan<- struct{}) error {
+		innerSignals := make(chan os.Signal)
+		errs := make(chan error, 1)
+		go func() {
+			errs <- r.Run(innerSignals, ready)
+		}()
+
+		// signals are forwarded without blocking, so that a runner which has stopped receiving them can still be
+		// abandoned
+		done := make(chan struct{})
+		defer close(done)
+		forward := signalqueue.Forward(innerSignals, done).Send
+
+		var deadline, forceReturn <-chan time.Time
+		killed := false
+		for {
+			select {
+			case err := <-errs:
+				if killed {
+					return TerminationError{Outcome: TerminatedByKill, Deadline: e.Deadline, Err: err}
+				}
+				return err
+
+			case signal := <-signals:
+				forward(signal)
+				if isTermination(signal) && deadline == nil && !killed {
+					deadline = time.After(e.Deadline)
+				}
+
+			case <-deadline:
+				deadline = nil
+				killed = true
+				forward(killSignal)
+				if e.ForceReturnAfter > 0 {
+					forceReturn = time.After(e.ForceReturnAfter)
+				}
+
+			case <-forceReturn:
+				return TerminationError{Outcome: TerminationAbandoned, Deadline: e.Deadline}
+			}
+		}
+	})
+}
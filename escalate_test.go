@@ -0,0 +1,121 @@
+package ifrit_test
+
+import (
+	"errors"
+	"os"
+	"syscall"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/tedsuo/ifrit"
+	"github.com/tedsuo/ifrit/test_helpers"
+)
+
+var _ = Describe("Escalate", func() {
+	escalation := ifrit.Escalation{Deadline: 50 * time.Millisecond}
+
+	It("returns the runner's error when it terminates within the deadline", func() {
+		recorder := test_helpers.NewSignalRecorder(syscall.SIGTERM)
+		process := ifrit.Invoke(ifrit.Escalate(recorder, escalation))
+
+		process.Signal(syscall.SIGTERM)
+		Eventually(process.Wait()).Should(Receive(BeNil()))
+		Ω(recorder.ReceivedSignals()).Should(Equal([]os.Signal{syscall.SIGTERM}))
+	})
+
+	It("forwards other signals without starting the deadline", func() {
+		recorder := test_helpers.NewSignalRecorder()
+		process := ifrit.Invoke(ifrit.Escalate(recorder, escalation))
+
+		process.Signal(syscall.SIGHUP)
+		Eventually(recorder.ReceivedSignals).Should(Equal([]os.Signal{syscall.SIGHUP}))
+		Consistently(process.Wait(), 100*time.Millisecond).ShouldNot(Receive())
+
+		process.Signal(os.Interrupt)
+		Eventually(process.Wait()).Should(Receive(BeNil()))
+	})
+
+	It("sends the kill signal once the deadline elapses", func() {
+		recorder := test_helpers.NewSignalRecorder()
+		process := ifrit.Invoke(ifrit.Escalate(recorder, ifrit.Escalation{
+			Deadline:           50 * time.Millisecond,
+			TerminationSignals: []os.Signal{syscall.SIGTERM},
+		}))
+
+		process.Signal(syscall.SIGTERM)
+		var err error
+		Eventually(process.Wait()).Should(Receive(&err))
+		Ω(err).Should(Equal(ifrit.TerminationError{Outcome: ifrit.TerminatedByKill, Deadline: 50 * time.Millisecond}))
+		Ω(recorder.ReceivedSignals()).Should(Equal([]os.Signal{syscall.SIGTERM, os.Kill}))
+	})
+
+	It("abandons the runner if it still has not exited", func() {
+		stuck := ifrit.RunFunc(func(signals <-chan os.Signal, ready chan<- struct{}) error {
+			close(ready)
+			select {}
+		})
+		process := ifrit.Invoke(ifrit.Escalate(stuck, ifrit.Escalation{
+			Deadline:         50 * time.Millisecond,
+			ForceReturnAfter: 50 * time.Millisecond,
+		}))
+
+		process.Signal(os.Interrupt)
+		var err error
+		Eventually(process.Wait()).Should(Receive(&err))
+		Ω(err).Should(Equal(ifrit.TerminationError{Outcome: ifrit.TerminationAbandoned, Deadline: 50 * time.Millisecond}))
+		Ω(err.Error()).Should(Equal("runner did not terminate within 50ms and was abandoned"))
+	})
+
+	It("wraps the error of a killed runner", func() {
+		killErr := errors.New("killed")
+		runner := ifrit.RunFunc(func(signals <-chan os.Signal, ready chan<- struct{}) error {
+			close(ready)
+			for signal := range signals {
+				if signal == os.Kill {
+					return killErr
+				}
+			}
+			return nil
+		})
+		process := ifrit.Invoke(ifrit.Escalate(runner, escalation))
+
+		process.Signal(os.Interrupt)
+		var err error
+		Eventually(process.Wait()).Should(Receive(&err))
+		Ω(errors.Is(err, killErr)).Should(BeTrue())
+	})
+
+	It("forwards signals in the order they were received", func() {
+		release := make(chan struct{})
+		received := make(chan os.Signal, 10)
+		slow := ifrit.RunFunc(func(signals <-chan os.Signal, ready chan<- struct{}) error {
+			close(ready)
+			<-release
+			for signal := range signals {
+				received <- signal
+				if signal == syscall.SIGTERM {
+					return nil
+				}
+			}
+			return nil
+		})
+
+		signals := make(chan os.Signal)
+		errs := make(chan error, 1)
+		go func() {
+			errs <- ifrit.Escalate(slow, ifrit.Escalation{Deadline: time.Second}).Run(signals, make(chan struct{}))
+		}()
+
+		sent := []os.Signal{syscall.SIGHUP, syscall.SIGUSR1, syscall.SIGUSR2, syscall.SIGTERM}
+		for _, signal := range sent {
+			signals <- signal
+		}
+		close(release)
+
+		Eventually(errs).Should(Receive(BeNil()))
+		for _, signal := range sent {
+			Ω(received).Should(Receive(Equal(signal)))
+		}
+	})
+})
@@ -0,0 +1,128 @@
+package ifrit
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+/*
+A LifecycleEventKind identifies a stage in the lifecycle of a Process.
+*/
+type LifecycleEventKind int
+
+const (
+	// EventStarted is emitted when the Runner starts running.
+	EventStarted LifecycleEventKind = iota
+	// EventReady is emitted when the Runner becomes ready.
+	EventReady
+	// EventSignaled is emitted each time the Process is sent a signal.
+	EventSignaled
+	// EventExited is emitted when the Runner returns.
+	EventExited
+)
+
+func (k LifecycleEventKind) String() string {
+	switch k {
+	case EventStarted:
+		return "started"
+	case EventReady:
+		return "ready"
+	case EventSignaled:
+		return "signaled"
+	case EventExited:
+		return "exited"
+	default:
+		return fmt.Sprintf("LifecycleEventKind(%d)", int(k))
+	}
+}
+
+/*
+A LifecycleEvent records a stage in the lifecycle of a Process, and when it
+happened.  Signal is set for EventSignaled, and Err for EventExited.
+*/
+type LifecycleEvent struct {
+	Kind   LifecycleEventKind
+	Time   time.Time
+	Signal os.Signal
+	Err    error
+}
+
+/*
+InvokeWithEvents is like Invoke, but also returns a channel of the Process's
+LifecycleEvents, see BackgroundWithEvents.
+*/
+func InvokeWithEvents(r Runner) (Process, <-chan LifecycleEvent) {
+	p, events := BackgroundWithEvents(r)
+
+	select {
+	case <-p.Ready():
+	case <-p.Wait():
+	}
+
+	return p, events
+}
+
+/*
+BackgroundWithEvents is like Background, but also returns a channel of the
+Process's LifecycleEvents.  Events are delivered in order, and buffered so that
+a slow reader never delays the Runner.  The channel is closed after
+EventExited.
+*/
+func BackgroundWithEvents(r Runner) (Process, <-chan LifecycleEvent) {
+	in := make(chan LifecycleEvent)
+	emit := func(event LifecycleEvent) {
+		event.Time = time.Now()
+		in <- event
+	}
+
+	p := Background(WithHooks(r, Hooks{
+		BeforeRun: func() {
+			emit(LifecycleEvent{Kind: EventStarted})
+		},
+		OnReady: func() {
+			emit(LifecycleEvent{Kind: EventReady})
+		},
+		OnSignal: func(signal os.Signal) {
+			emit(LifecycleEvent{Kind: EventSignaled, Signal: signal})
+		},
+		OnExit: func(err error) {
+			emit(LifecycleEvent{Kind: EventExited, Err: err})
+			close(in)
+		},
+	}))
+
+	return p, bufferEvents(in)
+}
+
+func bufferEvents(in <-chan LifecycleEvent) <-chan LifecycleEvent {
+	out := make(chan LifecycleEvent)
+
+	go func() {
+		defer close(out)
+
+		var queue []LifecycleEvent
+		for in != nil || len(queue) > 0 {
+			var send chan<- LifecycleEvent
+			var next LifecycleEvent
+			if len(queue) > 0 {
+				send = out
+				next = queue[0]
+			}
+
+			select {
+			case event, ok := <-in:
+				if !ok {
+					in = nil
+					continue
+				}
+				queue = append(queue, event)
+
+			case send <- next:
+				queue = queue[1:]
+			}
+		}
+	}()
+
+	return out
+}
@@ -0,0 +1,53 @@
+package ifrit_test
+
+import (
+	"os"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/tedsuo/ifrit"
+	"github.com/tedsuo/ifrit/test_helpers"
+)
+
+var _ = Describe("LifecycleEvents", func() {
+	It("emits each stage of the lifecycle in order", func() {
+		before := time.Now()
+		p, events := ifrit.InvokeWithEvents(make(test_helpers.PingChan))
+		p.Signal(os.Kill)
+		Eventually(p.Wait()).Should(Receive())
+
+		var received []ifrit.LifecycleEvent
+		for event := range events {
+			received = append(received, event)
+		}
+
+		Ω(received).Should(HaveLen(4))
+		Ω(received[0].Kind).Should(Equal(ifrit.EventStarted))
+		Ω(received[1].Kind).Should(Equal(ifrit.EventReady))
+		Ω(received[2].Kind).Should(Equal(ifrit.EventSignaled))
+		Ω(received[2].Signal).Should(Equal(os.Kill))
+		Ω(received[3].Kind).Should(Equal(ifrit.EventExited))
+		Ω(received[3].Err).Should(Equal(test_helpers.PingerExitedFromSignal))
+
+		Ω(received[0].Time).Should(BeTemporally(">=", before))
+		for i := 1; i < len(received); i++ {
+			Ω(received[i].Time).Should(BeTemporally(">=", received[i-1].Time))
+		}
+	})
+
+	It("does not emit ready for a runner which never becomes ready", func() {
+		_, events := ifrit.InvokeWithEvents(test_helpers.NoReadyRunner)
+
+		var kinds []ifrit.LifecycleEventKind
+		for event := range events {
+			kinds = append(kinds, event.Kind)
+		}
+		Ω(kinds).Should(Equal([]ifrit.LifecycleEventKind{ifrit.EventStarted, ifrit.EventExited}))
+	})
+
+	It("names each kind of event", func() {
+		Ω(ifrit.EventSignaled.String()).Should(Equal("signaled"))
+		Ω(ifrit.LifecycleEventKind(9).String()).Should(Equal("LifecycleEventKind(9)"))
+	})
+})
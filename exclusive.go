@@ -0,0 +1,31 @@
+package ifrit
+
+import (
+	"errors"
+	"os"
+	"sync/atomic"
+)
+
+/*
+ErrAlreadyInvoked is returned by Runners which may not be run more than once at
+a time, such as those returned by Exclusive, when they are already running.
+*/
+var ErrAlreadyInvoked = errors.New("runner is already running")
+
+/*
+Exclusive returns a Runner which runs r, but returns ErrAlreadyInvoked without
+running r if it is run again while an earlier run has not returned.  Once a run
+has returned, the Runner may be run again.
+*/
+func Exclusive(r Runner) Runner {
+	var running atomic.Bool
+
+	return RunFunc(func(signals <-chan os.Signal, ready chan<- struct{}) error {
+		if !running.CompareAndSwap(false, true) {
+			return ErrAlreadyInvoked
+		}
+		defer running.Store(false)
+
+		return r.Run(signals, ready)
+	})
+}
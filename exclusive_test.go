@@ -0,0 +1,28 @@
+package ifrit_test
+
+import (
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/tedsuo/ifrit"
+	"github.com/tedsuo/ifrit/test_helpers"
+)
+
+var _ = Describe("Exclusive", func() {
+	It("refuses to run again while it is running", func() {
+		runner := ifrit.Exclusive(make(test_helpers.PingChan))
+		first := ifrit.Invoke(runner)
+
+		second := ifrit.Invoke(runner)
+		Ω(<-second.Wait()).Should(Equal(ifrit.ErrAlreadyInvoked))
+
+		first.Signal(os.Kill)
+		Ω(<-first.Wait()).Should(Equal(test_helpers.PingerExitedFromSignal))
+
+		third := ifrit.Invoke(runner)
+		Ω(third.Ready()).Should(BeClosed())
+		third.Signal(os.Kill)
+		Ω(<-third.Wait()).Should(Equal(test_helpers.PingerExitedFromSignal))
+	})
+})
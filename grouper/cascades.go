@@ -0,0 +1,113 @@
+package grouper
+
+import (
+	"fmt"
+	"os"
+)
+
+/*
+Cascades maps the name of a member to the names of the dependencies whose
+restarts it follows.  Each must also be one of the member's Dependencies.
+*/
+type Cascades map[string][]string
+
+/*
+NewSupervisedGraph creates a Supervisor which starts its members in dependency
+order, as NewGraph does, and shuts them down in reverse dependency order.
+
+When a Supervisor restarts a member, each member which cascades from it is
+stopped as well, dependents first, with the termination signal, or os.Interrupt
+if there is none.  Once the restarted member is ready again, its cascaded
+dependents are started again in dependency order.  Cascaded restarts do not
+count towards a member's MaxRestarts, and their exits are not recorded.
+
+Use cascades to model members which cache connections to their dependencies,
+and must be bounced along with them.
+*/
+func NewSupervisedGraph(terminationSignal os.Signal, members Members, dependencies Dependencies, cascades Cascades, opts ...Option) *Supervisor {
+	s := NewSupervisor(terminationSignal, members, opts...)
+	s.group.dependencies = dependencies
+	s.group.cascades = cascades
+	return s
+}
+
+/*
+ErrUnknownCascade is returned when a member cascades from a member which it does
+not depend upon.
+*/
+type ErrUnknownCascade struct {
+	Member     string
+	Dependency string
+}
+
+func (e ErrUnknownCascade) Error() string {
+	return fmt.Sprintf("%s cascades from %s, which it does not depend on", e.Member, e.Dependency)
+}
+
+func validateCascades(dependencies Dependencies, cascades Cascades) error {
+	for name, cascadesFrom := range cascades {
+		for _, cascade := range cascadesFrom {
+			found := false
+			for _, dependency := range dependencies[name] {
+				if dependency == cascade {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return ErrUnknownCascade{Member: name, Dependency: cascade}
+			}
+		}
+	}
+	return nil
+}
+
+/*
+cascade marks each running member which cascades from m, directly or
+indirectly, to be restarted along with it, and begins stopping them.
+*/
+func (r *groupRun) cascade(m *memberRun) {
+	for _, dependent := range m.cascadeDependents {
+		if dependent.cascading || (dependent.state != MemberStarting && dependent.state != MemberReady) {
+			continue
+		}
+		dependent.cascading = true
+		r.cascade(dependent)
+	}
+	r.signalCascading()
+}
+
+/*
+signalCascading stops each cascading member once every cascading member which
+depends upon it has exited.
+*/
+func (r *groupRun) signalCascading() {
+	for _, m := range r.members {
+		if !m.cascading || (m.state != MemberStarting && m.state != MemberReady) {
+			continue
+		}
+
+		eligible := true
+		for _, dependent := range m.cascadeDependents {
+			if dependent.cascading {
+				eligible = false
+				break
+			}
+		}
+
+		if eligible {
+			m.state = MemberStopping
+			m.process.Signal(r.group.stopSignal())
+		}
+	}
+}
+
+/*
+cascaded records that a cascading member has exited, so that it will be started
+again once its dependencies are ready.
+*/
+func (r *groupRun) cascaded(m *memberRun) {
+	m.state = MemberPending
+	r.signalCascading()
+	r.startEligible()
+}
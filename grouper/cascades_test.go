@@ -0,0 +1,117 @@
+package grouper_test
+
+import (
+	"errors"
+	"os"
+	"time"
+
+	"github.com/tedsuo/ifrit"
+	"github.com/tedsuo/ifrit/ginkgomon"
+	"github.com/tedsuo/ifrit/grouper"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Restart cascades", func() {
+	var (
+		supervisor   *grouper.Supervisor
+		groupProcess ifrit.Process
+
+		db      *gatedRunner
+		cache   *gatedRunner
+		web     *gatedRunner
+		metrics *gatedRunner
+
+		Δ time.Duration = 10 * time.Millisecond
+	)
+
+	BeforeEach(func() {
+		db = newGatedRunner()
+		cache = newGatedRunner()
+		web = newGatedRunner()
+		metrics = newGatedRunner()
+
+		supervisor = grouper.NewSupervisedGraph(os.Interrupt, grouper.Members{
+			{Name: "db", Runner: db},
+			{Name: "cache", Runner: cache},
+			{Name: "web", Runner: web},
+			{Name: "metrics", Runner: metrics},
+		}, grouper.Dependencies{
+			"cache":   {"db"},
+			"web":     {"cache"},
+			"metrics": {"db"},
+		}, grouper.Cascades{
+			"cache": {"db"},
+			"web":   {"cache"},
+		}, grouper.WithMemberOptions("db", grouper.MemberOptions{Restart: grouper.RestartPolicy{Mode: grouper.RestartOnFailure}}))
+		groupProcess = ifrit.Background(supervisor)
+
+		db.ready <- struct{}{}
+		cache.ready <- struct{}{}
+		metrics.ready <- struct{}{}
+		web.ready <- struct{}{}
+		Eventually(groupProcess.Ready()).Should(BeClosed())
+	})
+
+	AfterEach(func() {
+		ginkgomon.Kill(groupProcess)
+	})
+
+	It("restarts the members which cascade from a restarted member, in dependency order", func() {
+		db.exits <- errors.New("Fail")
+
+		Eventually(web.signals).Should(Receive(Equal(os.Interrupt)))
+		Eventually(cache.signals).Should(Receive(Equal(os.Interrupt)))
+		Consistently(metrics.signals, Δ).ShouldNot(Receive())
+
+		Eventually(db.Runs).Should(Equal(2))
+		Consistently(cache.Runs, Δ).Should(Equal(1))
+
+		db.ready <- struct{}{}
+		Eventually(cache.Runs).Should(Equal(2))
+		Consistently(web.Runs, Δ).Should(Equal(1))
+
+		cache.ready <- struct{}{}
+		Eventually(web.Runs).Should(Equal(2))
+		web.ready <- struct{}{}
+
+		Eventually(func() []grouper.MemberState {
+			snapshot, _ := supervisor.Snapshot()
+			states := []grouper.MemberState{}
+			for _, status := range snapshot {
+				states = append(states, status.State)
+			}
+			return states
+		}).Should(Equal([]grouper.MemberState{grouper.MemberReady, grouper.MemberReady, grouper.MemberReady, grouper.MemberReady}))
+
+		snapshot, err := supervisor.Snapshot()
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(snapshot[0].Restarts).Should(Equal(1))
+		Ω(snapshot[1].Restarts).Should(Equal(0))
+		Consistently(groupProcess.Wait(), Δ).ShouldNot(Receive())
+	})
+
+	It("shuts down cleanly while a cascade is in progress", func() {
+		db.exits <- errors.New("Fail")
+		Eventually(cache.signals).Should(Receive())
+		Eventually(db.Runs).Should(Equal(2))
+
+		groupProcess.Signal(os.Interrupt)
+		Eventually(metrics.signals).Should(Receive(Equal(os.Interrupt)))
+		Eventually(db.signals).Should(Receive(Equal(os.Interrupt)))
+		Eventually(groupProcess.Wait()).Should(Receive(BeNil()))
+	})
+})
+
+var _ = Describe("NewSupervisedGraph", func() {
+	It("rejects a cascade which is not a dependency", func() {
+		supervisor := grouper.NewSupervisedGraph(os.Interrupt, grouper.Members{
+			{Name: "db", Runner: newGatedRunner()},
+			{Name: "web", Runner: newGatedRunner()},
+		}, nil, grouper.Cascades{"web": {"db"}})
+
+		process := ifrit.Background(supervisor)
+		Eventually(process.Wait()).Should(Receive(Equal(grouper.ErrUnknownCascade{Member: "web", Dependency: "db"})))
+	})
+})
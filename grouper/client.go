@@ -1,7 +1,11 @@
 package grouper
 
 import (
+	"errors"
+	"fmt"
+	"os"
 	"sync"
+	"time"
 
 	"github.com/tedsuo/ifrit"
 )
@@ -30,6 +34,25 @@ type DynamicClient interface {
 	*/
 	ExitListener() <-chan ExitEvent
 
+	/*
+	   CrashLoopListener provides a new buffered channel of crash loop events, which
+	   are emited every time a member is found to be in a crash loop.  Like the other
+	   listeners, every new channel is populated with previously emited events, up to
+	   it's buffer size.  No events are emited unless the group was created with
+	   WithCrashLoopLimit.
+	*/
+	CrashLoopListener() <-chan CrashLoopEvent
+
+	/*
+	   Events provides a new buffered channel of membership events, which are emited
+	   every time a member is started, becomes ready, or exits.  If replay is true,
+	   the channel begins with the current membership of the group: an entered event
+	   for each running member, followed by a ready event if it is ready, in the
+	   order they were started.  Later events follow on from the replayed ones,
+	   without gaps.  The channel is closed once the group exits.
+	*/
+	Events(replay bool) <-chan MembershipEvent
+
 	/*
 	   CloseNotifier provides a new unbuffered channel, which will emit a single event
 	   once the group has been closed.
@@ -42,6 +65,16 @@ type DynamicClient interface {
 	*/
 	Inserter() chan<- Member
 
+	/*
+	   Pause quiesces the group for maintenance, while keeping its members running.
+	   A paused group admits no new members, so the insert channel blocks, and
+	   ReplaceMember returns ErrGroupPaused.  Each running member whose Runner is
+	   Pausable is paused.  Resume undoes Pause.  Both return ErrGroupStopped once
+	   the group has been signaled.
+	*/
+	Pause() error
+	Resume() error
+
 	/*
 	   Close causes a dynamic group to become a static group. This means that no new
 	   members may be inserted, and the group will exit once all members have
@@ -50,6 +83,85 @@ type DynamicClient interface {
 	Close()
 
 	Get(name string) (ifrit.Process, bool)
+
+	/*
+	   RemoveMember stops the named member with the group's termination signal, or
+	   os.Interrupt if the group has none, and waits for it to exit.  Unlike other
+	   exits, the member's exit does not shut the group down.  RemoveMember returns
+	   the error the member exited with, ErrMemberNotFound if there is no such
+	   member, ErrMemberBusy if it is being replaced, or ErrGroupStopped once the
+	   group has been signaled.
+	*/
+	RemoveMember(name string) error
+
+	/*
+	   ReplaceMember starts member in place of the running member with the same
+	   name.  The old member keeps running until the new one is ready, and is then
+	   stopped as RemoveMember does; ReplaceMember returns once it has exited.  If
+	   the new member exits before it is ready, the old member keeps running, and
+	   ReplaceMember returns the new member's error, or ErrReplacementExited.
+	   ReplaceMember returns ErrGroupPaused while the group is paused.
+	*/
+	ReplaceMember(member Member) error
+
+	/*
+	   SignalMember sends signal to the named member, without signaling the rest of
+	   the group.  The member's exit, if the signal causes one, is handled as any
+	   other exit would be.  SignalMember returns ErrMemberNotFound if there is no
+	   such member, or ErrGroupStopped once the group has exited.
+	*/
+	SignalMember(name string, signal os.Signal) error
+
+	/*
+	   SignalMembers sends signal to each member which matches selector, as
+	   SignalMember does, and returns the names of the members it signaled, sorted.
+	   It returns ErrGroupStopped once the group has exited.
+	*/
+	SignalMembers(selector Selector, signal os.Signal) ([]string, error)
+
+	/*
+	   Snapshot describes each of the group's running members, including their
+	   labels, in the order they were started.  Members which are being replaced
+	   or removed are included until they exit.  Snapshot returns nil once the
+	   group has exited.
+	*/
+	Snapshot() []MemberStatus
+}
+
+/*
+ErrGroupStopped is returned by operations on a dynamic group which has been
+signaled, or has exited.
+*/
+var ErrGroupStopped = errors.New("group has been stopped")
+
+/*
+ErrReplacementExited is returned by ReplaceMember when the new member exits
+cleanly before it is ready.
+*/
+var ErrReplacementExited = errors.New("replacement member exited before it was ready")
+
+/*
+ErrMemberNotFound is returned by operations on a named member which is not
+running in the group, or is not in the list of Members.
+*/
+type ErrMemberNotFound struct {
+	Name string
+}
+
+func (e ErrMemberNotFound) Error() string {
+	return fmt.Sprintf("member not found: %s", e.Name)
+}
+
+/*
+ErrMemberBusy is returned by RemoveMember and ReplaceMember when the named
+member is already being replaced.
+*/
+type ErrMemberBusy struct {
+	Name string
+}
+
+func (e ErrMemberBusy) Error() string {
+	return fmt.Sprintf("member is already being replaced: %s", e.Name)
 }
 
 type memberRequest struct {
@@ -57,28 +169,62 @@ type memberRequest struct {
 	Response chan ifrit.Process
 }
 
+type removeRequest struct {
+	Name     string
+	Response chan error
+}
+
+type signalRequest struct {
+	Name     string
+	Signal   os.Signal
+	Response chan error
+}
+
+type replaceRequest struct {
+	Member   Member
+	Response chan error
+}
+
 /*
 dynamicClient implements DynamicClient.
 */
 type dynamicClient struct {
-	insertChannel       chan Member
-	getMemberChannel    chan memberRequest
-	completeNotifier    chan struct{}
-	closeNotifier       chan struct{}
-	closeOnce           *sync.Once
-	entranceBroadcaster *entranceEventBroadcaster
-	exitBroadcaster     *exitEventBroadcaster
+	insertChannel         chan Member
+	getMemberChannel      chan memberRequest
+	removeChannel         chan removeRequest
+	replaceChannel        chan replaceRequest
+	signalChannel         chan signalRequest
+	signalMembersChannel  chan signalMembersRequest
+	pauseChannel          chan pauseRequest
+	snapshotChannel       chan chan []MemberStatus
+	eventsChannel         chan eventsRequest
+	completeNotifier      chan struct{}
+	closeNotifier         chan struct{}
+	closeOnce             *sync.Once
+	entranceBroadcaster   *entranceEventBroadcaster
+	exitBroadcaster       *exitEventBroadcaster
+	crashLoopBroadcaster  *crashLoopEventBroadcaster
+	membershipBroadcaster *membershipEventBroadcaster
 }
 
 func newClient(bufferSize int) dynamicClient {
 	return dynamicClient{
-		insertChannel:       make(chan Member),
-		getMemberChannel:    make(chan memberRequest),
-		completeNotifier:    make(chan struct{}),
-		closeNotifier:       make(chan struct{}),
-		closeOnce:           new(sync.Once),
-		entranceBroadcaster: newEntranceEventBroadcaster(bufferSize),
-		exitBroadcaster:     newExitEventBroadcaster(bufferSize),
+		insertChannel:         make(chan Member),
+		getMemberChannel:      make(chan memberRequest),
+		removeChannel:         make(chan removeRequest),
+		replaceChannel:        make(chan replaceRequest),
+		signalChannel:         make(chan signalRequest),
+		signalMembersChannel:  make(chan signalMembersRequest),
+		pauseChannel:          make(chan pauseRequest),
+		snapshotChannel:       make(chan chan []MemberStatus),
+		eventsChannel:         make(chan eventsRequest),
+		completeNotifier:      make(chan struct{}),
+		closeNotifier:         make(chan struct{}),
+		closeOnce:             new(sync.Once),
+		entranceBroadcaster:   newEntranceEventBroadcaster(bufferSize),
+		exitBroadcaster:       newExitEventBroadcaster(bufferSize),
+		crashLoopBroadcaster:  newCrashLoopEventBroadcaster(bufferSize),
+		membershipBroadcaster: newMembershipEventBroadcaster(bufferSize),
 	}
 }
 
@@ -103,6 +249,142 @@ func (c dynamicClient) memberRequests() chan memberRequest {
 	return c.getMemberChannel
 }
 
+func (c dynamicClient) RemoveMember(name string) error {
+	req := removeRequest{
+		Name:     name,
+		Response: make(chan error, 1),
+	}
+	select {
+	case c.removeChannel <- req:
+		return <-req.Response
+	case <-c.completeNotifier:
+		return ErrGroupStopped
+	}
+}
+
+func (c dynamicClient) removeRequests() chan removeRequest {
+	return c.removeChannel
+}
+
+func (c dynamicClient) ReplaceMember(member Member) error {
+	req := replaceRequest{
+		Member:   member,
+		Response: make(chan error, 1),
+	}
+	select {
+	case c.replaceChannel <- req:
+		return <-req.Response
+	case <-c.completeNotifier:
+		return ErrGroupStopped
+	}
+}
+
+func (c dynamicClient) replaceRequests() chan replaceRequest {
+	return c.replaceChannel
+}
+
+func (c dynamicClient) SignalMember(name string, signal os.Signal) error {
+	req := signalRequest{
+		Name:     name,
+		Signal:   signal,
+		Response: make(chan error, 1),
+	}
+	select {
+	case c.signalChannel <- req:
+		return <-req.Response
+	case <-c.completeNotifier:
+		return ErrGroupStopped
+	}
+}
+
+func (c dynamicClient) signalRequests() chan signalRequest {
+	return c.signalChannel
+}
+
+func (c dynamicClient) SignalMembers(selector Selector, signal os.Signal) ([]string, error) {
+	req := signalMembersRequest{
+		Selector: selector,
+		Signal:   signal,
+		Response: make(chan []string, 1),
+	}
+	select {
+	case c.signalMembersChannel <- req:
+		return <-req.Response, nil
+	case <-c.completeNotifier:
+		return nil, ErrGroupStopped
+	}
+}
+
+func (c dynamicClient) signalMembersRequests() chan signalMembersRequest {
+	return c.signalMembersChannel
+}
+
+func (c dynamicClient) Snapshot() []MemberStatus {
+	response := make(chan []MemberStatus, 1)
+	select {
+	case c.snapshotChannel <- response:
+		return <-response
+	case <-c.completeNotifier:
+		return nil
+	}
+}
+
+func (c dynamicClient) snapshotRequests() chan chan []MemberStatus {
+	return c.snapshotChannel
+}
+
+func (c dynamicClient) Events(replay bool) <-chan MembershipEvent {
+	req := eventsRequest{
+		Replay:   replay,
+		Response: make(chan (<-chan MembershipEvent), 1),
+	}
+	select {
+	case c.eventsChannel <- req:
+		return <-req.Response
+	case <-c.completeNotifier:
+		return c.membershipBroadcaster.Attach(nil)
+	}
+}
+
+func (c dynamicClient) eventsRequests() chan eventsRequest {
+	return c.eventsChannel
+}
+
+func (c dynamicClient) broadcastMembership(kind MembershipEventKind, member Member, process ifrit.Process, err error) {
+	c.membershipBroadcaster.Broadcast(MembershipEvent{
+		Kind:    kind,
+		Member:  member,
+		Process: process,
+		Err:     err,
+		Time:    time.Now(),
+	})
+}
+
+func (c dynamicClient) Pause() error {
+	return c.pause(true)
+}
+
+func (c dynamicClient) Resume() error {
+	return c.pause(false)
+}
+
+func (c dynamicClient) pause(pause bool) error {
+	req := pauseRequest{
+		Pause:    pause,
+		Response: make(chan error, 1),
+	}
+	select {
+	case c.pauseChannel <- req:
+		return <-req.Response
+	case <-c.completeNotifier:
+		return ErrGroupStopped
+	}
+}
+
+func (c dynamicClient) pauseRequests() chan pauseRequest {
+	return c.pauseChannel
+}
+
 func (c dynamicClient) Inserter() chan<- Member {
 	return c.insertChannel
 }
@@ -135,9 +417,19 @@ func (c dynamicClient) closeExitBroadcaster() {
 	c.exitBroadcaster.Close()
 }
 
+func (c dynamicClient) CrashLoopListener() <-chan CrashLoopEvent {
+	return c.crashLoopBroadcaster.Attach()
+}
+
+func (c dynamicClient) broadcastCrashLoop(event CrashLoopEvent) {
+	c.crashLoopBroadcaster.Broadcast(event)
+}
+
 func (c dynamicClient) closeBroadcasters() error {
 	c.entranceBroadcaster.Close()
 	c.exitBroadcaster.Close()
+	c.crashLoopBroadcaster.Close()
+	c.membershipBroadcaster.Close()
 	close(c.completeNotifier)
 	return nil
 }
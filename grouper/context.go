@@ -0,0 +1,58 @@
+package grouper
+
+import (
+	"context"
+	"os"
+)
+
+/*
+WithContext ties an ordered, parallel or dynamic group to ctx: once ctx is
+done, the group behaves as though it had been sent its termination signal, or
+os.Interrupt if it has none.  Signals sent to the group are still delivered as
+usual.  Use it to drive a group's shutdown from a context-based framework,
+without forwarding signals by hand.
+*/
+func WithContext(ctx context.Context) Option {
+	return func(o *groupOptions) {
+		o.ctx = ctx
+	}
+}
+
+/*
+contextSignals returns a channel which receives signals, and then signal once
+the group's context is done.  The returned function must be called once the
+group has exited.
+*/
+func (o groupOptions) contextSignals(signals <-chan os.Signal, signal os.Signal) (<-chan os.Signal, func()) {
+	if o.ctx == nil {
+		return signals, func() {}
+	}
+	if signal == nil {
+		signal = os.Interrupt
+	}
+
+	merged := make(chan os.Signal)
+	done := make(chan struct{})
+	go func() {
+		ctxDone := o.ctx.Done()
+		for {
+			var received os.Signal
+			select {
+			case received = <-signals:
+			case <-ctxDone:
+				ctxDone = nil
+				received = signal
+			case <-done:
+				return
+			}
+
+			select {
+			case merged <- received:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return merged, func() { close(done) }
+}
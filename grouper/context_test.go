@@ -0,0 +1,97 @@
+package grouper_test
+
+import (
+	"context"
+	"os"
+	"syscall"
+
+	"github.com/tedsuo/ifrit"
+	"github.com/tedsuo/ifrit/fake_runner"
+	"github.com/tedsuo/ifrit/ginkgomon"
+	"github.com/tedsuo/ifrit/grouper"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("WithContext", func() {
+	var (
+		ctx    context.Context
+		cancel context.CancelFunc
+
+		groupProcess ifrit.Process
+		childRunner1 *fake_runner.TestRunner
+		childRunner2 *fake_runner.TestRunner
+		members      grouper.Members
+	)
+
+	BeforeEach(func() {
+		ctx, cancel = context.WithCancel(context.Background())
+		childRunner1 = fake_runner.NewTestRunner()
+		childRunner2 = fake_runner.NewTestRunner()
+		members = grouper.Members{
+			{Name: "child1", Runner: childRunner1},
+			{Name: "child2", Runner: childRunner2},
+		}
+	})
+
+	AfterEach(func() {
+		cancel()
+		childRunner1.EnsureExit()
+		childRunner2.EnsureExit()
+		ginkgomon.Kill(groupProcess)
+	})
+
+	start := func(runner ifrit.Runner) (<-chan os.Signal, <-chan os.Signal) {
+		groupProcess = ifrit.Background(runner)
+		signal1 := childRunner1.WaitForCall()
+		childRunner1.TriggerReady()
+		signal2 := childRunner2.WaitForCall()
+		childRunner2.TriggerReady()
+		Eventually(groupProcess.Ready()).Should(BeClosed())
+		return signal1, signal2
+	}
+
+	It("stops an ordered group with its termination signal once the context is done", func() {
+		signal1, signal2 := start(grouper.NewOrdered(syscall.SIGTERM, members, grouper.WithContext(ctx)))
+
+		cancel()
+		Eventually(signal2).Should(Receive(Equal(syscall.SIGTERM)))
+		childRunner2.TriggerExit(nil)
+		Eventually(signal1).Should(Receive(Equal(syscall.SIGTERM)))
+		childRunner1.TriggerExit(nil)
+
+		Eventually(groupProcess.Wait()).Should(Receive(BeNil()))
+	})
+
+	It("stops a parallel group without a termination signal with os.Interrupt", func() {
+		signal1, signal2 := start(grouper.NewParallel(nil, members, grouper.WithContext(ctx)))
+
+		cancel()
+		Eventually(signal1).Should(Receive(Equal(os.Interrupt)))
+		Eventually(signal2).Should(Receive(Equal(os.Interrupt)))
+		childRunner1.TriggerExit(nil)
+		childRunner2.TriggerExit(nil)
+
+		Eventually(groupProcess.Wait()).Should(Receive(BeNil()))
+	})
+
+	It("still delivers signals sent to the group", func() {
+		signal1, _ := start(grouper.NewParallel(os.Interrupt, members, grouper.WithContext(ctx)))
+
+		groupProcess.Signal(syscall.SIGHUP)
+		Eventually(signal1).Should(Receive(Equal(syscall.SIGHUP)))
+	})
+
+	It("stops a dynamic group once the context is done", func() {
+		pool := grouper.NewDynamic(nil, 1, 1, grouper.WithContext(ctx))
+		groupProcess = ifrit.Invoke(pool)
+		Eventually(pool.Client().Inserter()).Should(BeSent(members[0]))
+		signal1 := childRunner1.WaitForCall()
+
+		cancel()
+		Eventually(signal1).Should(Receive(Equal(os.Interrupt)))
+		childRunner1.TriggerExit(nil)
+		Eventually(groupProcess.Wait()).Should(Receive())
+	})
+})
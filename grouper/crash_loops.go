@@ -0,0 +1,157 @@
+package grouper
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+/*
+WithCrashLoopLimit enables crash-loop detection in a dynamic group.  A member
+which exits more than maxExits times within window is in a crash loop: the
+group emits a CrashLoopEvent, and no longer admits members with its name, so
+that a client which re-inserts members as they exit does not churn forever.
+
+Members which are removed or replaced are not counted as exiting.  Ordered and
+parallel groups ignore it.
+*/
+func WithCrashLoopLimit(maxExits int, window time.Duration) Option {
+	return func(o *groupOptions) {
+		o.crashLoopExits = maxExits
+		o.crashLoopWindow = window
+	}
+}
+
+/*
+A CrashLoopEvent occurs when a member of a dynamic group is found to be in a
+crash loop.  Exits are the member's exits within the window, oldest first; the
+last of them is the exit which tripped the limit.
+*/
+type CrashLoopEvent struct {
+	Member Member
+	Exits  []ExitEvent
+	Window time.Duration
+}
+
+/*
+ErrCrashLoop is returned by ReplaceMember when the named member is in a crash
+loop.
+*/
+type ErrCrashLoop struct {
+	Name string
+}
+
+func (e ErrCrashLoop) Error() string {
+	return fmt.Sprintf("member is in a crash loop: %s", e.Name)
+}
+
+/*
+crashLoops counts the recent exits of each member of a dynamic group.
+*/
+type crashLoops struct {
+	maxExits int
+	window   time.Duration
+	exits    map[string][]ExitEvent
+	looping  map[string]struct{}
+}
+
+func newCrashLoops(options groupOptions) *crashLoops {
+	return &crashLoops{
+		maxExits: options.crashLoopExits,
+		window:   options.crashLoopWindow,
+		exits:    map[string][]ExitEvent{},
+		looping:  map[string]struct{}{},
+	}
+}
+
+/*
+Exited records an exit, and reports whether it puts the member into a crash
+loop.
+*/
+func (c *crashLoops) Exited(exit ExitEvent) (CrashLoopEvent, bool) {
+	if c.maxExits <= 0 || c.Looping(exit.Member.Name) {
+		return CrashLoopEvent{}, false
+	}
+
+	name := exit.Member.Name
+	recent := make([]ExitEvent, 0, len(c.exits[name])+1)
+	for _, previous := range c.exits[name] {
+		if exit.ExitTime.Sub(previous.ExitTime) <= c.window {
+			recent = append(recent, previous)
+		}
+	}
+	recent = append(recent, exit)
+
+	if len(recent) <= c.maxExits {
+		c.exits[name] = recent
+		return CrashLoopEvent{}, false
+	}
+
+	delete(c.exits, name)
+	c.looping[name] = struct{}{}
+	return CrashLoopEvent{Member: exit.Member, Exits: recent, Window: c.window}, true
+}
+
+func (c *crashLoops) Looping(name string) bool {
+	_, found := c.looping[name]
+	return found
+}
+
+type crashLoopEventChannel chan CrashLoopEvent
+
+func newCrashLoopEventChannel(bufferSize int) crashLoopEventChannel {
+	return make(crashLoopEventChannel, bufferSize)
+}
+
+type crashLoopEventBroadcaster struct {
+	channels   []crashLoopEventChannel
+	buffer     slidingBuffer
+	bufferSize int
+	lock       *sync.Mutex
+}
+
+func newCrashLoopEventBroadcaster(bufferSize int) *crashLoopEventBroadcaster {
+	return &crashLoopEventBroadcaster{
+		channels:   make([]crashLoopEventChannel, 0),
+		buffer:     newSlidingBuffer(bufferSize),
+		bufferSize: bufferSize,
+		lock:       new(sync.Mutex),
+	}
+}
+
+func (b *crashLoopEventBroadcaster) Attach() crashLoopEventChannel {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	channel := newCrashLoopEventChannel(b.bufferSize)
+	b.buffer.Range(func(event interface{}) {
+		channel <- event.(CrashLoopEvent)
+	})
+	if b.channels != nil {
+		b.channels = append(b.channels, channel)
+	} else {
+		close(channel)
+	}
+	return channel
+}
+
+func (b *crashLoopEventBroadcaster) Broadcast(crashLoop CrashLoopEvent) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.buffer.Append(crashLoop)
+
+	for _, crashLoopChan := range b.channels {
+		crashLoopChan <- crashLoop
+	}
+}
+
+func (b *crashLoopEventBroadcaster) Close() {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	for _, channel := range b.channels {
+		close(channel)
+	}
+	b.channels = nil
+}
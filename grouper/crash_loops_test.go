@@ -0,0 +1,98 @@
+package grouper_test
+
+import (
+	"errors"
+	"os"
+	"time"
+
+	"github.com/tedsuo/ifrit"
+	"github.com/tedsuo/ifrit/fake_runner"
+	"github.com/tedsuo/ifrit/grouper"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Crash loop detection", func() {
+	var (
+		client      grouper.DynamicClient
+		poolProcess ifrit.Process
+		exits       <-chan grouper.ExitEvent
+		window      time.Duration
+
+		Δ time.Duration = 10 * time.Millisecond
+	)
+
+	BeforeEach(func() {
+		window = time.Hour
+	})
+
+	JustBeforeEach(func() {
+		pool := grouper.NewDynamic(nil, 2, 10, grouper.WithCrashLoopLimit(2, window))
+		client = pool.Client()
+		exits = client.ExitListener()
+		poolProcess = ifrit.Invoke(pool)
+	})
+
+	AfterEach(func() {
+		poolProcess.Signal(os.Kill)
+		Eventually(poolProcess.Wait()).Should(Receive())
+	})
+
+	crash := func() {
+		runner := fake_runner.NewTestRunner()
+		Eventually(client.Inserter()).Should(BeSent(grouper.Member{Name: "flaky", Runner: runner}))
+		runner.WaitForCall()
+		runner.TriggerExit(errors.New("Fail"))
+		Eventually(exits).Should(Receive())
+	}
+
+	It("emits a crash loop event, and stops admitting the member, once it exits too often", func() {
+		crashLoops := client.CrashLoopListener()
+
+		crash()
+		crash()
+		Consistently(crashLoops, Δ).ShouldNot(Receive())
+		crash()
+
+		var crashLoop grouper.CrashLoopEvent
+		Eventually(crashLoops).Should(Receive(&crashLoop))
+		Ω(crashLoop.Member.Name).Should(Equal("flaky"))
+		Ω(crashLoop.Exits).Should(HaveLen(3))
+		Ω(crashLoop.Window).Should(Equal(time.Hour))
+
+		runner := fake_runner.NewTestRunner()
+		Eventually(client.Inserter()).Should(BeSent(grouper.Member{Name: "flaky", Runner: runner}))
+		Consistently(runner.RunCallCount, Δ).Should(Equal(0))
+		_, found := client.Get("flaky")
+		Ω(found).Should(BeFalse())
+	})
+
+	It("still admits other members", func() {
+		crash()
+		crash()
+		crash()
+
+		runner := fake_runner.NewTestRunner()
+		Eventually(client.Inserter()).Should(BeSent(grouper.Member{Name: "steady", Runner: runner}))
+		runner.WaitForCall()
+		runner.TriggerExit(nil)
+	})
+
+	Context("when the exits are spread out beyond the window", func() {
+		BeforeEach(func() {
+			window = 20 * time.Millisecond
+		})
+
+		It("does not detect a crash loop", func() {
+			crashLoops := client.CrashLoopListener()
+
+			crash()
+			crash()
+			time.Sleep(2 * window)
+			crash()
+
+			Consistently(crashLoops, Δ).ShouldNot(Receive())
+		})
+	})
+})
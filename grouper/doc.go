@@ -4,12 +4,32 @@ which are then organized into an execution tree.  If you have modeled your subsy
 as ifrit runners, startup and shutdown of your entire application can now
 be controlled.
 
-Grouper provides three strategies for system startup: two static group
+Grouper provides five strategies for system startup: four static group
 strategies, and one DynamicGroup.  Each static group strategy takes a
 list of members, and starts the members in the following manner:
 
   - Parallel: all processes are started simultaneously.
   - Ordered:  the next process is started when the previous is ready.
+  - Graph:    each process is started when the processes it depends upon are ready.
+  - Stages:   each stage of processes is started when the previous stage is ready.
+
+Ordered and parallel groups accept Options, such as WithReadyTimeout, which
+bounds how long the group waits for each member to become ready,
+WithStartConcurrency, which bounds how many members a parallel group starts at
+once, WithReadyQuorum and WithFailureTolerance, which let a parallel group of
+replicated workers become ready and keep running without all of them, and
+WithShutdownOrder, which changes the order in which members are
+stopped.  In tests, WithScheduler lets a Scheduler, such as a StepScheduler,
+step each member's transitions in a chosen order.  WithMemberOptions configures
+how any group runs one of its members, by name: whether it is optional, how long
+it has to shut down, and, for a Supervisor, how it is restarted.
+
+A Supervisor starts its members like a parallel group, but restarts members
+which exit according to their MemberOptions' RestartPolicy, instead of shutting
+down.  A
+Supervisor created with NewSupervisedGraph starts its members in dependency
+order, and can restart the dependents of a member along with it.  A
+Supervisor's Lazy members are only started once they are demanded.
 
 The DynamicGroup allows up to N processes to be run concurrently. The dynamic
 group runs indefinitely until it is closed or signaled. The DynamicGroup provides
@@ -20,6 +40,8 @@ following properties:
   - A dynamic group can be manually closed via it's client.
   - A dynamic group is automatically closed once it is signaled.
   - Once a dynamic group is closed, it acts like a static group.
+  - With WithCrashLoopLimit, a member which exits too often is no longer admitted.
+  - A dynamic group can be paused, to admit no new members while it is maintained.
 
 Groups can optionally be configured with a termination signal, and all groups
 have the same signaling and shutdown properties:
@@ -27,5 +49,8 @@ have the same signaling and shutdown properties:
   - The group propogates all received signals to all running members.
   - If a member exits before being signaled, the group propogates the
     termination signal.  A nil termination signal is not propogated.
+
+Static groups and Supervisors can also be described by a JSON Manifest, and
+built with LoadManifest from a Registry of runner factories.
 */
 package grouper
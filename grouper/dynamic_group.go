@@ -3,6 +3,7 @@ package grouper
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/tedsuo/ifrit"
 )
@@ -23,6 +24,7 @@ type dynamicGroup struct {
 	client            dynamicClient
 	terminationSignal os.Signal
 	poolSize          int
+	options           groupOptions
 }
 
 /*
@@ -39,12 +41,16 @@ the total number of members is known in advance.
 The signal argument sets the termination signal.  If a member exits before
 being signaled, the group propogates the termination signal.  A nil termination
 signal is not propogated.
+
+Dynamic groups accept the WithCrashLoopLimit, WithPanicRecovery and WithContext
+options.
 */
-func NewDynamic(terminationSignal os.Signal, maxCapacity int, eventBufferSize int) DynamicGroup {
+func NewDynamic(terminationSignal os.Signal, maxCapacity int, eventBufferSize int, opts ...Option) DynamicGroup {
 	return &dynamicGroup{
 		client:            newClient(eventBufferSize),
 		poolSize:          maxCapacity,
 		terminationSignal: terminationSignal,
+		options:           newGroupOptions(ShutdownParallel, opts),
 	}
 }
 
@@ -53,26 +59,41 @@ func (p *dynamicGroup) Client() DynamicClient {
 }
 
 func (p *dynamicGroup) Run(signals <-chan os.Signal, ready chan<- struct{}) error {
-	processes := newProcessSet()
+	signals, stopContext := p.options.contextSignals(signals, p.terminationSignal)
+	defer stopContext()
+
+	processes := newProcessSet(p.options)
+	stops := newStopSignals(p.stopSignal())
+	crashLoops := newCrashLoops(p.options)
 	insertEvents := p.client.insertEventListener()
 	memberRequests := p.client.memberRequests()
+	snapshotRequests := p.client.snapshotRequests()
+	eventsRequests := p.client.eventsRequests()
+	removeRequests := p.client.removeRequests()
+	replaceRequests := p.client.replaceRequests()
+	signalRequests := p.client.signalRequests()
+	signalMembersRequests := p.client.signalMembersRequests()
+	pauseRequests := p.client.pauseRequests()
 	closeNotifier := p.client.CloseNotifier()
 	entranceEvents := make(entranceEventChannel)
-	exitEvents := make(exitEventChannel)
+	exitEvents := make(chan processExit)
+	replacements := make(chan replacement)
 
 	invoking := 0
+	paused := false
 	close(ready)
 
 	for {
 		select {
 		case shutdown := <-signals:
+			stops.add(shutdown)
 			processes.Signal(shutdown)
 			p.client.Close()
 
 		case <-closeNotifier:
 			closeNotifier = nil
 			insertEvents = nil
-			if processes.Length() == 0 {
+			if processes.Empty() {
 				return p.client.closeBroadcasters()
 			}
 			if invoking == 0 {
@@ -86,6 +107,118 @@ func (p *dynamicGroup) Run(signals <-chan os.Signal, ready chan<- struct{}) erro
 			}
 			close(memberRequest.Response)
 
+		case snapshotRequest := <-snapshotRequests:
+			snapshotRequest <- processes.Snapshot()
+
+		case eventsRequest := <-eventsRequests:
+			var replay []MembershipEvent
+			if eventsRequest.Replay {
+				replay = processes.Replay()
+			}
+			eventsRequest.Response <- p.client.membershipBroadcaster.Attach(replay)
+
+		case removeRequest := <-removeRequests:
+			err := processes.checkMember(removeRequest.Name)
+			if err != nil {
+				removeRequest.Response <- err
+				break
+			}
+
+			processes.Retire(removeRequest.Name, p.stopSignal(), removeRequest.Response)
+
+		case signalRequest := <-signalRequests:
+			process, ok := processes.Get(signalRequest.Name)
+			if !ok {
+				signalRequest.Response <- ErrMemberNotFound{Name: signalRequest.Name}
+				break
+			}
+
+			process.Signal(signalRequest.Signal)
+			signalRequest.Response <- nil
+
+		case pauseRequest := <-pauseRequests:
+			if processes.Signaled() {
+				pauseRequest.Response <- ErrGroupStopped
+				break
+			}
+
+			if pauseRequest.Pause != paused {
+				paused = pauseRequest.Pause
+				processes.Pause(paused)
+				if paused {
+					insertEvents = nil
+				} else if closeNotifier != nil && processes.Length() < p.poolSize {
+					insertEvents = p.client.insertEventListener()
+				}
+			}
+			pauseRequest.Response <- nil
+
+		case signalMembersRequest := <-signalMembersRequests:
+			signalMembersRequest.Response <- processes.SignalMatching(signalMembersRequest.Selector, signalMembersRequest.Signal)
+
+		case replaceRequest := <-replaceRequests:
+			err := processes.checkMember(replaceRequest.Member.Name)
+			if err == nil && crashLoops.Looping(replaceRequest.Member.Name) {
+				err = ErrCrashLoop{Name: replaceRequest.Member.Name}
+			}
+			if err == nil && paused {
+				err = ErrGroupPaused
+			}
+			if err != nil {
+				replaceRequest.Response <- err
+				break
+			}
+
+			startTime := time.Now()
+			process := ifrit.Background(p.options.runner(replaceRequest.Member, stops))
+			processes.AddIncoming(replaceRequest.Member, process, startTime)
+			p.client.broadcastMembership(MembershipEntered, replaceRequest.Member, process, nil)
+
+			invoking++
+
+			go waitForReplacement(replaceRequest, process, startTime, replacements, exitEvents)
+
+		case replacement := <-replacements:
+			invoking--
+			member := replacement.request.Member
+			processes.RemoveIncoming(member.Name)
+
+			if replacement.ready {
+				p.client.broadcastEntrance(EntranceEvent{
+					Member:  member,
+					Process: replacement.process,
+				})
+
+				processes.Ready(replacement.process)
+				p.client.broadcastMembership(MembershipReady, member, replacement.process, nil)
+				if !processes.Swap(member.Name, replacement.process, p.stopSignal(), replacement.request.Response) {
+					replacement.request.Response <- nil
+				}
+				if processes.Length() >= p.poolSize {
+					insertEvents = nil
+				}
+			} else {
+				processes.Exited(replacement.process)
+				p.client.broadcastExit(replacement.exit)
+				p.client.broadcastMembership(MembershipExited, member, replacement.process, replacement.exit.Err)
+				p.detectCrashLoop(crashLoops, replacement.exit)
+
+				err := replacement.exit.Err
+				if err == nil {
+					err = ErrReplacementExited
+				}
+				replacement.request.Response <- err
+			}
+
+			if closeNotifier == nil && invoking == 0 {
+				p.client.closeEntranceBroadcaster()
+				entranceEvents = nil
+			}
+
+			if processes.Complete() {
+				return p.client.closeBroadcasters()
+			}
+
 		case newMember, ok := <-insertEvents:
 			if !ok {
 				p.client.Close()
@@ -93,19 +226,28 @@ func (p *dynamicGroup) Run(signals <-chan os.Signal, ready chan<- struct{}) erro
 				break
 			}
 
-			process := ifrit.Background(newMember)
-			processes.Add(newMember.Name, process)
+			if crashLoops.Looping(newMember.Name) {
+				break
+			}
 
-			if processes.Length() == p.poolSize {
+			startTime := time.Now()
+			process := ifrit.Background(p.options.runner(newMember, stops))
+			processes.Add(newMember, process, startTime)
+			p.client.broadcastMembership(MembershipEntered, newMember, process, nil)
+
+			if processes.Length() >= p.poolSize {
 				insertEvents = nil
 			}
 
 			invoking++
 
-			go waitForEvents(newMember, process, entranceEvents, exitEvents)
+			go waitForEvents(newMember, process, startTime, entranceEvents, exitEvents)
 
 		case entranceEvent := <-entranceEvents:
 			invoking--
+			if processes.Ready(entranceEvent.Process) {
+				p.client.broadcastMembership(MembershipReady, entranceEvent.Member, entranceEvent.Process, nil)
+			}
 			p.client.broadcastEntrance(entranceEvent)
 
 			if closeNotifier == nil && invoking == 0 {
@@ -113,32 +255,71 @@ func (p *dynamicGroup) Run(signals <-chan os.Signal, ready chan<- struct{}) erro
 				entranceEvents = nil
 			}
 
-		case exitEvent := <-exitEvents:
-			processes.Remove(exitEvent.Member.Name)
-			p.client.broadcastExit(exitEvent)
-
-			if !processes.Signaled() && p.terminationSignal != nil {
-				processes.Signal(p.terminationSignal)
-				p.client.Close()
-				insertEvents = nil
+		case exit := <-exitEvents:
+			processes.Exited(exit.process)
+			p.client.broadcastExit(exit.ExitEvent)
+			p.client.broadcastMembership(MembershipExited, exit.Member, exit.process, exit.Err)
+
+			response, retired := processes.Retired(exit.process)
+			if retired {
+				response <- exit.Err
+			} else {
+				processes.Remove(exit.Member.Name, exit.process)
+				p.detectCrashLoop(crashLoops, exit.ExitEvent)
+
+				if !processes.Signaled() && p.terminationSignal != nil && !p.options.member(exit.Member.Name).Optional {
+					processes.Signal(p.terminationSignal)
+					p.client.Close()
+					insertEvents = nil
+				}
 			}
 
-			if processes.Complete() || (processes.Length() == 0 && insertEvents == nil) {
+			if processes.Complete() || (processes.Empty() && closeNotifier == nil) {
 				return p.client.closeBroadcasters()
 			}
 
-			if !processes.Signaled() && closeNotifier != nil {
+			if !processes.Signaled() && closeNotifier != nil && !paused && processes.Length() < p.poolSize {
 				insertEvents = p.client.insertEventListener()
 			}
 		}
 	}
 }
 
+func (p *dynamicGroup) detectCrashLoop(crashLoops *crashLoops, exit ExitEvent) {
+	crashLoop, looping := crashLoops.Exited(exit)
+	if looping {
+		p.client.broadcastCrashLoop(crashLoop)
+	}
+}
+
+/*
+stopSignal is sent to members which are removed or replaced.
+*/
+func (p *dynamicGroup) stopSignal() os.Signal {
+	if p.terminationSignal != nil {
+		return p.terminationSignal
+	}
+	return os.Interrupt
+}
+
+type processExit struct {
+	ExitEvent
+	process ifrit.Process
+}
+
+type replacement struct {
+	request replaceRequest
+	process ifrit.Process
+	ready   bool
+	exit    ExitEvent
+}
+
 func waitForEvents(
 	member Member,
 	process ifrit.Process,
+	startTime time.Time,
 	entrance entranceEventChannel,
-	exit exitEventChannel,
+	exit chan<- processExit,
 ) {
 	select {
 	case <-process.Ready():
@@ -147,9 +328,9 @@ func waitForEvents(
 			Process: process,
 		}
 
-		exit <- ExitEvent{
-			Member: member,
-			Err:    <-process.Wait(),
+		exit <- processExit{
+			ExitEvent: newExitEvent(member, startTime, <-process.Wait()),
+			process:   process,
 		}
 
 	case err := <-process.Wait():
@@ -158,21 +339,60 @@ func waitForEvents(
 			Process: process,
 		}
 
-		exit <- ExitEvent{
-			Member: member,
-			Err:    err,
+		exit <- processExit{
+			ExitEvent: newExitEvent(member, startTime, err),
+			process:   process,
+		}
+	}
+}
+
+func waitForReplacement(
+	request replaceRequest,
+	process ifrit.Process,
+	startTime time.Time,
+	replacements chan<- replacement,
+	exit chan<- processExit,
+) {
+	select {
+	case <-process.Ready():
+		replacements <- replacement{
+			request: request,
+			process: process,
+			ready:   true,
+		}
+
+		exit <- processExit{
+			ExitEvent: newExitEvent(request.Member, startTime, <-process.Wait()),
+			process:   process,
+		}
+
+	case err := <-process.Wait():
+		replacements <- replacement{
+			request: request,
+			process: process,
+			exit:    newExitEvent(request.Member, startTime, err),
 		}
 	}
 }
 
 type processSet struct {
 	processes map[string]ifrit.Process
+	incoming  map[string]ifrit.Process
+	retiring  map[ifrit.Process]chan error
+	statuses  map[ifrit.Process]*MemberStatus
+	members   map[ifrit.Process]Member
+	options   groupOptions
 	shutdown  os.Signal
 }
 
-func newProcessSet() *processSet {
+func newProcessSet(options groupOptions) *processSet {
 	return &processSet{
+		options:   options,
 		processes: map[string]ifrit.Process{},
+		incoming:  map[string]ifrit.Process{},
+		retiring:  map[ifrit.Process]chan error{},
+		statuses:  map[ifrit.Process]*MemberStatus{},
+		members:   map[ifrit.Process]Member{},
 	}
 }
 
@@ -186,14 +406,31 @@ func (g *processSet) Signal(signal os.Signal) {
 	for _, p := range g.processes {
 		p.Signal(signal)
 	}
+	for _, p := range g.incoming {
+		p.Signal(signal)
+	}
+	for p := range g.retiring {
+		p.Signal(signal)
+	}
+	for _, status := range g.statuses {
+		status.State = MemberStopping
+	}
 }
 
+/*
+Length counts the processes which occupy the group's capacity: its members, and
+the members which have been removed but have not yet exited.
+*/
 func (g *processSet) Length() int {
-	return len(g.processes)
+	return len(g.processes) + len(g.retiring)
+}
+
+func (g *processSet) Empty() bool {
+	return len(g.processes) == 0 && len(g.incoming) == 0 && len(g.retiring) == 0
 }
 
 func (g *processSet) Complete() bool {
-	return len(g.processes) == 0 && g.shutdown != nil
+	return g.Empty() && g.shutdown != nil
 }
 
 func (g *processSet) Get(name string) (ifrit.Process, bool) {
@@ -201,14 +438,120 @@ func (g *processSet) Get(name string) (ifrit.Process, bool) {
 	return p, ok
 }
 
-func (g *processSet) Add(name string, process ifrit.Process) {
+func (g *processSet) Add(member Member, process ifrit.Process, startTime time.Time) {
+	_, ok := g.processes[member.Name]
+	if ok {
+		panic(fmt.Errorf("member inserted twice: %#v", member.Name))
+	}
+	g.processes[member.Name] = process
+	g.started(member, process, startTime)
+}
+
+func (g *processSet) started(member Member, process ifrit.Process, startTime time.Time) {
+	state := MemberStarting
+	if g.Signaled() {
+		state = MemberStopping
+	}
+	g.statuses[process] = &MemberStatus{Name: member.Name, State: state, StartTime: startTime}
+	g.members[process] = member
+}
+
+/*
+Ready records that a process has become ready, and reports whether it has: a
+process which exits before it is ready never becomes ready.
+*/
+func (g *processSet) Ready(process ifrit.Process) bool {
+	if !isReady(process) {
+		return false
+	}
+	status, ok := g.statuses[process]
+	if ok && status.State == MemberStarting {
+		status.State = MemberReady
+	}
+	return ok
+}
+
+func (g *processSet) Exited(process ifrit.Process) {
+	delete(g.statuses, process)
+	delete(g.members, process)
+}
+
+/*
+Snapshot describes each running process, in the order they were started.
+*/
+func (g *processSet) Snapshot() []MemberStatus {
+	now := time.Now()
+	statuses := make([]MemberStatus, 0, len(g.statuses))
+	for _, status := range g.statuses {
+		snapshot := newMemberStatus(status.Name, status.State, nil, status.StartTime, 0, now)
+		snapshot.Labels = g.options.member(status.Name).Labels
+		statuses = append(statuses, snapshot)
+	}
+	sortStatuses(statuses)
+	return statuses
+}
+
+/*
+Remove removes the named member, unless it has since been replaced by a
+process other than the one given.
+*/
+func (g *processSet) Remove(name string, process ifrit.Process) {
+	if g.processes[name] == process {
+		delete(g.processes, name)
+	}
+}
+
+func (g *processSet) checkMember(name string) error {
+	if g.Signaled() {
+		return ErrGroupStopped
+	}
+	if _, ok := g.processes[name]; !ok {
+		return ErrMemberNotFound{Name: name}
+	}
+	if _, ok := g.incoming[name]; ok {
+		return ErrMemberBusy{Name: name}
+	}
+	return nil
+}
+
+func (g *processSet) AddIncoming(member Member, process ifrit.Process, startTime time.Time) {
+	g.incoming[member.Name] = process
+	g.started(member, process, startTime)
+}
+
+func (g *processSet) RemoveIncoming(name string) {
+	delete(g.incoming, name)
+}
+
+/*
+Retire removes the named member and signals it.  Its exit error will be sent on
+response.
+*/
+func (g *processSet) Retire(name string, signal os.Signal, response chan error) {
+	p := g.processes[name]
+	delete(g.processes, name)
+	g.retiring[p] = response
+	g.statuses[p].State = MemberStopping
+	p.Signal(signal)
+}
+
+/*
+Swap runs process as the named member, and retires the process it replaces.  It
+returns false if there was no process to retire, because it has already exited.
+*/
+func (g *processSet) Swap(name string, process ifrit.Process, signal os.Signal, response chan error) bool {
 	_, ok := g.processes[name]
 	if ok {
-		panic(fmt.Errorf("member inserted twice: %#v", name))
+		g.Retire(name, signal, response)
 	}
 	g.processes[name] = process
+	return ok
 }
 
-func (g *processSet) Remove(name string) {
-	delete(g.processes, name)
+func (g *processSet) Retired(process ifrit.Process) (chan error, bool) {
+	response, ok := g.retiring[process]
+	if ok {
+		delete(g.retiring, process)
+	}
+	return response, ok
 }
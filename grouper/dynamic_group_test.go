@@ -1,6 +1,7 @@
 package grouper_test
 
 import (
+	"errors"
 	"os"
 	"syscall"
 	"time"
@@ -146,4 +147,262 @@ var _ = Describe("dynamicGroup", func() {
 			Consistently(exits).ShouldNot(Receive())
 		})
 	})
+
+	Describe("RemoveMember", func() {
+		var (
+			member1, member2 grouper.Member
+			signals1         <-chan os.Signal
+			signals2         <-chan os.Signal
+		)
+
+		BeforeEach(func() {
+			member1 = grouper.Member{Name: "child1", Runner: childRunner1}
+			member2 = grouper.Member{Name: "child2", Runner: childRunner2}
+
+			pool = grouper.NewDynamic(syscall.SIGTERM, 2, 2)
+			client = pool.Client()
+			poolProcess = ifrit.Invoke(pool)
+
+			insert := client.Inserter()
+			Eventually(insert).Should(BeSent(member1))
+			Eventually(insert).Should(BeSent(member2))
+			signals1 = childRunner1.WaitForCall()
+			signals2 = childRunner2.WaitForCall()
+		})
+
+		AfterEach(func() {
+			poolProcess.Signal(os.Kill)
+			childRunner1.EnsureExit()
+			childRunner2.EnsureExit()
+			childRunner3.EnsureExit()
+			Eventually(poolProcess.Wait()).Should(Receive())
+		})
+
+		It("stops the member and returns its exit error, without stopping the group", func() {
+			errs := make(chan error)
+			go func() { errs <- client.RemoveMember("child1") }()
+
+			Eventually(signals1).Should(Receive(Equal(syscall.SIGTERM)))
+			Consistently(errs).ShouldNot(Receive())
+
+			childRunner1.TriggerExit(errors.New("Fail"))
+			Eventually(errs).Should(Receive(MatchError("Fail")))
+
+			Consistently(signals2).ShouldNot(Receive())
+			Consistently(poolProcess.Wait()).ShouldNot(Receive())
+
+			_, ok := client.Get("child1")
+			Ω(ok).Should(BeFalse())
+		})
+
+		It("makes room for new members once the member has exited", func() {
+			go client.RemoveMember("child1")
+			Eventually(signals1).Should(Receive())
+			childRunner1.TriggerExit(nil)
+
+			Eventually(client.Inserter()).Should(BeSent(grouper.Member{Name: "child3", Runner: childRunner3}))
+			childRunner3.WaitForCall()
+		})
+
+		It("returns ErrMemberNotFound for an unknown member", func() {
+			Ω(client.RemoveMember("blah")).Should(Equal(grouper.ErrMemberNotFound{Name: "blah"}))
+		})
+
+		It("returns ErrGroupStopped once the group has been signaled", func() {
+			poolProcess.Signal(syscall.SIGUSR2)
+			Eventually(signals1).Should(Receive(Equal(syscall.SIGUSR2)))
+			Ω(client.RemoveMember("child1")).Should(Equal(grouper.ErrGroupStopped))
+		})
+	})
+
+	Describe("SignalMember", func() {
+		var (
+			signals1 <-chan os.Signal
+			signals2 <-chan os.Signal
+		)
+
+		BeforeEach(func() {
+			pool = grouper.NewDynamic(syscall.SIGTERM, 2, 2)
+			client = pool.Client()
+			poolProcess = ifrit.Invoke(pool)
+
+			insert := client.Inserter()
+			Eventually(insert).Should(BeSent(grouper.Member{Name: "child1", Runner: childRunner1}))
+			Eventually(insert).Should(BeSent(grouper.Member{Name: "child2", Runner: childRunner2}))
+			signals1 = childRunner1.WaitForCall()
+			signals2 = childRunner2.WaitForCall()
+		})
+
+		AfterEach(func() {
+			poolProcess.Signal(os.Kill)
+			childRunner1.EnsureExit()
+			childRunner2.EnsureExit()
+			Eventually(poolProcess.Wait()).Should(Receive())
+		})
+
+		It("signals only the named member", func() {
+			Ω(client.SignalMember("child1", syscall.SIGHUP)).Should(Succeed())
+
+			Eventually(signals1).Should(Receive(Equal(syscall.SIGHUP)))
+			Consistently(signals2).ShouldNot(Receive())
+		})
+
+		It("returns ErrMemberNotFound for an unknown member", func() {
+			Ω(client.SignalMember("blah", syscall.SIGHUP)).Should(Equal(grouper.ErrMemberNotFound{Name: "blah"}))
+		})
+	})
+
+	Describe("ReplaceMember", func() {
+		var (
+			member1     grouper.Member
+			replacement grouper.Member
+			signals1    <-chan os.Signal
+			errs        chan error
+		)
+
+		BeforeEach(func() {
+			member1 = grouper.Member{Name: "child1", Runner: childRunner1}
+			replacement = grouper.Member{Name: "child1", Runner: childRunner2}
+
+			pool = grouper.NewDynamic(syscall.SIGTERM, 2, 2)
+			client = pool.Client()
+			poolProcess = ifrit.Invoke(pool)
+
+			Eventually(client.Inserter()).Should(BeSent(member1))
+			signals1 = childRunner1.WaitForCall()
+			childRunner1.TriggerReady()
+
+			replaced := make(chan error, 1)
+			go func() { replaced <- client.ReplaceMember(replacement) }()
+			errs = replaced
+			childRunner2.WaitForCall()
+		})
+
+		AfterEach(func() {
+			poolProcess.Signal(os.Kill)
+			childRunner1.EnsureExit()
+			childRunner2.EnsureExit()
+			childRunner3.EnsureExit()
+			Eventually(poolProcess.Wait()).Should(Receive())
+		})
+
+		It("stops the old member once the new member is ready", func() {
+			Consistently(signals1).ShouldNot(Receive())
+
+			entrances := client.EntranceListener()
+			childRunner2.TriggerReady()
+			Eventually(signals1).Should(Receive(Equal(syscall.SIGTERM)))
+			Eventually(entrances).Should(Receive(HaveField("Member", replacement)))
+
+			Consistently(errs).ShouldNot(Receive())
+			childRunner1.TriggerExit(nil)
+			Eventually(errs).Should(Receive(BeNil()))
+
+			Consistently(poolProcess.Wait()).ShouldNot(Receive())
+
+			signals2 := childRunner2.WaitForCall()
+			p, ok := client.Get("child1")
+			Ω(ok).Should(BeTrue())
+			p.Signal(syscall.SIGUSR2)
+			Eventually(signals2).Should(Receive(Equal(syscall.SIGUSR2)))
+		})
+
+		It("refuses to replace the member again while it is being replaced", func() {
+			Ω(client.ReplaceMember(replacement)).Should(Equal(grouper.ErrMemberBusy{Name: "child1"}))
+			Ω(client.RemoveMember("child1")).Should(Equal(grouper.ErrMemberBusy{Name: "child1"}))
+		})
+
+		Context("when the new member exits before it is ready", func() {
+			It("keeps the old member, and returns the new member's error", func() {
+				childRunner2.TriggerExit(errors.New("Fail"))
+				Eventually(errs).Should(Receive(MatchError("Fail")))
+
+				Consistently(signals1).ShouldNot(Receive())
+				Consistently(poolProcess.Wait()).ShouldNot(Receive())
+			})
+
+			It("returns ErrReplacementExited when it exits cleanly", func() {
+				childRunner2.TriggerExit(nil)
+				Eventually(errs).Should(Receive(Equal(grouper.ErrReplacementExited)))
+			})
+		})
+	})
+
+	Describe("when the group is full", func() {
+		var signals1 <-chan os.Signal
+
+		BeforeEach(func() {
+			pool = grouper.NewDynamic(syscall.SIGTERM, 1, 2)
+			client = pool.Client()
+			poolProcess = ifrit.Invoke(pool)
+
+			Eventually(client.Inserter()).Should(BeSent(grouper.Member{Name: "child1", Runner: childRunner1}))
+			signals1 = childRunner1.WaitForCall()
+			childRunner1.TriggerReady()
+		})
+
+		AfterEach(func() {
+			poolProcess.Signal(os.Kill)
+			childRunner1.EnsureExit()
+			childRunner2.EnsureExit()
+			childRunner3.EnsureExit()
+			Eventually(poolProcess.Wait()).Should(Receive())
+		})
+
+		It("keeps running once a removed member has exited, and admits a new member", func() {
+			errs := make(chan error, 1)
+			go func() { errs <- client.RemoveMember("child1") }()
+			Eventually(signals1).Should(Receive(Equal(syscall.SIGTERM)))
+			childRunner1.TriggerExit(nil)
+			Eventually(errs).Should(Receive(BeNil()))
+
+			Consistently(poolProcess.Wait()).ShouldNot(Receive())
+
+			Eventually(client.Inserter()).Should(BeSent(grouper.Member{Name: "child2", Runner: childRunner2}))
+			childRunner2.WaitForCall()
+		})
+
+		It("admits no new members once a replaced member has exited", func() {
+			errs := make(chan error, 1)
+			go func() { errs <- client.ReplaceMember(grouper.Member{Name: "child1", Runner: childRunner2}) }()
+			childRunner2.WaitForCall()
+			childRunner2.TriggerReady()
+			Eventually(signals1).Should(Receive(Equal(syscall.SIGTERM)))
+			childRunner1.TriggerExit(nil)
+			Eventually(errs).Should(Receive(BeNil()))
+
+			Consistently(client.Inserter()).ShouldNot(BeSent(grouper.Member{Name: "child3", Runner: childRunner3}))
+			Ω(client.Snapshot()).Should(HaveLen(1))
+		})
+	})
+
+	Describe("Optional members", func() {
+		BeforeEach(func() {
+			pool = grouper.NewDynamic(syscall.SIGTERM, 2, 2, grouper.WithMemberOptions("child1", grouper.MemberOptions{Optional: true}))
+			client = pool.Client()
+			poolProcess = ifrit.Invoke(pool)
+		})
+
+		AfterEach(func() {
+			poolProcess.Signal(os.Kill)
+			childRunner1.EnsureExit()
+			childRunner2.EnsureExit()
+			Eventually(poolProcess.Wait()).Should(Receive())
+		})
+
+		It("does not stop the group when an optional member exits", func() {
+			insert := client.Inserter()
+			Eventually(insert).Should(BeSent(grouper.Member{Name: "child1", Runner: childRunner1}))
+			Eventually(insert).Should(BeSent(grouper.Member{Name: "child2", Runner: childRunner2}))
+			childRunner1.WaitForCall()
+			signals2 := childRunner2.WaitForCall()
+
+			exits := client.ExitListener()
+			childRunner1.TriggerExit(errors.New("Fail"))
+			Eventually(exits).Should(Receive(HaveField("Err", errors.New("Fail"))))
+
+			Consistently(signals2).ShouldNot(Receive())
+			Consistently(poolProcess.Wait()).ShouldNot(Receive())
+		})
+	})
 })
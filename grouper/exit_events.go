@@ -3,14 +3,56 @@ package grouper
 import (
 	"fmt"
 	"sync"
+	"time"
 )
 
 /*
-An ExitEvent occurs every time an invoked member exits.
+An ExitEvent occurs every time an invoked member exits.  StartTime is when the
+group started the member, ExitTime is when the group saw it exit, and Duration
+is the time between them.
+
+Restarts is how many times a Supervisor had restarted the member before the run
+which exited, and TotalUptime is how long the member ran in all of its runs,
+including that one.  Other groups run each member once, so their Restarts is
+zero, and their TotalUptime is Duration.
+
+An ExitEvent is also an error which wraps Err, so that errors.Is and errors.As
+can look through an ErrorTrace to the errors its members exited with.
 */
 type ExitEvent struct {
-	Member Member
-	Err    error
+	Member      Member
+	Err         error
+	StartTime   time.Time
+	ExitTime    time.Time
+	Duration    time.Duration
+	Restarts    int
+	TotalUptime time.Duration
+}
+
+func newExitEvent(member Member, startTime time.Time, err error) ExitEvent {
+	return exitEventAt(member, startTime, time.Now(), err)
+}
+
+func exitEventAt(member Member, startTime, exitTime time.Time, err error) ExitEvent {
+	return ExitEvent{
+		Member:      member,
+		Err:         err,
+		StartTime:   startTime,
+		ExitTime:    exitTime,
+		Duration:    exitTime.Sub(startTime),
+		TotalUptime: exitTime.Sub(startTime),
+	}
+}
+
+func (e ExitEvent) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf("%s exited with nil", e.Member.Name)
+	}
+	return fmt.Sprintf("%s exited with error: %s", e.Member.Name, e.Err.Error())
+}
+
+func (e ExitEvent) Unwrap() error {
+	return e.Err
 }
 
 type exitEventChannel chan ExitEvent
@@ -70,22 +112,40 @@ func (b *exitEventBroadcaster) Close() {
 	b.channels = nil
 }
 
+/*
+An ErrorTrace lists the exits of a group's members, in the order they exited.
+It is returned by static groups when any member exits with an error.  The
+exits of the members of nested groups are described by their qualified names.
+*/
 type ErrorTrace []ExitEvent
 
 func (trace ErrorTrace) Error() string {
 	msg := "Exit trace for group:\n"
 
-	for _, exit := range trace {
-		if exit.Err == nil {
-			msg += fmt.Sprintf("%s exited with nil\n", exit.Member.Name)
-		} else {
-			msg += fmt.Sprintf("%s exited with error: %s\n", exit.Member.Name, exit.Err.Error())
-		}
+	for _, exit := range trace.Flatten() {
+		msg += exit.Error() + "\n"
 	}
 
 	return msg
 }
 
+/*
+Unwrap returns the exits in the trace which have an error.
+*/
+func (trace ErrorTrace) Unwrap() []error {
+	errs := []error{}
+	for _, exit := range trace {
+		if exit.Err != nil {
+			errs = append(errs, exit)
+		}
+	}
+	return errs
+}
+
+/*
+ErrorOrNil returns the trace if any member exited with an error, and nil
+otherwise.
+*/
 func (trace ErrorTrace) ErrorOrNil() error {
 	for _, exit := range trace {
 		if exit.Err != nil {
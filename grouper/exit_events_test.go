@@ -0,0 +1,92 @@
+package grouper_test
+
+import (
+	"errors"
+	"os"
+	"time"
+
+	"github.com/tedsuo/ifrit"
+	"github.com/tedsuo/ifrit/fake_runner"
+	"github.com/tedsuo/ifrit/ginkgomon"
+	"github.com/tedsuo/ifrit/grouper"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// untimed strips the timing from each exit in an ErrorTrace, so that it can be compared with the expected exits
+func untimed(err error) grouper.ErrorTrace {
+	trace := err.(grouper.ErrorTrace)
+	stripped := make(grouper.ErrorTrace, len(trace))
+	for i, exit := range trace {
+		stripped[i] = grouper.ExitEvent{Member: exit.Member, Err: exit.Err}
+	}
+	return stripped
+}
+
+var _ = Describe("ErrorTrace", func() {
+	var (
+		errFail = errors.New("Fail")
+
+		groupProcess ifrit.Process
+		childRunner1 *fake_runner.TestRunner
+		childRunner2 *fake_runner.TestRunner
+		started      time.Time
+	)
+
+	BeforeEach(func() {
+		childRunner1 = fake_runner.NewTestRunner()
+		childRunner2 = fake_runner.NewTestRunner()
+
+		started = time.Now()
+		groupProcess = ifrit.Background(grouper.NewParallel(os.Interrupt, grouper.Members{
+			{Name: "child1", Runner: childRunner1},
+			{Name: "child2", Runner: childRunner2},
+		}))
+		childRunner1.WaitForCall()
+		childRunner1.TriggerReady()
+		childRunner2.WaitForCall()
+		childRunner2.TriggerReady()
+		Eventually(groupProcess.Ready()).Should(BeClosed())
+
+		time.Sleep(10 * time.Millisecond)
+		childRunner1.TriggerExit(&os.PathError{Op: "open", Path: "config", Err: errFail})
+		childRunner2.TriggerExit(nil)
+	})
+
+	AfterEach(func() {
+		ginkgomon.Kill(groupProcess)
+	})
+
+	It("records when each member started and exited", func() {
+		var err error
+		Eventually(groupProcess.Wait()).Should(Receive(&err))
+
+		trace := err.(grouper.ErrorTrace)
+		Ω(trace).Should(HaveLen(2))
+		for _, exit := range trace {
+			Ω(exit.StartTime).Should(BeTemporally("~", started, 10*time.Millisecond))
+			Ω(exit.ExitTime).Should(BeTemporally(">", exit.StartTime))
+			Ω(exit.Duration).Should(Equal(exit.ExitTime.Sub(exit.StartTime)))
+			Ω(exit.Duration).Should(BeNumerically(">=", 10*time.Millisecond))
+		}
+	})
+
+	It("unwraps to the exits with errors", func() {
+		var err error
+		Eventually(groupProcess.Wait()).Should(Receive(&err))
+
+		Ω(errors.Is(err, errFail)).Should(BeTrue())
+
+		var pathErr *os.PathError
+		Ω(errors.As(err, &pathErr)).Should(BeTrue())
+		Ω(pathErr.Path).Should(Equal("config"))
+
+		var exit grouper.ExitEvent
+		Ω(errors.As(err, &exit)).Should(BeTrue())
+		Ω(exit.Member.Name).Should(Equal("child1"))
+		Ω(exit).Should(MatchError("child1 exited with error: open config: Fail"))
+
+		Ω(err.(grouper.ErrorTrace).Unwrap()).Should(HaveLen(1))
+	})
+})
@@ -0,0 +1,126 @@
+package grouper
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/tedsuo/ifrit"
+)
+
+/*
+Dependencies maps the name of a member to the names of the members it depends
+upon.  Members which are not keys have no dependencies.
+*/
+type Dependencies map[string][]string
+
+/*
+NewGraph starts its members in dependency order: each member starts once every
+member it depends upon is ready, so members which do not depend upon each other
+start in parallel.  The group is ready once all of its members are ready.  On
+shutdown, each member is signaled once every member which depends upon it has
+exited, so members are shut down in reverse dependency order.
+
+Use a graph group to describe processes whose dependencies are not a simple
+list, such as several services which share a database but not each other.
+*/
+func NewGraph(terminationSignal os.Signal, members Members, dependencies Dependencies, opts ...Option) ifrit.Runner {
+	return &group{
+		terminationSignal: terminationSignal,
+		members:           members,
+		dependencies:      dependencies,
+		options:           newGroupOptions(ShutdownReverse, opts),
+	}
+}
+
+/*
+ErrUnknownDependency is returned when a member depends upon a name which is not
+a member of the group, or when dependencies are given for one, in which case
+Dependency is empty.
+*/
+type ErrUnknownDependency struct {
+	Member     string
+	Dependency string
+}
+
+func (e ErrUnknownDependency) Error() string {
+	if e.Dependency == "" {
+		return fmt.Sprintf("dependencies given for unknown member %s", e.Member)
+	}
+	return fmt.Sprintf("%s depends on unknown member %s", e.Member, e.Dependency)
+}
+
+/*
+ErrDependencyCycle is returned when members depend upon each other in a cycle.
+Cycle lists the members in the cycle, starting and ending with the same member.
+*/
+type ErrDependencyCycle struct {
+	Cycle []string
+}
+
+func (e ErrDependencyCycle) Error() string {
+	return fmt.Sprintf("dependency cycle: %s", strings.Join(e.Cycle, " -> "))
+}
+
+func validateDependencies(members Members, dependencies Dependencies) error {
+	names := map[string]bool{}
+	for _, member := range members {
+		names[member.Name] = true
+	}
+
+	for _, member := range members {
+		for _, dependency := range dependencies[member.Name] {
+			if !names[dependency] {
+				return ErrUnknownDependency{Member: member.Name, Dependency: dependency}
+			}
+		}
+	}
+	for name := range dependencies {
+		if !names[name] {
+			return ErrUnknownDependency{Member: name}
+		}
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	marks := map[string]int{}
+	var path []string
+
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch marks[name] {
+		case visited:
+			return nil
+		case visiting:
+			for i, n := range path {
+				if n == name {
+					return ErrDependencyCycle{Cycle: append(append([]string{}, path[i:]...), name)}
+				}
+			}
+		}
+
+		marks[name] = visiting
+		path = append(path, name)
+		for _, dependency := range dependencies[name] {
+			err := visit(dependency)
+			if err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		marks[name] = visited
+		return nil
+	}
+
+	for _, member := range members {
+		err := visit(member.Name)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
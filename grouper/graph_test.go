@@ -0,0 +1,232 @@
+package grouper_test
+
+import (
+	"errors"
+	"os"
+	"syscall"
+	"time"
+
+	"github.com/tedsuo/ifrit"
+	"github.com/tedsuo/ifrit/fake_runner"
+	"github.com/tedsuo/ifrit/ginkgomon"
+	"github.com/tedsuo/ifrit/grouper"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Graph Group", func() {
+	var (
+		groupRunner  ifrit.Runner
+		groupProcess ifrit.Process
+		members      grouper.Members
+		dependencies grouper.Dependencies
+
+		database *fake_runner.TestRunner
+		cache    *fake_runner.TestRunner
+		api      *fake_runner.TestRunner
+		web      *fake_runner.TestRunner
+
+		Δ time.Duration = 10 * time.Millisecond
+	)
+
+	BeforeEach(func() {
+		database = fake_runner.NewTestRunner()
+		cache = fake_runner.NewTestRunner()
+		api = fake_runner.NewTestRunner()
+		web = fake_runner.NewTestRunner()
+
+		members = grouper.Members{
+			{Name: "web", Runner: web},
+			{Name: "api", Runner: api},
+			{Name: "database", Runner: database},
+			{Name: "cache", Runner: cache},
+		}
+		dependencies = grouper.Dependencies{
+			"web": {"api"},
+			"api": {"database", "cache"},
+		}
+	})
+
+	JustBeforeEach(func() {
+		groupRunner = grouper.NewGraph(os.Interrupt, members, dependencies)
+		groupProcess = ifrit.Background(groupRunner)
+	})
+
+	AfterEach(func() {
+		database.EnsureExit()
+		cache.EnsureExit()
+		api.EnsureExit()
+		web.EnsureExit()
+
+		ginkgomon.Kill(groupProcess)
+	})
+
+	Describe("validation", func() {
+		Context("when the dependencies contain a cycle", func() {
+			BeforeEach(func() {
+				dependencies["database"] = []string{"web"}
+			})
+
+			It("fails without starting any members", func() {
+				var err error
+				Eventually(groupProcess.Wait()).Should(Receive(&err))
+				Ω(err).Should(Equal(grouper.ErrDependencyCycle{Cycle: []string{"web", "api", "database", "web"}}))
+				Ω(database.RunCallCount()).Should(BeZero())
+			})
+		})
+
+		Context("when a dependency is not a member", func() {
+			BeforeEach(func() {
+				dependencies["api"] = []string{"queue"}
+			})
+
+			It("fails", func() {
+				Eventually(groupProcess.Wait()).Should(Receive(Equal(grouper.ErrUnknownDependency{Member: "api", Dependency: "queue"})))
+			})
+		})
+
+		Context("when dependencies are given for a name which is not a member", func() {
+			BeforeEach(func() {
+				dependencies["queue"] = []string{"database"}
+			})
+
+			It("fails", func() {
+				Eventually(groupProcess.Wait()).Should(Receive(MatchError("dependencies given for unknown member queue")))
+			})
+		})
+	})
+
+	Describe("Start", func() {
+		It("starts members once their dependencies are ready", func() {
+			database.WaitForCall()
+			cache.WaitForCall()
+			Consistently(api.RunCallCount, Δ).Should(BeZero())
+
+			database.TriggerReady()
+			Consistently(api.RunCallCount, Δ).Should(BeZero())
+
+			cache.TriggerReady()
+			api.WaitForCall()
+			Consistently(web.RunCallCount, Δ).Should(BeZero())
+
+			api.TriggerReady()
+			web.WaitForCall()
+			Consistently(groupProcess.Ready(), Δ).ShouldNot(BeClosed())
+
+			web.TriggerReady()
+			Eventually(groupProcess.Ready()).Should(BeClosed())
+		})
+
+		Context("when a member exits before it is ready", func() {
+			It("stops the started members and does not start the rest", func() {
+				databaseSignals := database.WaitForCall()
+				cacheSignals := cache.WaitForCall()
+				database.TriggerReady()
+
+				cache.TriggerExit(errors.New("Fail"))
+				Eventually(databaseSignals).Should(Receive(Equal(os.Interrupt)))
+				Consistently(cacheSignals, Δ).ShouldNot(Receive())
+				database.TriggerExit(nil)
+
+				var err error
+				Eventually(groupProcess.Wait()).Should(Receive(&err))
+				Ω(untimed(err)).Should(Equal(grouper.ErrorTrace{
+					{Member: members[3], Err: errors.New("Fail")},
+					{Member: members[2], Err: nil},
+				}))
+				Ω(api.RunCallCount()).Should(BeZero())
+				Ω(groupProcess.Ready()).ShouldNot(BeClosed())
+			})
+		})
+
+		Context("when it is signaled during startup", func() {
+			It("stops the started members and does not start the rest", func() {
+				databaseSignals := database.WaitForCall()
+				cacheSignals := cache.WaitForCall()
+				database.TriggerReady()
+
+				groupProcess.Signal(syscall.SIGTERM)
+				Eventually(databaseSignals).Should(Receive(Equal(syscall.SIGTERM)))
+				Eventually(cacheSignals).Should(Receive(Equal(syscall.SIGTERM)))
+				database.TriggerExit(nil)
+				cache.TriggerExit(nil)
+
+				Eventually(groupProcess.Wait()).Should(Receive(BeNil()))
+				Ω(api.RunCallCount()).Should(BeZero())
+			})
+		})
+	})
+
+	Describe("Stop", func() {
+		var (
+			databaseSignals <-chan os.Signal
+			cacheSignals    <-chan os.Signal
+			apiSignals      <-chan os.Signal
+			webSignals      <-chan os.Signal
+		)
+
+		JustBeforeEach(func() {
+			databaseSignals = database.WaitForCall()
+			cacheSignals = cache.WaitForCall()
+			database.TriggerReady()
+			cache.TriggerReady()
+			apiSignals = api.WaitForCall()
+			api.TriggerReady()
+			webSignals = web.WaitForCall()
+			web.TriggerReady()
+			Eventually(groupProcess.Ready()).Should(BeClosed())
+		})
+
+		It("stops members in reverse dependency order", func() {
+			groupProcess.Signal(syscall.SIGTERM)
+
+			Eventually(webSignals).Should(Receive(Equal(syscall.SIGTERM)))
+			Consistently(apiSignals, Δ).ShouldNot(Receive())
+			web.TriggerExit(nil)
+
+			Eventually(apiSignals).Should(Receive(Equal(syscall.SIGTERM)))
+			Consistently(databaseSignals, Δ).ShouldNot(Receive())
+			Consistently(cacheSignals, Δ).ShouldNot(Receive())
+			api.TriggerExit(nil)
+
+			Eventually(databaseSignals).Should(Receive(Equal(syscall.SIGTERM)))
+			Eventually(cacheSignals).Should(Receive(Equal(syscall.SIGTERM)))
+			database.TriggerExit(nil)
+			cache.TriggerExit(nil)
+
+			Eventually(groupProcess.Wait()).Should(Receive(BeNil()))
+		})
+
+		It("sends later signals to the members being stopped", func() {
+			groupProcess.Signal(syscall.SIGTERM)
+			Eventually(webSignals).Should(Receive(Equal(syscall.SIGTERM)))
+
+			groupProcess.Signal(os.Kill)
+			Eventually(webSignals).Should(Receive(Equal(os.Kill)))
+			Consistently(apiSignals, Δ).ShouldNot(Receive())
+		})
+
+		Context("when a member exits", func() {
+			It("stops the group with the termination signal, and returns the exit trace", func() {
+				api.TriggerExit(errors.New("Fail"))
+
+				Eventually(webSignals).Should(Receive(Equal(os.Interrupt)))
+				Consistently(databaseSignals, Δ).ShouldNot(Receive())
+				web.TriggerExit(nil)
+
+				Eventually(databaseSignals).Should(Receive(Equal(os.Interrupt)))
+				Eventually(cacheSignals).Should(Receive(Equal(os.Interrupt)))
+				database.TriggerExit(nil)
+				cache.TriggerExit(nil)
+
+				var err error
+				Eventually(groupProcess.Wait()).Should(Receive(&err))
+				errTrace := untimed(err)
+				Ω(errTrace).Should(HaveLen(4))
+				Ω(errTrace[0]).Should(Equal(grouper.ExitEvent{Member: members[1], Err: errors.New("Fail")}))
+				Ω(errTrace[1]).Should(Equal(grouper.ExitEvent{Member: members[0], Err: nil}))
+			})
+		})
+	})
+})
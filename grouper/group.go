@@ -0,0 +1,415 @@
+package grouper
+
+import (
+	"os"
+	"sync"
+	"time"
+
+	"github.com/tedsuo/ifrit"
+)
+
+/*
+group runs members as a dependency graph.  It is the implementation of
+NewGraph, NewStages and Supervisor.
+*/
+type group struct {
+	terminationSignal os.Signal
+	members           Members
+	dependencies      Dependencies
+	cascades          Cascades
+	supervised        bool
+	options           groupOptions
+
+	requests    chan func(*groupRun)
+	stopped     chan struct{}
+	stoppedOnce sync.Once
+}
+
+type memberRun struct {
+	member       Member
+	options      MemberOptions
+	dependencies []*memberRun
+	dependents   []*memberRun
+	state        MemberState
+	process      ifrit.Process
+	restarts     int
+	lastErr      error
+	startTime    time.Time
+	exitTime     time.Time
+	uptime       time.Duration
+	rolling      bool
+	demanded     bool
+	waiters      []chan error
+
+	cascadeDependents []*memberRun
+	cascading         bool
+}
+
+type memberEvent struct {
+	member  *memberRun
+	ready   bool
+	restart bool
+	err     error
+}
+
+type groupRun struct {
+	group   *group
+	members []*memberRun
+	events  chan memberEvent
+	done    chan struct{}
+
+	stopping    bool
+	signal      os.Signal
+	stops       *stopSignals
+	errTrace    ErrorTrace
+	errOccurred bool
+
+	rolling *rollingRestart
+}
+
+func (g *group) validate() error {
+	err := g.members.Validate()
+	if err != nil {
+		return err
+	}
+	err = g.options.validateMembers(g.members)
+	if err != nil {
+		return err
+	}
+	err = validateDependencies(g.members, g.dependencies)
+	if err != nil {
+		return err
+	}
+	return validateCascades(g.dependencies, g.cascades)
+}
+
+func (g *group) Run(signals <-chan os.Signal, ready chan<- struct{}) error {
+	if g.stopped != nil {
+		defer g.stoppedOnce.Do(func() { close(g.stopped) })
+	}
+
+	err := g.validate()
+	if err != nil {
+		return err
+	}
+
+	r := g.newRun()
+	defer close(r.done)
+
+	r.startEligible()
+	for {
+		if ready != nil && !r.stopping && r.allReady() {
+			close(ready)
+			ready = nil
+		}
+
+		if r.stopping && r.allExited() {
+			if r.errOccurred {
+				return r.errTrace
+			}
+			return nil
+		}
+
+		select {
+		case signal := <-signals:
+			r.stop(signal)
+
+		case event := <-r.events:
+			r.handle(event)
+
+		case request := <-g.requests:
+			request(r)
+		}
+	}
+}
+
+/*
+do runs request on the group's event loop, once the group is running.  It
+returns ErrGroupStopped if the group has exited.
+*/
+func (g *group) do(request func(*groupRun)) error {
+	select {
+	case g.requests <- request:
+		return nil
+	case <-g.stopped:
+		return ErrGroupStopped
+	}
+}
+
+func (g *group) newRun() *groupRun {
+	r := &groupRun{
+		group:  g,
+		events: make(chan memberEvent),
+		done:   make(chan struct{}),
+		stops:  newStopSignals(g.stopSignal()),
+	}
+
+	byName := map[string]*memberRun{}
+	for _, member := range g.members {
+		m := &memberRun{member: member, options: g.options.member(member.Name)}
+		r.members = append(r.members, m)
+		byName[member.Name] = m
+	}
+
+	for _, m := range r.members {
+		for _, name := range g.dependencies[m.member.Name] {
+			dependency := byName[name]
+			m.dependencies = append(m.dependencies, dependency)
+			dependency.dependents = append(dependency.dependents, m)
+		}
+		for _, name := range g.cascades[m.member.Name] {
+			dependency := byName[name]
+			dependency.cascadeDependents = append(dependency.cascadeDependents, m)
+		}
+	}
+
+	for _, m := range r.members {
+		if !g.supervised || !m.options.Lazy {
+			r.demand(m)
+		}
+	}
+
+	return r
+}
+
+func (r *groupRun) startEligible() {
+	if r.stopping {
+		return
+	}
+
+	for _, m := range r.members {
+		if m.state != MemberPending || !m.demanded {
+			continue
+		}
+
+		eligible := true
+		for _, dependency := range m.dependencies {
+			if !dependency.satisfied() {
+				eligible = false
+				break
+			}
+		}
+
+		if eligible {
+			r.start(m)
+		}
+	}
+}
+
+func (r *groupRun) start(m *memberRun) {
+	m.state = MemberStarting
+	m.startTime = time.Now()
+	m.process = ifrit.Background(r.group.options.runner(m.member, r.stops))
+
+	process := m.process
+	go func() {
+		select {
+		case <-process.Ready():
+			select {
+			case r.events <- memberEvent{member: m, ready: true}:
+			case <-r.done:
+				return
+			}
+
+		case err := <-process.Wait():
+			select {
+			case r.events <- memberEvent{member: m, err: err}:
+			case <-r.done:
+			}
+			return
+		}
+
+		err := <-process.Wait()
+		select {
+		case r.events <- memberEvent{member: m, err: err}:
+		case <-r.done:
+		}
+	}()
+}
+
+func (r *groupRun) handle(event memberEvent) {
+	m := event.member
+
+	if event.ready {
+		if m.state == MemberStarting {
+			m.state = MemberReady
+			m.notifyWaiters(nil)
+			if m.rolling {
+				r.rolledOver(m, nil)
+			}
+			r.startEligible()
+		}
+		return
+	}
+
+	if event.restart {
+		if m.state == MemberRestarting {
+			m.restarts++
+			r.start(m)
+		}
+		return
+	}
+
+	m.exitTime = time.Now()
+	m.uptime += m.exitTime.Sub(m.startTime)
+
+	if m.rolling {
+		if !r.stopping && m.state == MemberReady {
+			r.start(m)
+			return
+		}
+		r.rolledOver(m, ErrRollingRestartFailed{Member: m.member.Name, Err: event.err})
+	}
+
+	if m.cascading {
+		m.cascading = false
+		if !r.stopping {
+			r.cascaded(m)
+			return
+		}
+	}
+
+	if !r.stopping && r.group.supervised && m.options.Restart.shouldRestart(event.err, m.restarts) {
+		m.state = MemberRestarting
+		m.lastErr = event.err
+		r.scheduleRestart(m)
+		r.cascade(m)
+		return
+	}
+
+	r.exited(m, event.err)
+
+	if !r.stopping {
+		if m.options.Optional {
+			r.startEligible()
+			return
+		}
+		r.stop(r.group.terminationSignal)
+		return
+	}
+
+	r.signalEligible()
+}
+
+func (r *groupRun) exited(m *memberRun, err error) {
+	m.state = MemberExited
+	m.lastErr = err
+	exit := exitEventAt(m.member, m.startTime, m.exitTime, err)
+	exit.Restarts = m.restarts
+	exit.TotalUptime = m.uptime
+	r.errTrace = append(r.errTrace, exit)
+	m.notifyWaiters(ErrMemberExited{Name: m.member.Name, Err: err})
+	if err != nil && !m.options.Optional {
+		r.errOccurred = true
+	}
+}
+
+func (r *groupRun) scheduleRestart(m *memberRun) {
+	timer := time.NewTimer(m.options.Restart.delay(m.restarts))
+
+	go func() {
+		defer timer.Stop()
+
+		select {
+		case <-timer.C:
+		case <-r.done:
+			return
+		}
+
+		select {
+		case r.events <- memberEvent{member: m, restart: true}:
+		case <-r.done:
+		}
+	}()
+}
+
+/*
+stop begins shutting the group down with signal, or, if it is already shutting
+down, sends signal to the members which have already been signaled.
+*/
+func (r *groupRun) stop(signal os.Signal) {
+	if signal != nil {
+		r.stops.add(signal)
+	}
+	if r.stopping {
+		r.signal = signal
+		for _, m := range r.members {
+			if m.state == MemberStopping && signal != nil {
+				m.process.Signal(signal)
+			}
+		}
+		r.signalEligible()
+		return
+	}
+
+	r.stopping = true
+	r.signal = signal
+	if r.rolling != nil {
+		r.rolling.response <- ErrGroupStopped
+		r.rolling = nil
+	}
+	for _, m := range r.members {
+		m.notifyWaiters(ErrGroupStopped)
+		if m.state == MemberRestarting {
+			r.exited(m, m.lastErr)
+		}
+	}
+	r.signalEligible()
+}
+
+/*
+signalEligible signals each running member whose dependents, direct or
+indirect, have all exited.
+*/
+func (r *groupRun) signalEligible() {
+	for _, m := range r.members {
+		if m.state != MemberStarting && m.state != MemberReady {
+			continue
+		}
+
+		if m.dependentsExited() {
+			m.state = MemberStopping
+			if r.signal != nil {
+				m.process.Signal(r.signal)
+			}
+		}
+	}
+}
+
+func (m *memberRun) dependentsExited() bool {
+	for _, dependent := range m.dependents {
+		if dependent.state != MemberExited && dependent.state != MemberPending {
+			return false
+		}
+		if !dependent.dependentsExited() {
+			return false
+		}
+	}
+	return true
+}
+
+/*
+satisfied reports whether the member is ready, or is an optional member which
+has exited and will not be waited for.
+*/
+func (m *memberRun) satisfied() bool {
+	return m.state == MemberReady || (m.state == MemberExited && m.options.Optional)
+}
+
+func (r *groupRun) allReady() bool {
+	for _, m := range r.members {
+		if m.demanded && !m.satisfied() {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *groupRun) allExited() bool {
+	for _, m := range r.members {
+		if m.state != MemberExited && m.state != MemberPending {
+			return false
+		}
+	}
+	return true
+}
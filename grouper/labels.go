@@ -0,0 +1,97 @@
+package grouper
+
+import (
+	"os"
+	"sort"
+)
+
+/*
+Labels are key/value metadata attached to a member through its MemberOptions,
+so that operations can act on groups of members finer than the whole group.
+*/
+type Labels map[string]string
+
+/*
+A Selector matches the members whose labels include every one of its labels.
+An empty Selector matches every member.
+*/
+type Selector map[string]string
+
+/*
+Matches reports whether labels include every label of the selector.
+*/
+func (s Selector) Matches(labels Labels) bool {
+	for key, value := range s {
+		label, found := labels[key]
+		if !found || label != value {
+			return false
+		}
+	}
+	return true
+}
+
+/*
+Select returns the members whose labels, as given to them by opts, match
+selector, in member order.
+*/
+func (m Members) Select(selector Selector, opts ...Option) Members {
+	options := newGroupOptions(ShutdownReverse, opts)
+	selected := Members{}
+	for _, member := range m {
+		if selector.Matches(options.member(member.Name).Labels) {
+			selected = append(selected, member)
+		}
+	}
+	return selected
+}
+
+/*
+SignalMembers sends signal to each running member which matches selector, as
+SignalMember does, and returns the names of the members it signaled, in member
+order.
+*/
+func (s *Supervisor) SignalMembers(selector Selector, signal os.Signal) ([]string, error) {
+	response := make(chan []string, 1)
+	err := s.group.do(func(r *groupRun) {
+		response <- r.signalMembers(selector, signal)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return <-response, nil
+}
+
+func (r *groupRun) signalMembers(selector Selector, signal os.Signal) []string {
+	signaled := []string{}
+	for _, m := range r.members {
+		if !selector.Matches(m.options.Labels) {
+			continue
+		}
+		if r.signalMember(m.member.Name, signal) == nil {
+			signaled = append(signaled, m.member.Name)
+		}
+	}
+	return signaled
+}
+
+type signalMembersRequest struct {
+	Selector Selector
+	Signal   os.Signal
+	Response chan []string
+}
+
+/*
+SignalMatching signals each member which matches selector, and returns their
+names, sorted.
+*/
+func (g *processSet) SignalMatching(selector Selector, signal os.Signal) []string {
+	signaled := []string{}
+	for name, process := range g.processes {
+		if selector.Matches(g.options.member(name).Labels) {
+			process.Signal(signal)
+			signaled = append(signaled, name)
+		}
+	}
+	sort.Strings(signaled)
+	return signaled
+}
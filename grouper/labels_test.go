@@ -0,0 +1,153 @@
+package grouper_test
+
+import (
+	"os"
+	"syscall"
+	"time"
+
+	"github.com/tedsuo/ifrit"
+	"github.com/tedsuo/ifrit/fake_runner"
+	"github.com/tedsuo/ifrit/ginkgomon"
+	"github.com/tedsuo/ifrit/grouper"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Labels", func() {
+	var (
+		frontendLabels = grouper.Labels{"tier": "frontend", "critical": "true"}
+		backendLabels  = grouper.Labels{"tier": "backend", "critical": "true"}
+		workerLabels   = grouper.Labels{"tier": "backend"}
+
+		labels = []grouper.Option{
+			grouper.WithMemberOptions("frontend", grouper.MemberOptions{Labels: frontendLabels}),
+			grouper.WithMemberOptions("backend", grouper.MemberOptions{Labels: backendLabels}),
+			grouper.WithMemberOptions("worker", grouper.MemberOptions{Labels: workerLabels}),
+		}
+
+		Δ time.Duration = 10 * time.Millisecond
+	)
+
+	Describe("Selector", func() {
+		It("matches labels which include all of its labels", func() {
+			Ω(grouper.Selector{"tier": "frontend"}.Matches(frontendLabels)).Should(BeTrue())
+			Ω(grouper.Selector{"tier": "frontend", "critical": "true"}.Matches(frontendLabels)).Should(BeTrue())
+			Ω(grouper.Selector{"tier": "frontend"}.Matches(backendLabels)).Should(BeFalse())
+			Ω(grouper.Selector{"critical": "true"}.Matches(workerLabels)).Should(BeFalse())
+			Ω(grouper.Selector{}.Matches(nil)).Should(BeTrue())
+		})
+	})
+
+	Describe("Members.Select", func() {
+		It("returns the matching members, in member order", func() {
+			members := grouper.Members{
+				{Name: "frontend"},
+				{Name: "backend"},
+				{Name: "worker"},
+			}
+
+			selected := members.Select(grouper.Selector{"tier": "backend"}, labels...)
+			Ω(selected).Should(HaveLen(2))
+			Ω(selected[0].Name).Should(Equal("backend"))
+			Ω(selected[1].Name).Should(Equal("worker"))
+		})
+	})
+
+	Describe("Supervisor", func() {
+		var (
+			supervisor   *grouper.Supervisor
+			groupProcess ifrit.Process
+			frontend     *fake_runner.TestRunner
+			backend      *fake_runner.TestRunner
+			worker       *fake_runner.TestRunner
+		)
+
+		BeforeEach(func() {
+			frontend = fake_runner.NewTestRunner()
+			backend = fake_runner.NewTestRunner()
+			worker = fake_runner.NewTestRunner()
+			supervisor = grouper.NewSupervisor(os.Interrupt, grouper.Members{
+				{Name: "frontend", Runner: frontend},
+				{Name: "backend", Runner: backend},
+				{Name: "worker", Runner: worker},
+			}, labels...)
+			groupProcess = ifrit.Background(supervisor)
+			frontend.WaitForCall()
+			backend.WaitForCall()
+			worker.WaitForCall()
+		})
+
+		AfterEach(func() {
+			frontend.EnsureExit()
+			backend.EnsureExit()
+			worker.EnsureExit()
+			ginkgomon.Kill(groupProcess)
+		})
+
+		It("signals only the members which match", func() {
+			signaled, err := supervisor.SignalMembers(grouper.Selector{"critical": "true"}, syscall.SIGHUP)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(signaled).Should(Equal([]string{"frontend", "backend"}))
+
+			Eventually(frontend.WaitForCall()).Should(Receive(Equal(syscall.SIGHUP)))
+			Eventually(backend.WaitForCall()).Should(Receive(Equal(syscall.SIGHUP)))
+			Consistently(worker.WaitForCall(), Δ).ShouldNot(Receive())
+		})
+
+		It("includes each member's labels in its snapshot", func() {
+			snapshot, err := supervisor.Snapshot()
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(snapshot[2].Labels).Should(Equal(workerLabels))
+		})
+	})
+
+	Describe("DynamicGroup", func() {
+		var (
+			client      grouper.DynamicClient
+			poolProcess ifrit.Process
+			frontend    *fake_runner.TestRunner
+			worker      *fake_runner.TestRunner
+		)
+
+		BeforeEach(func() {
+			frontend = fake_runner.NewTestRunner()
+			worker = fake_runner.NewTestRunner()
+
+			pool := grouper.NewDynamic(nil, 2, 2, labels...)
+			client = pool.Client()
+			poolProcess = ifrit.Invoke(pool)
+
+			Eventually(client.Inserter()).Should(BeSent(grouper.Member{Name: "frontend", Runner: frontend}))
+			Eventually(client.Inserter()).Should(BeSent(grouper.Member{Name: "worker", Runner: worker}))
+			frontend.WaitForCall()
+			worker.WaitForCall()
+		})
+
+		AfterEach(func() {
+			poolProcess.Signal(os.Kill)
+			frontend.EnsureExit()
+			worker.EnsureExit()
+			Eventually(poolProcess.Wait()).Should(Receive())
+		})
+
+		It("signals only the members which match", func() {
+			signaled, err := client.SignalMembers(grouper.Selector{"tier": "frontend"}, syscall.SIGHUP)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(signaled).Should(Equal([]string{"frontend"}))
+
+			Eventually(frontend.WaitForCall()).Should(Receive(Equal(syscall.SIGHUP)))
+			Consistently(worker.WaitForCall(), Δ).ShouldNot(Receive())
+		})
+
+		It("includes each member's labels in its snapshot", func() {
+			snapshot := client.Snapshot()
+			Ω(snapshot).Should(HaveLen(2))
+			for _, status := range snapshot {
+				if status.Name == "worker" {
+					Ω(status.Labels).Should(Equal(workerLabels))
+				}
+			}
+		})
+	})
+})
@@ -0,0 +1,116 @@
+package grouper
+
+import "fmt"
+
+/*
+ErrMemberExited is returned by StartMember when the member exits before it is
+ready, and is not restarted.
+*/
+type ErrMemberExited struct {
+	Name string
+	Err  error
+}
+
+func (e ErrMemberExited) Error() string {
+	return fmt.Sprintf("member %s exited before it was ready: %v", e.Name, e.Err)
+}
+
+func (e ErrMemberExited) Unwrap() error {
+	return e.Err
+}
+
+/*
+StartMember starts the named lazy member, along with any lazy members it
+depends on, and returns once it is ready.  A member which is already running
+is not started again.  StartMember returns ErrMemberNotFound if there is no
+such member, ErrMemberExited if it exits before it is ready and is not
+restarted, or ErrGroupStopped if the Supervisor is signaled first.
+*/
+func (s *Supervisor) StartMember(name string) error {
+	response := make(chan error, 1)
+	err := s.group.do(func(r *groupRun) {
+		r.startMember(name, response)
+	})
+	if err != nil {
+		return err
+	}
+	return <-response
+}
+
+/*
+A MemberHandle starts a lazy member of a Supervisor on demand.  It can be given
+to the Runners of the Supervisor's other members, so that a lazy member is only
+started once another member first needs it.
+*/
+type MemberHandle struct {
+	supervisor *Supervisor
+	name       string
+}
+
+/*
+Handle returns a MemberHandle for the named member.
+*/
+func (s *Supervisor) Handle(name string) MemberHandle {
+	return MemberHandle{supervisor: s, name: name}
+}
+
+func (h MemberHandle) Name() string {
+	return h.name
+}
+
+/*
+Start starts the member, as StartMember does.
+*/
+func (h MemberHandle) Start() error {
+	return h.supervisor.StartMember(h.name)
+}
+
+func (r *groupRun) startMember(name string, response chan error) {
+	if r.stopping {
+		response <- ErrGroupStopped
+		return
+	}
+
+	for _, m := range r.members {
+		if m.member.Name != name {
+			continue
+		}
+
+		switch m.state {
+		case MemberReady:
+			response <- nil
+		case MemberExited:
+			response <- ErrMemberExited{Name: name, Err: m.lastErr}
+		default:
+			m.waiters = append(m.waiters, response)
+			r.demand(m)
+			r.startEligible()
+		}
+		return
+	}
+	response <- ErrMemberNotFound{Name: name}
+}
+
+/*
+demand marks m, and the members it depends on, to be started once they are
+eligible.
+*/
+func (r *groupRun) demand(m *memberRun) {
+	if m.demanded {
+		return
+	}
+	m.demanded = true
+	for _, dependency := range m.dependencies {
+		r.demand(dependency)
+	}
+}
+
+/*
+notifyWaiters responds to each StartMember waiting for m.
+*/
+func (m *memberRun) notifyWaiters(err error) {
+	for _, waiter := range m.waiters {
+		waiter <- err
+	}
+	m.waiters = nil
+}
@@ -0,0 +1,165 @@
+package grouper_test
+
+import (
+	"errors"
+	"os"
+	"time"
+
+	"github.com/tedsuo/ifrit"
+	"github.com/tedsuo/ifrit/ginkgomon"
+	"github.com/tedsuo/ifrit/grouper"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Lazy members", func() {
+	var (
+		supervisor   *grouper.Supervisor
+		groupProcess ifrit.Process
+		eager        *gatedRunner
+		lazy         *gatedRunner
+
+		Δ time.Duration = 10 * time.Millisecond
+	)
+
+	startMember := func(name string) <-chan error {
+		errs := make(chan error, 1)
+		go func() {
+			errs <- supervisor.StartMember(name)
+		}()
+		return errs
+	}
+
+	BeforeEach(func() {
+		eager = newGatedRunner()
+		lazy = newGatedRunner()
+		supervisor = grouper.NewSupervisor(os.Interrupt, grouper.Members{
+			{Name: "eager", Runner: eager},
+			{Name: "lazy", Runner: lazy},
+		}, grouper.WithMemberOptions("lazy", grouper.MemberOptions{Lazy: true}))
+		groupProcess = ifrit.Background(supervisor)
+		eager.ready <- struct{}{}
+	})
+
+	AfterEach(func() {
+		ginkgomon.Kill(groupProcess)
+	})
+
+	It("becomes ready without starting them", func() {
+		Eventually(groupProcess.Ready()).Should(BeClosed())
+		Consistently(lazy.Runs, Δ).Should(BeZero())
+
+		snapshot, err := supervisor.Snapshot()
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(snapshot[1].State).Should(Equal(grouper.MemberPending))
+	})
+
+	It("starts a member on demand, and returns once it is ready", func() {
+		Eventually(groupProcess.Ready()).Should(BeClosed())
+
+		errs := startMember("lazy")
+		Eventually(lazy.Runs).Should(Equal(1))
+		Consistently(errs, Δ).ShouldNot(Receive())
+
+		lazy.ready <- struct{}{}
+		Eventually(errs).Should(Receive(BeNil()))
+
+		Ω(supervisor.StartMember("lazy")).Should(Succeed())
+		Ω(lazy.Runs()).Should(Equal(1))
+	})
+
+	It("starts a member through a handle", func() {
+		Eventually(groupProcess.Ready()).Should(BeClosed())
+
+		handle := supervisor.Handle("lazy")
+		Ω(handle.Name()).Should(Equal("lazy"))
+
+		errs := make(chan error, 1)
+		go func() {
+			errs <- handle.Start()
+		}()
+		Eventually(lazy.Runs).Should(Equal(1))
+		lazy.ready <- struct{}{}
+		Eventually(errs).Should(Receive(BeNil()))
+	})
+
+	It("shuts down the members which were started", func() {
+		Eventually(groupProcess.Ready()).Should(BeClosed())
+		errs := startMember("lazy")
+		lazy.ready <- struct{}{}
+		Eventually(errs).Should(Receive(BeNil()))
+
+		groupProcess.Signal(os.Interrupt)
+		Eventually(lazy.signals).Should(Receive(Equal(os.Interrupt)))
+		Eventually(eager.signals).Should(Receive(Equal(os.Interrupt)))
+		Eventually(groupProcess.Wait()).Should(Receive(BeNil()))
+	})
+
+	It("never runs the members which were not started", func() {
+		Eventually(groupProcess.Ready()).Should(BeClosed())
+
+		groupProcess.Signal(os.Interrupt)
+		Eventually(groupProcess.Wait()).Should(Receive(BeNil()))
+		Ω(lazy.Runs()).Should(BeZero())
+	})
+
+	It("returns ErrMemberExited if the member exits before it is ready", func() {
+		errs := startMember("lazy")
+		Eventually(lazy.Runs).Should(Equal(1))
+		lazy.exits <- errors.New("Fail")
+
+		var err error
+		Eventually(errs).Should(Receive(&err))
+		Ω(err).Should(Equal(grouper.ErrMemberExited{Name: "lazy", Err: errors.New("Fail")}))
+		Ω(errors.Unwrap(err)).Should(MatchError("Fail"))
+	})
+
+	It("returns ErrGroupStopped if the group is signaled first", func() {
+		errs := startMember("lazy")
+		Eventually(lazy.Runs).Should(Equal(1))
+
+		groupProcess.Signal(os.Interrupt)
+		Eventually(errs).Should(Receive(Equal(grouper.ErrGroupStopped)))
+	})
+
+	It("returns ErrMemberNotFound for unknown members", func() {
+		Ω(supervisor.StartMember("missing")).Should(Equal(grouper.ErrMemberNotFound{Name: "missing"}))
+	})
+
+	Context("in a supervised graph", func() {
+		var (
+			db  *gatedRunner
+			web *gatedRunner
+		)
+
+		BeforeEach(func() {
+			ginkgomon.Kill(groupProcess)
+
+			db = newGatedRunner()
+			web = newGatedRunner()
+			supervisor = grouper.NewSupervisedGraph(os.Interrupt, grouper.Members{
+				{Name: "db", Runner: db},
+				{Name: "web", Runner: web},
+				{Name: "eager", Runner: eager},
+			}, grouper.Dependencies{"web": {"db"}}, nil,
+				grouper.WithMemberOptions("db", grouper.MemberOptions{Lazy: true}),
+				grouper.WithMemberOptions("web", grouper.MemberOptions{Lazy: true}),
+			)
+			groupProcess = ifrit.Background(supervisor)
+		})
+
+		It("starts the lazy members a member depends on along with it", func() {
+			Consistently(db.Runs, Δ).Should(BeZero())
+
+			errs := startMember("web")
+			Eventually(db.Runs).Should(Equal(1))
+			Consistently(web.Runs, Δ).Should(BeZero())
+
+			db.ready <- struct{}{}
+			Eventually(web.Runs).Should(Equal(1))
+			web.ready <- struct{}{}
+			Eventually(errs).Should(Receive(BeNil()))
+		})
+	})
+})
@@ -0,0 +1,327 @@
+package grouper
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"syscall"
+	"time"
+
+	"github.com/tedsuo/ifrit"
+)
+
+/*
+A RunnerFactory builds the Runner for a member of a manifest, from the member's
+name and the config given for it in the manifest.
+*/
+type RunnerFactory func(name string, config json.RawMessage) (ifrit.Runner, error)
+
+/*
+A Registry maps the member types a manifest may name to the factories which
+build their Runners.
+*/
+type Registry map[string]RunnerFactory
+
+/*
+A Manifest describes a group declaratively, so that the shape of a process tree
+can be changed without rebuilding the program which runs it.
+
+Strategy is one of "ordered", which is the default, "parallel", "graph" or
+"supervisor".  A supervisor whose members have dependencies is built with
+NewSupervisedGraph.  TerminationSignal names one of SIGHUP, SIGINT, SIGQUIT,
+SIGKILL or SIGTERM, and defaults to SIGINT; "none" gives a nil termination
+signal.  ReadyTimeout, StartConcurrency and ShutdownOrder configure ordered and
+parallel groups, as the options of the same names do.
+
+Durations are given as strings, such as "1m30s", in the format accepted by
+time.ParseDuration.
+*/
+type Manifest struct {
+	Strategy          string           `json:"strategy,omitempty"`
+	TerminationSignal string           `json:"termination_signal,omitempty"`
+	ReadyTimeout      Duration         `json:"ready_timeout,omitempty"`
+	StartConcurrency  int              `json:"start_concurrency,omitempty"`
+	ShutdownOrder     string           `json:"shutdown_order,omitempty"`
+	Members           []MemberManifest `json:"members"`
+}
+
+/*
+A MemberManifest describes a member of a Manifest.  The member's Runner is built
+by the factory registered for Type, which is passed Config; alternatively, Group
+describes a nested group to run as the member.
+
+DependsOn and CascadesFrom may only be given for graph and supervisor groups.
+RestartMode is one of "never", "on-failure" or "always", and KillSignal names
+a signal as a Manifest's TerminationSignal does.  The remaining fields set the
+MemberOptions fields of the same names.
+*/
+type MemberManifest struct {
+	Name   string          `json:"name"`
+	Type   string          `json:"type,omitempty"`
+	Config json.RawMessage `json:"config,omitempty"`
+	Group  *Manifest       `json:"group,omitempty"`
+
+	DependsOn    []string `json:"depends_on,omitempty"`
+	CascadesFrom []string `json:"cascades_from,omitempty"`
+
+	Labels   Labels `json:"labels,omitempty"`
+	Optional bool   `json:"optional,omitempty"`
+	Lazy     bool   `json:"lazy,omitempty"`
+
+	RestartMode string   `json:"restart_mode,omitempty"`
+	Backoff     Duration `json:"backoff,omitempty"`
+	MaxBackoff  Duration `json:"max_backoff,omitempty"`
+	MaxRestarts int      `json:"max_restarts,omitempty"`
+
+	StartRetries  int      `json:"start_retries,omitempty"`
+	StartBackoff  Duration `json:"start_backoff,omitempty"`
+	RecoverPanics bool     `json:"recover_panics,omitempty"`
+
+	ShutdownTimeout Duration `json:"shutdown_timeout,omitempty"`
+	KillSignal      string   `json:"kill_signal,omitempty"`
+}
+
+/*
+A Duration is a time.Duration which is read from, and written to, JSON as a
+string.
+*/
+type Duration time.Duration
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	err := json.Unmarshal(data, &s)
+	if err != nil {
+		return err
+	}
+	duration, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(duration)
+	return nil
+}
+
+/*
+ErrUnknownRunnerType is returned when a manifest names a member type which is
+not in the registry.
+*/
+type ErrUnknownRunnerType struct {
+	Member string
+	Type   string
+}
+
+func (e ErrUnknownRunnerType) Error() string {
+	return fmt.Sprintf("member %s has unknown type %q", e.Member, e.Type)
+}
+
+/*
+ErrInvalidManifest is returned when a field of a manifest has a value which
+cannot be used.
+*/
+type ErrInvalidManifest struct {
+	Field string
+	Value string
+}
+
+func (e ErrInvalidManifest) Error() string {
+	return fmt.Sprintf("invalid manifest %s: %q", e.Field, e.Value)
+}
+
+/*
+LoadManifest reads a JSON manifest, and builds the group it describes.  Unknown
+fields are rejected, so that a misspelled field is not silently ignored.
+Manifests written in YAML can be converted to JSON before they are loaded.
+*/
+func LoadManifest(data []byte, registry Registry) (ifrit.Runner, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	var manifest Manifest
+	err := decoder.Decode(&manifest)
+	if err != nil {
+		return nil, err
+	}
+	return manifest.Build(registry)
+}
+
+/*
+Build builds the group the manifest describes, using registry to build the
+Runners of its members.
+*/
+func (m Manifest) Build(registry Registry) (ifrit.Runner, error) {
+	terminationSignal, err := parseSignal("termination_signal", m.TerminationSignal, os.Interrupt)
+	if err != nil {
+		return nil, err
+	}
+
+	members := make(Members, 0, len(m.Members))
+	memberOpts := make([]Option, 0, len(m.Members))
+	dependencies := Dependencies{}
+	cascades := Cascades{}
+	for _, memberManifest := range m.Members {
+		member, options, err := memberManifest.build(registry)
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, member)
+		memberOpts = append(memberOpts, WithMemberOptions(member.Name, options))
+
+		if len(memberManifest.DependsOn) > 0 {
+			dependencies[member.Name] = memberManifest.DependsOn
+		}
+		if len(memberManifest.CascadesFrom) > 0 {
+			cascades[member.Name] = memberManifest.CascadesFrom
+		}
+	}
+
+	opts, err := m.options()
+	if err != nil {
+		return nil, err
+	}
+	opts = append(opts, memberOpts...)
+
+	switch m.Strategy {
+	case "", "ordered", "parallel":
+		if len(dependencies) > 0 || len(cascades) > 0 {
+			return nil, ErrInvalidManifest{Field: "strategy", Value: m.Strategy}
+		}
+		if m.Strategy == "parallel" {
+			return NewParallel(terminationSignal, members, opts...), nil
+		}
+		return NewOrdered(terminationSignal, members, opts...), nil
+	case "graph":
+		if len(cascades) > 0 {
+			return nil, ErrInvalidManifest{Field: "strategy", Value: m.Strategy}
+		}
+		return NewGraph(terminationSignal, members, dependencies, opts...), nil
+	case "supervisor":
+		if len(dependencies) > 0 {
+			return NewSupervisedGraph(terminationSignal, members, dependencies, cascades, opts...), nil
+		}
+		return NewSupervisor(terminationSignal, members, opts...), nil
+	default:
+		return nil, ErrInvalidManifest{Field: "strategy", Value: m.Strategy}
+	}
+}
+
+func (m Manifest) options() ([]Option, error) {
+	opts := []Option{}
+	if m.ReadyTimeout > 0 {
+		opts = append(opts, WithReadyTimeout(time.Duration(m.ReadyTimeout)))
+	}
+	if m.StartConcurrency > 0 {
+		opts = append(opts, WithStartConcurrency(m.StartConcurrency))
+	}
+	if m.ShutdownOrder != "" {
+		order, found := parseShutdownOrder(m.ShutdownOrder)
+		if !found {
+			return nil, ErrInvalidManifest{Field: "shutdown_order", Value: m.ShutdownOrder}
+		}
+		opts = append(opts, WithShutdownOrder(order))
+	}
+	return opts, nil
+}
+
+func (m MemberManifest) build(registry Registry) (Member, MemberOptions, error) {
+	runner, err := m.runner(registry)
+	if err != nil {
+		return Member{}, MemberOptions{}, err
+	}
+
+	mode := RestartNever
+	if m.RestartMode != "" {
+		var found bool
+		mode, found = parseRestartMode(m.RestartMode)
+		if !found {
+			return Member{}, MemberOptions{}, ErrInvalidManifest{Field: "restart_mode", Value: m.RestartMode}
+		}
+	}
+
+	killSignal, err := parseSignal("kill_signal", m.KillSignal, nil)
+	if err != nil {
+		return Member{}, MemberOptions{}, err
+	}
+
+	return Member{Name: m.Name, Runner: runner}, MemberOptions{
+		Labels: m.Labels,
+		Restart: RestartPolicy{
+			Mode:        mode,
+			Backoff:     time.Duration(m.Backoff),
+			MaxBackoff:  time.Duration(m.MaxBackoff),
+			MaxRestarts: m.MaxRestarts,
+		},
+		Optional:        m.Optional,
+		Lazy:            m.Lazy,
+		ShutdownTimeout: time.Duration(m.ShutdownTimeout),
+		KillSignal:      killSignal,
+		StartRetries:    m.StartRetries,
+		StartBackoff:    time.Duration(m.StartBackoff),
+		RecoverPanics:   m.RecoverPanics,
+	}, nil
+}
+
+func (m MemberManifest) runner(registry Registry) (ifrit.Runner, error) {
+	if m.Group != nil {
+		if m.Type != "" {
+			return nil, ErrInvalidManifest{Field: "type", Value: m.Type}
+		}
+		return m.Group.Build(registry)
+	}
+
+	factory, found := registry[m.Type]
+	if !found {
+		return nil, ErrUnknownRunnerType{Member: m.Name, Type: m.Type}
+	}
+
+	runner, err := factory(m.Name, m.Config)
+	if err != nil {
+		return nil, fmt.Errorf("member %s: %w", m.Name, err)
+	}
+	return runner, nil
+}
+
+var manifestSignals = map[string]os.Signal{
+	"SIGHUP":  syscall.SIGHUP,
+	"SIGINT":  syscall.SIGINT,
+	"SIGQUIT": syscall.SIGQUIT,
+	"SIGKILL": syscall.SIGKILL,
+	"SIGTERM": syscall.SIGTERM,
+}
+
+func parseSignal(field, name string, defaultSignal os.Signal) (os.Signal, error) {
+	switch name {
+	case "":
+		return defaultSignal, nil
+	case "none":
+		return nil, nil
+	}
+
+	signal, found := manifestSignals[name]
+	if !found {
+		return nil, ErrInvalidManifest{Field: field, Value: name}
+	}
+	return signal, nil
+}
+
+func parseRestartMode(name string) (RestartMode, bool) {
+	for _, mode := range []RestartMode{RestartNever, RestartOnFailure, RestartAlways} {
+		if mode.String() == name {
+			return mode, true
+		}
+	}
+	return RestartNever, false
+}
+
+func parseShutdownOrder(name string) (ShutdownOrder, bool) {
+	for _, order := range []ShutdownOrder{ShutdownReverse, ShutdownForward, ShutdownParallel} {
+		if order.String() == name {
+			return order, true
+		}
+	}
+	return ShutdownReverse, false
+}
@@ -0,0 +1,172 @@
+package grouper_test
+
+import (
+	"encoding/json"
+	"errors"
+	"syscall"
+	"time"
+
+	"github.com/tedsuo/ifrit"
+	"github.com/tedsuo/ifrit/ginkgomon"
+	"github.com/tedsuo/ifrit/grouper"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Manifest", func() {
+	var (
+		registry grouper.Registry
+		runners  map[string]*gatedRunner
+		configs  map[string]string
+	)
+
+	BeforeEach(func() {
+		runners = map[string]*gatedRunner{}
+		configs = map[string]string{}
+		registry = grouper.Registry{
+			"gated": func(name string, config json.RawMessage) (ifrit.Runner, error) {
+				runners[name] = newGatedRunner()
+				configs[name] = string(config)
+				return runners[name], nil
+			},
+			"broken": func(name string, config json.RawMessage) (ifrit.Runner, error) {
+				return nil, errors.New("no config")
+			},
+		}
+	})
+
+	flatten := func(runner ifrit.Runner) grouper.Members {
+		return grouper.Members{{Name: "app", Runner: runner}}.Flatten()
+	}
+
+	flattenOptions := func(runner ifrit.Runner) map[string]grouper.MemberOptions {
+		return grouper.Members{{Name: "app", Runner: runner}}.FlattenOptions()
+	}
+
+	It("builds the members it describes, in order", func() {
+		runner, err := grouper.LoadManifest([]byte(`{
+			"members": [
+				{"name": "db", "type": "gated", "config": {"port": 5432}},
+				{"name": "web", "type": "gated", "labels": {"tier": "frontend"}, "optional": true, "lazy": true,
+				 "shutdown_timeout": "5s", "kill_signal": "SIGKILL", "start_retries": 2, "start_backoff": "100ms"}
+			]
+		}`), registry)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		members := flatten(runner)
+		Ω(members).Should(HaveLen(2))
+		Ω(members[0].Name).Should(Equal("app/db"))
+		Ω(members[0].Runner).Should(Equal(runners["db"]))
+		Ω(configs["db"]).Should(MatchJSON(`{"port": 5432}`))
+
+		web := flattenOptions(runner)["app/web"]
+		Ω(web.Labels).Should(Equal(grouper.Labels{"tier": "frontend"}))
+		Ω(web.Optional).Should(BeTrue())
+		Ω(web.Lazy).Should(BeTrue())
+		Ω(web.ShutdownTimeout).Should(Equal(5 * time.Second))
+		Ω(web.KillSignal).Should(Equal(syscall.SIGKILL))
+		Ω(web.StartRetries).Should(Equal(2))
+		Ω(web.StartBackoff).Should(Equal(100 * time.Millisecond))
+	})
+
+	It("builds supervisors with restart policies and dependencies", func() {
+		runner, err := grouper.LoadManifest([]byte(`{
+			"strategy": "supervisor",
+			"members": [
+				{"name": "db", "type": "gated", "restart_mode": "on-failure", "backoff": "1s", "max_restarts": 3},
+				{"name": "web", "type": "gated", "depends_on": ["db"], "cascades_from": ["db"]}
+			]
+		}`), registry)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(runner).Should(BeAssignableToTypeOf(&grouper.Supervisor{}))
+
+		Ω(flattenOptions(runner)["app/db"].Restart).Should(Equal(grouper.RestartPolicy{Mode: grouper.RestartOnFailure, Backoff: time.Second, MaxRestarts: 3}))
+	})
+
+	It("builds nested groups", func() {
+		runner, err := grouper.LoadManifest([]byte(`{
+			"members": [
+				{"name": "db", "type": "gated"},
+				{"name": "api", "group": {"strategy": "parallel", "members": [
+					{"name": "http", "type": "gated"},
+					{"name": "grpc", "type": "gated"}
+				]}}
+			]
+		}`), registry)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		names := []string{}
+		for _, member := range flatten(runner) {
+			names = append(names, member.Name)
+		}
+		Ω(names).Should(Equal([]string{"app/db", "app/api/http", "app/api/grpc"}))
+	})
+
+	It("runs the group it builds", func() {
+		runner, err := grouper.LoadManifest([]byte(`{
+			"strategy": "parallel",
+			"termination_signal": "SIGTERM",
+			"members": [
+				{"name": "first", "type": "gated"},
+				{"name": "second", "type": "gated"}
+			]
+		}`), registry)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		process := ifrit.Background(runner)
+		defer ginkgomon.Kill(process)
+
+		runners["first"].ready <- struct{}{}
+		runners["second"].ready <- struct{}{}
+		Eventually(process.Ready()).Should(BeClosed())
+
+		runners["first"].exits <- nil
+		Eventually(runners["second"].signals).Should(Receive(Equal(syscall.SIGTERM)))
+		Eventually(process.Wait()).Should(Receive(BeNil()))
+	})
+
+	Describe("invalid manifests", func() {
+		load := func(manifest string) error {
+			_, err := grouper.LoadManifest([]byte(manifest), registry)
+			return err
+		}
+
+		It("rejects unknown member types", func() {
+			Ω(load(`{"members": [{"name": "db", "type": "postgres"}]}`)).Should(Equal(grouper.ErrUnknownRunnerType{Member: "db", Type: "postgres"}))
+		})
+
+		It("rejects unknown fields", func() {
+			Ω(load(`{"members": [{"name": "db", "type": "gated", "restart": "always"}]}`)).Should(HaveOccurred())
+		})
+
+		It("rejects unknown values", func() {
+			Ω(load(`{"strategy": "random", "members": []}`)).Should(Equal(grouper.ErrInvalidManifest{Field: "strategy", Value: "random"}))
+			Ω(load(`{"termination_signal": "SIGFOO", "members": []}`)).Should(Equal(grouper.ErrInvalidManifest{Field: "termination_signal", Value: "SIGFOO"}))
+			Ω(load(`{"shutdown_order": "sideways", "members": []}`)).Should(Equal(grouper.ErrInvalidManifest{Field: "shutdown_order", Value: "sideways"}))
+			Ω(load(`{"members": [{"name": "db", "type": "gated", "restart_mode": "sometimes"}]}`)).Should(Equal(grouper.ErrInvalidManifest{Field: "restart_mode", Value: "sometimes"}))
+			Ω(load(`{"members": [{"name": "db", "type": "gated", "backoff": "soon"}]}`)).Should(HaveOccurred())
+		})
+
+		It("rejects dependencies in groups which do not support them", func() {
+			Ω(load(`{"members": [{"name": "db", "type": "gated"}, {"name": "web", "type": "gated", "depends_on": ["db"]}]}`)).Should(Equal(grouper.ErrInvalidManifest{Field: "strategy", Value: ""}))
+		})
+
+		It("returns the errors of runner factories, naming the member", func() {
+			err := load(`{"members": [{"name": "db", "type": "broken"}]}`)
+			Ω(err).Should(MatchError("member db: no config"))
+		})
+	})
+})
+
+var _ = Describe("Duration", func() {
+	It("round-trips through JSON as a string", func() {
+		data, err := json.Marshal(grouper.Duration(90 * time.Second))
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(string(data)).Should(Equal(`"1m30s"`))
+
+		var d grouper.Duration
+		Ω(json.Unmarshal(data, &d)).Should(Succeed())
+		Ω(d).Should(Equal(grouper.Duration(90 * time.Second)))
+	})
+})
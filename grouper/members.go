@@ -2,23 +2,379 @@ package grouper
 
 import (
 	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
 
 	"github.com/tedsuo/ifrit"
+	"github.com/tedsuo/ifrit/internal/signalqueue"
 )
 
 /*
-A Member associates a unique name with a Runner.
+A Member associates a unique name with a Runner.  How a group runs each member
+is configured separately, by name; see WithMemberOptions.
 */
 type Member struct {
 	Name string
 	ifrit.Runner
 }
 
+/*
+MemberOptions configure how a group runs one of its members.
+
+Restart is only used by Supervisors; other groups never restart their members.
+
+An Optional member is not needed for the group to keep running: if it exits,
+even before it is ready, its exit is recorded and the group carries on, instead
+of shutting down.  An optional member's error does not cause a static group to
+return an error.
+
+If ShutdownTimeout is set, the group waits at most that long for the member to
+exit once it has been signaled to stop.  Signals which only reach the member
+through SignalMember or SignalMatching do not start the timeout.  A member which
+outlives its timeout is sent KillSignal, if it is set, and abandoned: the group
+records an ErrShutdownTimeout as its exit, and carries on shutting down without
+it.
+
+Signals translates the signals the group sends to the member: a signal which is
+a key is delivered as the signal it maps to, so that a group can send one stop
+signal to members which each expect a different one.  KillSignal is delivered
+as is.
+
+If StartRetries is set, a member which exits with an error before it is ready
+is run again, up to StartRetries times, before the group treats it as having
+failed.  The first retry happens after StartBackoff, and each later one after
+twice the previous delay.  A member which is signaled to stop while starting is
+not retried.
+
+Labels attach metadata to the member, which Selectors match against.
+
+If RecoverPanics is set, a panic in the member's Run is recovered, and recorded
+as the member's exit with an ifrit.PanicError, so that the group handles it as
+it would any other failure, rather than the whole program crashing.
+
+A Lazy member of a Supervisor is not started with the group, and does not hold
+up its readiness.  It is started on first demand: by StartMember, through a
+MemberHandle, or once a member which is not lazy depends on it.  Once started,
+a lazy member is restarted and shut down like any other member.  Other groups
+start lazy members as usual.
+*/
+type MemberOptions struct {
+	Labels Labels
+
+	Restart  RestartPolicy
+	Optional bool
+
+	ShutdownTimeout time.Duration
+	KillSignal      os.Signal
+
+	Signals map[os.Signal]os.Signal
+
+	StartRetries int
+	StartBackoff time.Duration
+
+	RecoverPanics bool
+
+	Lazy bool
+}
+
+/*
+WithMemberOptions configures the member named name, in any group it is run in.
+The options of members which are not given any are zero.  A group other than a
+dynamic group, whose members arrive as it runs, fails to start with
+ErrUnknownMemberOptions if it has no member named name.  A later
+WithMemberOptions for the same name replaces an earlier one.
+*/
+func WithMemberOptions(name string, options MemberOptions) Option {
+	return func(o *groupOptions) {
+		if o.memberOptions == nil {
+			o.memberOptions = map[string]MemberOptions{}
+		}
+		o.memberOptions[name] = options
+	}
+}
+
+/*
+ErrUnknownMemberOptions is returned by a group which is given options, by
+WithMemberOptions, for a name which is not one of its members.
+*/
+type ErrUnknownMemberOptions struct {
+	Member string
+}
+
+func (e ErrUnknownMemberOptions) Error() string {
+	return fmt.Sprintf("options given for unknown member %s", e.Member)
+}
+
+/*
+validateMembers checks that options were only given for members.
+*/
+func (o groupOptions) validateMembers(members Members) error {
+	names := map[string]bool{}
+	for _, member := range members {
+		names[member.Name] = true
+	}
+
+	unknown := []string{}
+	for name := range o.memberOptions {
+		if !names[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return ErrUnknownMemberOptions{Member: unknown[0]}
+	}
+	return nil
+}
+
+/*
+runner returns the Runner a group runs for the member.  stops recognizes the
+signals with which the group stops its members; a nil stops treats every signal
+the group sends as a stop.
+*/
+func (o groupOptions) runner(member Member, stops *stopSignals) ifrit.Runner {
+	options := o.member(member.Name)
+	runner := member.Runner
+	if options.RecoverPanics {
+		runner = ifrit.RecoverPanics(runner)
+	}
+	if options.StartRetries > 0 {
+		runner = options.withStartRetries(stops, runner)
+	}
+	if options.ShutdownTimeout > 0 {
+		runner = options.withShutdownTimeout(member.Name, stops, runner)
+	}
+	if len(options.Signals) > 0 {
+		runner = translateSignals(runner, options.Signals)
+	}
+	if o.scheduler != nil {
+		runner = scheduled(runner, member.Name, o.scheduler)
+	}
+	return runner
+}
+
+/*
+stopSignals records the signals with which a group stops its members, as
+opposed to those it merely forwards to them, such as by SignalMember.
+*/
+type stopSignals struct {
+	lock    sync.Mutex
+	signals []os.Signal
+}
+
+func newStopSignals(signals ...os.Signal) *stopSignals {
+	return &stopSignals{signals: signals}
+}
+
+func (s *stopSignals) add(signal os.Signal) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.signals = append(s.signals, signal)
+}
+
+/*
+stops reports whether signal is one of the stop signals, once translated by
+translations.
+*/
+func (s *stopSignals) stops(signal os.Signal, translations map[os.Signal]os.Signal) bool {
+	if s == nil {
+		return true
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for _, stop := range s.signals {
+		if translated, found := translations[stop]; found {
+			stop = translated
+		}
+		if stop == signal {
+			return true
+		}
+	}
+	return false
+}
+
+func (m MemberOptions) withShutdownTimeout(name string, stops *stopSignals, runner ifrit.Runner) ifrit.Runner {
+	return ifrit.RunFunc(func(signals <-chan os.Signal, ready chan<- struct{}) error {
+		innerSignals := make(chan os.Signal)
+		errs := make(chan error, 1)
+		exited := make(chan struct{})
+		go func() {
+			errs <- runner.Run(innerSignals, ready)
+			close(exited)
+		}()
+
+		// signals are forwarded without blocking, so that a member which has
+		// stopped receiving them can still be abandoned
+		forward := signalqueue.Forward(innerSignals, exited).Send
+
+		var timeout <-chan time.Time
+		for {
+			select {
+			case err := <-errs:
+				return err
+
+			case signal := <-signals:
+				forward(signal)
+				if timeout == nil && stops.stops(signal, m.Signals) {
+					timer := time.NewTimer(m.ShutdownTimeout)
+					defer timer.Stop()
+					timeout = timer.C
+				}
+
+			case <-timeout:
+				if m.KillSignal != nil {
+					forward(m.KillSignal)
+				}
+				return ErrShutdownTimeout{Member: name, Timeout: m.ShutdownTimeout}
+			}
+		}
+	})
+}
+
+func (m MemberOptions) withStartRetries(stops *stopSignals, runner ifrit.Runner) ifrit.Runner {
+	return ifrit.RunFunc(func(signals <-chan os.Signal, ready chan<- struct{}) error {
+		stopping := func(signal os.Signal) bool {
+			return stops.stops(signal, m.Signals)
+		}
+
+		backoff := m.StartBackoff
+		for retries := 0; ; retries++ {
+			retry, err := startAttempt(runner, stopping, signals, ready)
+			if !retry || retries == m.StartRetries {
+				return err
+			}
+
+			timer := time.NewTimer(backoff)
+		wait:
+			for {
+				select {
+				case <-timer.C:
+					break wait
+				case signal := <-signals:
+					if stopping(signal) {
+						timer.Stop()
+						return err
+					}
+				}
+			}
+			backoff *= 2
+		}
+	})
+}
+
+/*
+startAttempt runs the runner once, and reports whether it failed before it was
+ready, without having been signaled to stop.
+*/
+func startAttempt(runner ifrit.Runner, stopping func(os.Signal) bool, signals <-chan os.Signal, ready chan<- struct{}) (bool, error) {
+	innerSignals := make(chan os.Signal)
+	innerReady := make(chan struct{})
+	errs := make(chan error, 1)
+	go func() {
+		errs <- runner.Run(innerSignals, innerReady)
+	}()
+
+	stopped := false
+	for {
+		select {
+		case <-innerReady:
+			close(ready)
+			innerReady = nil
+
+		case err := <-errs:
+			if innerReady != nil {
+				select {
+				case <-innerReady:
+					close(ready)
+					return false, err
+				default:
+				}
+			}
+			return err != nil && !stopped && innerReady != nil, err
+
+		case signal := <-signals:
+			if stopping(signal) {
+				stopped = true
+			}
+			select {
+			case innerSignals <- signal:
+			case err := <-errs:
+				// handled as any other exit on the next pass
+				errs <- err
+			}
+		}
+	}
+}
+
+func translateSignals(runner ifrit.Runner, translations map[os.Signal]os.Signal) ifrit.Runner {
+	return ifrit.RunFunc(func(signals <-chan os.Signal, ready chan<- struct{}) error {
+		innerSignals := make(chan os.Signal)
+		errs := make(chan error, 1)
+		go func() {
+			errs <- runner.Run(innerSignals, ready)
+		}()
+
+		for {
+			select {
+			case err := <-errs:
+				return err
+
+			case signal := <-signals:
+				if translated, found := translations[signal]; found {
+					signal = translated
+				}
+				select {
+				case innerSignals <- signal:
+				case err := <-errs:
+					return err
+				}
+			}
+		}
+	})
+}
+
 /*
 Members are treated as an ordered list. Member names must be unique.
 */
 type Members []Member
 
+/*
+InsertBefore returns a copy of the list with members inserted before the member
+named name, so that group builders can slot members, such as middleware, into
+an ordered list.  It returns ErrMemberNotFound if there is no such member.
+*/
+func (m Members) InsertBefore(name string, members ...Member) (Members, error) {
+	for i, member := range m {
+		if member.Name == name {
+			return m.insertAt(i, members), nil
+		}
+	}
+	return nil, ErrMemberNotFound{Name: name}
+}
+
+/*
+InsertAfter returns a copy of the list with members inserted after the member
+named name.  It returns ErrMemberNotFound if there is no such member.
+*/
+func (m Members) InsertAfter(name string, members ...Member) (Members, error) {
+	for i, member := range m {
+		if member.Name == name {
+			return m.insertAt(i+1, members), nil
+		}
+	}
+	return nil, ErrMemberNotFound{Name: name}
+}
+
+func (m Members) insertAt(i int, members Members) Members {
+	inserted := make(Members, 0, len(m)+len(members))
+	inserted = append(inserted, m[:i]...)
+	inserted = append(inserted, members...)
+	return append(inserted, m[i:]...)
+}
+
 /*
 Validate checks that all member names in the list are unique. It returns an
 error of type ErrDuplicateNames if duplicates are detected.
@@ -69,3 +425,16 @@ func (e ErrDuplicateNames) Error() string {
 
 	return msg
 }
+
+/*
+ErrShutdownTimeout is recorded as the exit error of a member which did not exit
+within its ShutdownTimeout of being signaled.
+*/
+type ErrShutdownTimeout struct {
+	Member  string
+	Timeout time.Duration
+}
+
+func (e ErrShutdownTimeout) Error() string {
+	return fmt.Sprintf("member %s did not exit within %s of being signaled", e.Member, e.Timeout)
+}
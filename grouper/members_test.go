@@ -1,6 +1,15 @@
 package grouper_test
 
 import (
+	"errors"
+	"os"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/tedsuo/ifrit"
+	"github.com/tedsuo/ifrit/fake_runner"
+	"github.com/tedsuo/ifrit/ginkgomon"
 	"github.com/tedsuo/ifrit/grouper"
 
 	. "github.com/onsi/ginkgo"
@@ -41,4 +50,479 @@ var _ = Describe("Members", func() {
 			}
 		})
 	})
+
+	Describe("InsertBefore and InsertAfter", func() {
+		var members grouper.Members
+
+		names := func(members grouper.Members) []string {
+			names := []string{}
+			for _, member := range members {
+				names = append(names, member.Name)
+			}
+			return names
+		}
+
+		BeforeEach(func() {
+			members = grouper.Members{{Name: "db"}, {Name: "app"}}
+		})
+
+		It("inserts members before the named member, leaving the list unchanged", func() {
+			inserted, err := members.InsertBefore("app", grouper.Member{Name: "auth"}, grouper.Member{Name: "cache"})
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(names(inserted)).Should(Equal([]string{"db", "auth", "cache", "app"}))
+			Ω(names(members)).Should(Equal([]string{"db", "app"}))
+		})
+
+		It("inserts members after the named member", func() {
+			inserted, err := members.InsertAfter("app", grouper.Member{Name: "metrics"})
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(names(inserted)).Should(Equal([]string{"db", "app", "metrics"}))
+
+			inserted, err = members.InsertAfter("db", grouper.Member{Name: "migrate"})
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(names(inserted)).Should(Equal([]string{"db", "migrate", "app"}))
+		})
+
+		It("returns ErrMemberNotFound for an unknown member", func() {
+			_, err := members.InsertBefore("blah", grouper.Member{Name: "auth"})
+			Ω(err).Should(Equal(grouper.ErrMemberNotFound{Name: "blah"}))
+
+			_, err = members.InsertAfter("blah", grouper.Member{Name: "auth"})
+			Ω(err).Should(Equal(grouper.ErrMemberNotFound{Name: "blah"}))
+		})
+	})
+
+	Describe("WithMemberOptions", func() {
+		var members grouper.Members
+
+		BeforeEach(func() {
+			members = grouper.Members{
+				{Name: "web", Runner: fake_runner.NewTestRunner()},
+				{Name: "api", Runner: fake_runner.NewTestRunner()},
+			}
+		})
+
+		It("fails a group which has no member with the given name", func() {
+			optional := grouper.WithMemberOptions("wbe", grouper.MemberOptions{Optional: true})
+			expected := grouper.ErrUnknownMemberOptions{Member: "wbe"}
+
+			for _, group := range []ifrit.Runner{
+				grouper.NewOrdered(os.Interrupt, members, optional),
+				grouper.NewParallel(os.Interrupt, members, optional),
+				grouper.NewGraph(os.Interrupt, members, grouper.Dependencies{}, optional),
+				grouper.NewSupervisor(os.Interrupt, members, optional),
+			} {
+				process := ifrit.Background(group)
+				Eventually(process.Wait()).Should(Receive(Equal(expected)))
+			}
+			Ω(expected.Error()).Should(Equal("options given for unknown member wbe"))
+		})
+	})
+
+	Describe("ShutdownTimeout", func() {
+		var (
+			groupProcess ifrit.Process
+			stuck        *fake_runner.TestRunner
+			steady       *fake_runner.TestRunner
+			members      grouper.Members
+		)
+
+		BeforeEach(func() {
+			stuck = fake_runner.NewTestRunner()
+			steady = fake_runner.NewTestRunner()
+			members = grouper.Members{
+				{Name: "steady", Runner: steady},
+				{Name: "stuck", Runner: stuck},
+			}
+
+			groupProcess = ifrit.Background(grouper.NewOrdered(os.Interrupt, members, grouper.WithMemberOptions("stuck", grouper.MemberOptions{
+				ShutdownTimeout: 50 * time.Millisecond,
+				KillSignal:      os.Kill,
+			})))
+			steady.WaitForCall()
+			steady.TriggerReady()
+			stuck.WaitForCall()
+			stuck.TriggerReady()
+			Eventually(groupProcess.Ready()).Should(BeClosed())
+		})
+
+		AfterEach(func() {
+			stuck.EnsureExit()
+			steady.EnsureExit()
+			ginkgomon.Kill(groupProcess)
+		})
+
+		It("abandons a member which outlives its timeout, and stops the rest", func() {
+			stuckSignals := stuck.WaitForCall()
+			steadySignals := steady.WaitForCall()
+
+			groupProcess.Signal(syscall.SIGTERM)
+			Eventually(stuckSignals).Should(Receive(Equal(syscall.SIGTERM)))
+			Consistently(steadySignals, 30*time.Millisecond).ShouldNot(Receive())
+
+			Eventually(stuckSignals).Should(Receive(Equal(os.Kill)))
+			Eventually(steadySignals).Should(Receive(Equal(syscall.SIGTERM)))
+			steady.TriggerExit(nil)
+
+			var err error
+			Eventually(groupProcess.Wait()).Should(Receive(&err))
+			Ω(untimed(err)).Should(Equal(grouper.ErrorTrace{
+				{Member: members[1], Err: grouper.ErrShutdownTimeout{Member: "stuck", Timeout: 50 * time.Millisecond}},
+				{Member: members[0], Err: nil},
+			}))
+		})
+
+		It("records the member's own exit when it exits in time", func() {
+			stuckSignals := stuck.WaitForCall()
+
+			groupProcess.Signal(syscall.SIGTERM)
+			Eventually(stuckSignals).Should(Receive(Equal(syscall.SIGTERM)))
+			stuck.TriggerExit(nil)
+			steady.TriggerExit(nil)
+
+			Eventually(groupProcess.Wait()).Should(Receive(BeNil()))
+		})
+	})
+
+	Describe("ShutdownTimeout in a dynamic group", func() {
+		var (
+			client       grouper.DynamicClient
+			poolProcess  ifrit.Process
+			stuck        *fake_runner.TestRunner
+			stuckSignals <-chan os.Signal
+		)
+
+		BeforeEach(func() {
+			stuck = fake_runner.NewTestRunner()
+			pool := grouper.NewDynamic(syscall.SIGTERM, 1, 1, grouper.WithMemberOptions("stuck", grouper.MemberOptions{
+				ShutdownTimeout: 20 * time.Millisecond,
+				KillSignal:      os.Kill,
+			}))
+			client = pool.Client()
+			poolProcess = ifrit.Invoke(pool)
+
+			Eventually(client.Inserter()).Should(BeSent(grouper.Member{Name: "stuck", Runner: stuck}))
+			stuckSignals = stuck.WaitForCall()
+		})
+
+		AfterEach(func() {
+			stuck.EnsureExit()
+			ginkgomon.Kill(poolProcess)
+		})
+
+		It("only starts the timeout for the group's stop signal", func() {
+			Ω(client.SignalMember("stuck", syscall.SIGHUP)).Should(Succeed())
+			Eventually(stuckSignals).Should(Receive(Equal(syscall.SIGHUP)))
+			Consistently(poolProcess.Wait(), 60*time.Millisecond).ShouldNot(Receive())
+
+			poolProcess.Signal(syscall.SIGTERM)
+			Eventually(stuckSignals).Should(Receive(Equal(syscall.SIGTERM)))
+			Eventually(stuckSignals).Should(Receive(Equal(os.Kill)))
+			Eventually(poolProcess.Wait()).Should(Receive())
+		})
+	})
+
+	Describe("StartRetries in a dynamic group", func() {
+		var (
+			client      grouper.DynamicClient
+			poolProcess ifrit.Process
+			runs        int32
+			signaled    chan os.Signal
+		)
+
+		BeforeEach(func() {
+			runs = 0
+			signaled = make(chan os.Signal, 10)
+			flaky := ifrit.RunFunc(func(signals <-chan os.Signal, ready chan<- struct{}) error {
+				if atomic.AddInt32(&runs, 1) > 1 {
+					close(ready)
+				}
+				signal := <-signals
+				signaled <- signal
+				return errors.New("Fail")
+			})
+
+			pool := grouper.NewDynamic(syscall.SIGTERM, 1, 1, grouper.WithMemberOptions("flaky", grouper.MemberOptions{
+				StartRetries: 1,
+			}))
+			client = pool.Client()
+			poolProcess = ifrit.Invoke(pool)
+			Eventually(client.Inserter()).Should(BeSent(grouper.Member{Name: "flaky", Runner: flaky}))
+		})
+
+		AfterEach(func() {
+			ginkgomon.Kill(poolProcess)
+		})
+
+		It("retries a member which fails after a signal other than the group's stop signal", func() {
+			Eventually(func() error { return client.SignalMember("flaky", syscall.SIGHUP) }).Should(Succeed())
+			Eventually(signaled).Should(Receive(Equal(syscall.SIGHUP)))
+			Eventually(func() int32 { return atomic.LoadInt32(&runs) }).Should(Equal(int32(2)))
+			Consistently(poolProcess.Wait()).ShouldNot(Receive())
+		})
+	})
+
+	Describe("Signals", func() {
+		var (
+			groupProcess ifrit.Process
+			quitter      *fake_runner.TestRunner
+			terminator   *fake_runner.TestRunner
+		)
+
+		BeforeEach(func() {
+			quitter = fake_runner.NewTestRunner()
+			terminator = fake_runner.NewTestRunner()
+			members := grouper.Members{
+				{Name: "quitter", Runner: quitter},
+				{Name: "terminator", Runner: terminator},
+			}
+
+			groupProcess = ifrit.Background(grouper.NewParallel(os.Interrupt, members, grouper.WithMemberOptions("quitter", grouper.MemberOptions{
+				Signals: map[os.Signal]os.Signal{os.Interrupt: syscall.SIGQUIT},
+			})))
+			quitter.WaitForCall()
+			quitter.TriggerReady()
+			terminator.WaitForCall()
+			terminator.TriggerReady()
+			Eventually(groupProcess.Ready()).Should(BeClosed())
+		})
+
+		AfterEach(func() {
+			quitter.EnsureExit()
+			terminator.EnsureExit()
+			ginkgomon.Kill(groupProcess)
+		})
+
+		It("delivers each member the signal it maps the group's signal to", func() {
+			quitterSignals := quitter.WaitForCall()
+			terminatorSignals := terminator.WaitForCall()
+
+			groupProcess.Signal(os.Interrupt)
+			Eventually(quitterSignals).Should(Receive(Equal(syscall.SIGQUIT)))
+			Eventually(terminatorSignals).Should(Receive(Equal(os.Interrupt)))
+
+			groupProcess.Signal(syscall.SIGUSR2)
+			Eventually(quitterSignals).Should(Receive(Equal(syscall.SIGUSR2)))
+		})
+	})
+
+	Describe("StartRetries", func() {
+		var (
+			groupProcess ifrit.Process
+			flaky        *gatedRunner
+			steady       *fake_runner.TestRunner
+			members      grouper.Members
+		)
+
+		BeforeEach(func() {
+			flaky = newGatedRunner()
+			steady = fake_runner.NewTestRunner()
+			members = grouper.Members{
+				{Name: "flaky", Runner: flaky},
+				{Name: "steady", Runner: steady},
+			}
+
+			groupProcess = ifrit.Background(grouper.NewOrdered(os.Interrupt, members, grouper.WithMemberOptions("flaky", grouper.MemberOptions{
+				StartRetries: 2,
+				StartBackoff: 20 * time.Millisecond,
+			})))
+		})
+
+		AfterEach(func() {
+			steady.EnsureExit()
+			ginkgomon.Kill(groupProcess)
+		})
+
+		It("runs a member which fails to start again, after a backoff", func() {
+			Eventually(flaky.Runs).Should(Equal(1))
+			flaky.exits <- errors.New("Fail")
+			Consistently(flaky.Runs, 10*time.Millisecond).Should(Equal(1))
+			Eventually(flaky.Runs).Should(Equal(2))
+
+			flaky.ready <- struct{}{}
+			steady.WaitForCall()
+			steady.TriggerReady()
+			Eventually(groupProcess.Ready()).Should(BeClosed())
+		})
+
+		It("fails the group once the member has used up its retries", func() {
+			for runs := 1; runs <= 3; runs++ {
+				Eventually(flaky.Runs).Should(Equal(runs))
+				flaky.exits <- errors.New("Fail")
+			}
+
+			var err error
+			Eventually(groupProcess.Wait()).Should(Receive(&err))
+			Ω(untimed(err)).Should(Equal(grouper.ErrorTrace{
+				{Member: members[0], Err: errors.New("Fail")},
+			}))
+			Ω(flaky.Runs()).Should(Equal(3))
+			Ω(steady.RunCallCount()).Should(Equal(0))
+		})
+
+		It("does not retry a member which exits once it is ready", func() {
+			flaky.ready <- struct{}{}
+			steady.WaitForCall()
+			steady.TriggerReady()
+			Eventually(groupProcess.Ready()).Should(BeClosed())
+
+			flaky.exits <- errors.New("Fail")
+			steady.TriggerExit(nil)
+			Eventually(groupProcess.Wait()).Should(Receive(HaveOccurred()))
+			Ω(flaky.Runs()).Should(Equal(1))
+		})
+
+		It("does not retry a member which is signaled while starting", func() {
+			Eventually(flaky.Runs).Should(Equal(1))
+			groupProcess.Signal(syscall.SIGTERM)
+			Eventually(flaky.signals).Should(Receive(Equal(syscall.SIGTERM)))
+
+			Eventually(groupProcess.Wait()).Should(Receive(BeNil()))
+			Ω(flaky.Runs()).Should(Equal(1))
+		})
+	})
+
+	Describe("RecoverPanics", func() {
+		var (
+			groupProcess ifrit.Process
+			steady       *fake_runner.TestRunner
+			panics       int32
+			panicky      ifrit.Runner
+		)
+
+		BeforeEach(func() {
+			steady = fake_runner.NewTestRunner()
+			atomic.StoreInt32(&panics, 0)
+			panicky = ifrit.RunFunc(func(signals <-chan os.Signal, ready chan<- struct{}) error {
+				atomic.AddInt32(&panics, 1)
+				panic("boom")
+			})
+		})
+
+		AfterEach(func() {
+			steady.EnsureExit()
+			ginkgomon.Kill(groupProcess)
+		})
+
+		It("records a panic as the member's exit, and stops the group", func() {
+			groupProcess = ifrit.Background(grouper.NewParallel(os.Interrupt, grouper.Members{
+				{Name: "panicky", Runner: panicky},
+				{Name: "steady", Runner: steady},
+			}, grouper.WithPanicRecovery()))
+
+			Eventually(steady.WaitForCall()).Should(Receive(Equal(os.Interrupt)))
+			steady.TriggerExit(nil)
+
+			var err error
+			Eventually(groupProcess.Wait()).Should(Receive(&err))
+
+			var panicErr ifrit.PanicError
+			Ω(errors.As(err, &panicErr)).Should(BeTrue())
+			Ω(panicErr.Value).Should(Equal("boom"))
+		})
+
+		It("lets a Supervisor restart a member which panics", func() {
+			groupProcess = ifrit.Background(grouper.NewSupervisor(os.Interrupt, grouper.Members{
+				{Name: "panicky", Runner: panicky},
+				{Name: "steady", Runner: steady},
+			}, grouper.WithMemberOptions("panicky", grouper.MemberOptions{
+				RecoverPanics: true,
+				Restart:       grouper.RestartPolicy{Mode: grouper.RestartOnFailure, MaxRestarts: 2},
+			})))
+
+			Eventually(steady.WaitForCall()).Should(Receive(Equal(os.Interrupt)))
+			steady.TriggerExit(nil)
+			Eventually(groupProcess.Wait()).Should(Receive(HaveOccurred()))
+			Ω(atomic.LoadInt32(&panics)).Should(BeEquivalentTo(3))
+		})
+	})
+
+	Describe("Optional", func() {
+		var (
+			groupProcess ifrit.Process
+			sidecar      *fake_runner.TestRunner
+			service      *fake_runner.TestRunner
+			members      grouper.Members
+
+			optional = grouper.WithMemberOptions("sidecar", grouper.MemberOptions{Optional: true})
+		)
+
+		BeforeEach(func() {
+			sidecar = fake_runner.NewTestRunner()
+			service = fake_runner.NewTestRunner()
+			members = grouper.Members{
+				{Name: "sidecar", Runner: sidecar},
+				{Name: "service", Runner: service},
+			}
+		})
+
+		AfterEach(func() {
+			sidecar.EnsureExit()
+			service.EnsureExit()
+			ginkgomon.Kill(groupProcess)
+		})
+
+		itKeepsRunning := func() {
+			It("keeps running when the optional member fails to start", func() {
+				sidecar.WaitForCall()
+				sidecar.TriggerExit(errors.New("Fail"))
+
+				serviceSignals := service.WaitForCall()
+				service.TriggerReady()
+				Eventually(groupProcess.Ready()).Should(BeClosed())
+				Consistently(serviceSignals).ShouldNot(Receive())
+
+				groupProcess.Signal(syscall.SIGTERM)
+				Eventually(serviceSignals).Should(Receive(Equal(syscall.SIGTERM)))
+				service.TriggerExit(nil)
+				Eventually(groupProcess.Wait()).Should(Receive(BeNil()))
+			})
+
+			It("keeps running when the optional member exits, and records its exit", func() {
+				sidecar.WaitForCall()
+				sidecar.TriggerReady()
+				serviceSignals := service.WaitForCall()
+				service.TriggerReady()
+				Eventually(groupProcess.Ready()).Should(BeClosed())
+
+				sidecar.TriggerExit(errors.New("Fail"))
+				Consistently(serviceSignals).ShouldNot(Receive())
+
+				service.TriggerExit(errors.New("Boom"))
+
+				var err error
+				Eventually(groupProcess.Wait()).Should(Receive(&err))
+				Ω(untimed(err)).Should(ConsistOf(
+					grouper.ExitEvent{Member: members[0], Err: errors.New("Fail")},
+					grouper.ExitEvent{Member: members[1], Err: errors.New("Boom")},
+				))
+			})
+		}
+
+		Context("in an ordered group", func() {
+			JustBeforeEach(func() {
+				groupProcess = ifrit.Background(grouper.NewOrdered(os.Interrupt, members, optional))
+			})
+
+			itKeepsRunning()
+		})
+
+		Context("in a parallel group", func() {
+			JustBeforeEach(func() {
+				groupProcess = ifrit.Background(grouper.NewParallel(os.Interrupt, members, optional))
+			})
+
+			itKeepsRunning()
+		})
+
+		Context("in a graph group", func() {
+			JustBeforeEach(func() {
+				groupProcess = ifrit.Background(grouper.NewGraph(os.Interrupt, members, grouper.Dependencies{
+					"service": {"sidecar"},
+				}, optional))
+			})
+
+			itKeepsRunning()
+		})
+	})
 })
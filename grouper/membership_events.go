@@ -0,0 +1,158 @@
+package grouper
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/tedsuo/ifrit"
+)
+
+/*
+A MembershipEventKind identifies what happened to a member of a dynamic group.
+*/
+type MembershipEventKind int
+
+const (
+	// MembershipEntered is emitted when a member is started.
+	MembershipEntered MembershipEventKind = iota
+	// MembershipReady is emitted when a member becomes ready.
+	MembershipReady
+	// MembershipExited is emitted when a member exits.
+	MembershipExited
+)
+
+func (k MembershipEventKind) String() string {
+	switch k {
+	case MembershipEntered:
+		return "entered"
+	case MembershipReady:
+		return "ready"
+	case MembershipExited:
+		return "exited"
+	default:
+		return fmt.Sprintf("MembershipEventKind(%d)", int(k))
+	}
+}
+
+/*
+A MembershipEvent occurs every time a member of a dynamic group is started,
+becomes ready, or exits.  Err is the member's exit error, for MembershipExited
+events.  Time is when the event occurred.
+
+Replayed events describe the membership of the group when the subscription was
+made, rather than something which has just happened; their Time is when the
+subscription was made.
+*/
+type MembershipEvent struct {
+	Kind     MembershipEventKind
+	Member   Member
+	Process  ifrit.Process
+	Err      error
+	Time     time.Time
+	Replayed bool
+}
+
+type eventsRequest struct {
+	Replay   bool
+	Response chan (<-chan MembershipEvent)
+}
+
+/*
+Replay describes each running process as the events which brought it to its
+current state, in the order they were started.
+*/
+func (g *processSet) Replay() []MembershipEvent {
+	processes := make([]ifrit.Process, 0, len(g.statuses))
+	for process := range g.statuses {
+		processes = append(processes, process)
+	}
+	sort.Slice(processes, func(i, j int) bool {
+		a, b := g.statuses[processes[i]], g.statuses[processes[j]]
+		if !a.StartTime.Equal(b.StartTime) {
+			return a.StartTime.Before(b.StartTime)
+		}
+		return a.Name < b.Name
+	})
+
+	now := time.Now()
+	events := make([]MembershipEvent, 0, 2*len(processes))
+	for _, process := range processes {
+		event := MembershipEvent{
+			Kind:     MembershipEntered,
+			Member:   g.members[process],
+			Process:  process,
+			Time:     now,
+			Replayed: true,
+		}
+		events = append(events, event)
+
+		if isReady(process) {
+			event.Kind = MembershipReady
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+func isReady(process ifrit.Process) bool {
+	select {
+	case <-process.Ready():
+		return true
+	default:
+		return false
+	}
+}
+
+type membershipEventBroadcaster struct {
+	channels   []chan MembershipEvent
+	bufferSize int
+	lock       *sync.Mutex
+}
+
+func newMembershipEventBroadcaster(bufferSize int) *membershipEventBroadcaster {
+	return &membershipEventBroadcaster{
+		channels:   make([]chan MembershipEvent, 0),
+		bufferSize: bufferSize,
+		lock:       new(sync.Mutex),
+	}
+}
+
+/*
+Attach provides a new channel, which begins with the replayed events.
+*/
+func (b *membershipEventBroadcaster) Attach(replay []MembershipEvent) chan MembershipEvent {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	channel := make(chan MembershipEvent, b.bufferSize+len(replay))
+	for _, event := range replay {
+		channel <- event
+	}
+	if b.channels != nil {
+		b.channels = append(b.channels, channel)
+	} else {
+		close(channel)
+	}
+	return channel
+}
+
+func (b *membershipEventBroadcaster) Broadcast(event MembershipEvent) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	for _, channel := range b.channels {
+		channel <- event
+	}
+}
+
+func (b *membershipEventBroadcaster) Close() {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	for _, channel := range b.channels {
+		close(channel)
+	}
+	b.channels = nil
+}
@@ -0,0 +1,123 @@
+package grouper_test
+
+import (
+	"errors"
+	"os"
+	"time"
+
+	"github.com/tedsuo/ifrit"
+	"github.com/tedsuo/ifrit/fake_runner"
+	"github.com/tedsuo/ifrit/grouper"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Membership events", func() {
+	var (
+		client       grouper.DynamicClient
+		poolProcess  ifrit.Process
+		childRunner1 *fake_runner.TestRunner
+		childRunner2 *fake_runner.TestRunner
+
+		Δ time.Duration = 10 * time.Millisecond
+	)
+
+	kinds := func(events <-chan grouper.MembershipEvent, count int) []string {
+		described := []string{}
+		for i := 0; i < count; i++ {
+			var event grouper.MembershipEvent
+			Eventually(events).Should(Receive(&event))
+			described = append(described, event.Member.Name+" "+event.Kind.String())
+		}
+		return described
+	}
+
+	BeforeEach(func() {
+		childRunner1 = fake_runner.NewTestRunner()
+		childRunner2 = fake_runner.NewTestRunner()
+
+		pool := grouper.NewDynamic(nil, 2, 10)
+		client = pool.Client()
+		poolProcess = ifrit.Invoke(pool)
+	})
+
+	AfterEach(func() {
+		poolProcess.Signal(os.Kill)
+		childRunner1.EnsureExit()
+		childRunner2.EnsureExit()
+		Eventually(poolProcess.Wait()).Should(Receive())
+	})
+
+	It("delivers an event as each member enters, becomes ready, and exits", func() {
+		events := client.Events(false)
+
+		Eventually(client.Inserter()).Should(BeSent(grouper.Member{Name: "child1", Runner: childRunner1}))
+		childRunner1.WaitForCall()
+		childRunner1.TriggerReady()
+		childRunner1.TriggerExit(errors.New("Fail"))
+
+		Ω(kinds(events, 2)).Should(Equal([]string{"child1 entered", "child1 ready"}))
+
+		var exit grouper.MembershipEvent
+		Eventually(events).Should(Receive(&exit))
+		Ω(exit.Kind).Should(Equal(grouper.MembershipExited))
+		Ω(exit.Err).Should(MatchError("Fail"))
+		Ω(exit.Replayed).Should(BeFalse())
+	})
+
+	It("does not deliver a ready event for a member which exits before it is ready", func() {
+		events := client.Events(false)
+
+		Eventually(client.Inserter()).Should(BeSent(grouper.Member{Name: "child1", Runner: childRunner1}))
+		childRunner1.WaitForCall()
+		childRunner1.TriggerExit(nil)
+
+		Ω(kinds(events, 2)).Should(Equal([]string{"child1 entered", "child1 exited"}))
+		Consistently(events, Δ).ShouldNot(Receive())
+	})
+
+	Context("when a subscriber attaches late", func() {
+		BeforeEach(func() {
+			Eventually(client.Inserter()).Should(BeSent(grouper.Member{Name: "child1", Runner: childRunner1}))
+			childRunner1.WaitForCall()
+			childRunner1.TriggerReady()
+			Eventually(client.EntranceListener()).Should(Receive())
+
+			Eventually(client.Inserter()).Should(BeSent(grouper.Member{Name: "child2", Runner: childRunner2}))
+			childRunner2.WaitForCall()
+		})
+
+		It("replays the current membership, followed by later events", func() {
+			events := client.Events(true)
+
+			var replayed grouper.MembershipEvent
+			Eventually(events).Should(Receive(&replayed))
+			Ω(replayed.Replayed).Should(BeTrue())
+			Ω(replayed.Member.Name).Should(Equal("child1"))
+			Ω(kinds(events, 2)).Should(Equal([]string{"child1 ready", "child2 entered"}))
+
+			childRunner2.TriggerReady()
+			Ω(kinds(events, 1)).Should(Equal([]string{"child2 ready"}))
+		})
+
+		It("does not replay without being asked to", func() {
+			events := client.Events(false)
+			Consistently(events, Δ).ShouldNot(Receive())
+		})
+	})
+
+	It("provides a closed channel once the group has exited", func() {
+		poolProcess.Signal(os.Kill)
+		Eventually(poolProcess.Wait()).Should(Receive())
+
+		Eventually(client.Events(true)).Should(BeClosed())
+	})
+})
+
+var _ = Describe("MembershipEventKind", func() {
+	It("names each kind", func() {
+		Ω(grouper.MembershipReady.String()).Should(Equal("ready"))
+		Ω(grouper.MembershipEventKind(7).String()).Should(Equal("MembershipEventKind(7)"))
+	})
+})
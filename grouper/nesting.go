@@ -0,0 +1,111 @@
+package grouper
+
+import (
+	"errors"
+	"strings"
+)
+
+/*
+NameSeparator joins the names of nested members into qualified names, such as
+"api/http" for the member "http" of a group run as the member "api".
+*/
+const NameSeparator = "/"
+
+/*
+QualifiedName joins the names of a member and the groups it is nested in,
+outermost first.
+*/
+func QualifiedName(names ...string) string {
+	return strings.Join(names, NameSeparator)
+}
+
+/*
+Flatten returns the trace with the exit of each member which ran a nested group
+replaced by the exits of that group's members, recursively.  The nested exits
+are named with their qualified names, so that it is clear which group each
+member belonged to.  Exits which do not wrap an ErrorTrace are kept as they
+are.
+*/
+func (trace ErrorTrace) Flatten() ErrorTrace {
+	flattened := ErrorTrace{}
+	for _, exit := range trace {
+		var nested ErrorTrace
+		if exit.Err == nil || !errors.As(exit.Err, &nested) || len(nested) == 0 {
+			flattened = append(flattened, exit)
+			continue
+		}
+
+		for _, nestedExit := range nested.Flatten() {
+			nestedExit.Member.Name = QualifiedName(exit.Member.Name, nestedExit.Member.Name)
+			flattened = append(flattened, nestedExit)
+		}
+	}
+	return flattened
+}
+
+/*
+nestedGroup is implemented by the static groups and Supervisors, so that their
+members can be found when they are nested within another group.
+*/
+type nestedGroup interface {
+	nestedMembers() Members
+	nestedOptions() groupOptions
+}
+
+func (g *orderedGroup) nestedMembers() Members { return g.members }
+func (g parallelGroup) nestedMembers() Members { return g.members }
+func (g *group) nestedMembers() Members        { return g.members }
+func (s *Supervisor) nestedMembers() Members   { return s.group.members }
+
+func (g *orderedGroup) nestedOptions() groupOptions { return g.options }
+func (g parallelGroup) nestedOptions() groupOptions { return g.options }
+func (g *group) nestedOptions() groupOptions        { return g.options }
+func (s *Supervisor) nestedOptions() groupOptions   { return s.group.options }
+
+/*
+Flatten returns the members with each member which runs a nested group
+replaced by that group's members, recursively, for inspection.  The nested
+members are named with their qualified names.  Dynamic groups, whose members
+change as they run, are kept as they are.
+*/
+func (m Members) Flatten() Members {
+	flattened := Members{}
+	for _, member := range m {
+		nested, ok := member.Runner.(nestedGroup)
+		if !ok {
+			flattened = append(flattened, member)
+			continue
+		}
+
+		for _, nestedMember := range nested.nestedMembers().Flatten() {
+			nestedMember.Name = QualifiedName(member.Name, nestedMember.Name)
+			flattened = append(flattened, nestedMember)
+		}
+	}
+	return flattened
+}
+
+/*
+FlattenOptions returns the options the nested groups among the members give
+their members, keyed by the qualified names Flatten gives those members.
+*/
+func (m Members) FlattenOptions() map[string]MemberOptions {
+	flattened := map[string]MemberOptions{}
+	for _, member := range m {
+		nested, ok := member.Runner.(nestedGroup)
+		if !ok {
+			continue
+		}
+
+		options := nested.nestedOptions()
+		for _, nestedMember := range nested.nestedMembers() {
+			if _, ok := nestedMember.Runner.(nestedGroup); !ok {
+				flattened[QualifiedName(member.Name, nestedMember.Name)] = options.member(nestedMember.Name)
+			}
+		}
+		for name, nestedOptions := range nested.nestedMembers().FlattenOptions() {
+			flattened[QualifiedName(member.Name, name)] = nestedOptions
+		}
+	}
+	return flattened
+}
@@ -0,0 +1,97 @@
+package grouper_test
+
+import (
+	"errors"
+	"os"
+
+	"github.com/tedsuo/ifrit"
+	"github.com/tedsuo/ifrit/fake_runner"
+	"github.com/tedsuo/ifrit/ginkgomon"
+	"github.com/tedsuo/ifrit/grouper"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Nested groups", func() {
+	var (
+		http   *fake_runner.TestRunner
+		grpc   *fake_runner.TestRunner
+		db     *fake_runner.TestRunner
+		api    ifrit.Runner
+		groups grouper.Members
+	)
+
+	BeforeEach(func() {
+		http = fake_runner.NewTestRunner()
+		grpc = fake_runner.NewTestRunner()
+		db = fake_runner.NewTestRunner()
+
+		api = grouper.NewParallel(os.Interrupt, grouper.Members{
+			{Name: "http", Runner: http},
+			{Name: "grpc", Runner: grpc},
+		})
+		groups = grouper.Members{
+			{Name: "db", Runner: db},
+			{Name: "api", Runner: api},
+		}
+	})
+
+	It("joins qualified names with the separator", func() {
+		Ω(grouper.QualifiedName("api", "http")).Should(Equal("api/http"))
+	})
+
+	Describe("Members.Flatten", func() {
+		It("replaces nested groups with their members, named by their qualified names", func() {
+			flattened := grouper.Members{{Name: "app", Runner: grouper.NewOrdered(os.Interrupt, groups)}}.Flatten()
+
+			names := []string{}
+			for _, member := range flattened {
+				names = append(names, member.Name)
+			}
+			Ω(names).Should(Equal([]string{"app/db", "app/api/http", "app/api/grpc"}))
+			Ω(flattened[0].Runner).Should(Equal(db))
+		})
+	})
+
+	Describe("ErrorTrace.Flatten", func() {
+		var groupProcess ifrit.Process
+
+		BeforeEach(func() {
+			groupProcess = ifrit.Background(grouper.NewOrdered(os.Interrupt, groups))
+			dbSignals := db.WaitForCall()
+			db.TriggerReady()
+			http.WaitForCall()
+			grpcSignals := grpc.WaitForCall()
+
+			http.TriggerExit(errors.New("Fail"))
+			Eventually(grpcSignals).Should(Receive(Equal(os.Interrupt)))
+			grpc.TriggerExit(nil)
+			Eventually(dbSignals).Should(Receive(Equal(os.Interrupt)))
+			db.TriggerExit(nil)
+		})
+
+		AfterEach(func() {
+			ginkgomon.Kill(groupProcess)
+		})
+
+		It("replaces the exits of nested groups with their members' exits, named by their qualified names", func() {
+			var err error
+			Eventually(groupProcess.Wait()).Should(Receive(&err))
+
+			flattened := untimed(err.(grouper.ErrorTrace).Flatten())
+			Ω(flattened).Should(Equal(grouper.ErrorTrace{
+				{Member: grouper.Member{Name: "api/http", Runner: http}, Err: errors.New("Fail")},
+				{Member: grouper.Member{Name: "api/grpc", Runner: grpc}, Err: nil},
+				{Member: groups[0], Err: nil},
+			}))
+		})
+
+		It("describes nested exits by their qualified names", func() {
+			var err error
+			Eventually(groupProcess.Wait()).Should(Receive(&err))
+
+			Ω(err.Error()).Should(ContainSubstring("api/http exited with error: Fail\n"))
+		})
+	})
+})
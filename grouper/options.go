@@ -0,0 +1,132 @@
+package grouper
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+/*
+An Option configures an ordered, parallel or dynamic group.  Each group ignores
+the options which do not apply to it.
+*/
+type Option func(*groupOptions)
+
+type groupOptions struct {
+	readyTimeout        time.Duration
+	startConcurrency    int
+	readyQuorum         int
+	failureTolerance    int
+	recoverPanics       bool
+	shutdownOrder       ShutdownOrder
+	shutdownConcurrency int
+	crashLoopExits      int
+	crashLoopWindow     time.Duration
+	ctx                 context.Context
+	scheduler           Scheduler
+	memberOptions       map[string]MemberOptions
+}
+
+func newGroupOptions(shutdownOrder ShutdownOrder, opts []Option) groupOptions {
+	options := groupOptions{shutdownOrder: shutdownOrder}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return options
+}
+
+/*
+WithReadyTimeout bounds how long the group waits for each member to become
+ready.  A member which is not ready in time is treated as though it had exited:
+the group shuts down, and the member's exit is recorded with an ErrReadyTimeout.
+An ordered group instead stops an optional member which is not ready in time,
+and carries on starting the rest.
+
+An ordered group times each member from when it is started; a parallel group
+times all of its members together.  A zero timeout waits forever.
+*/
+func WithReadyTimeout(timeout time.Duration) Option {
+	return func(o *groupOptions) {
+		o.readyTimeout = timeout
+	}
+}
+
+/*
+WithStartConcurrency bounds how many members a parallel group starts at once.
+Members are started in member order as earlier ones become ready, so that a
+large group does not start everything at the same moment.  A limit of zero
+starts every member at once.  Ordered groups already start one member at a
+time, and ignore it.
+
+The group still fails as a whole if a member exits before it is ready, and a
+ready timeout still bounds the start of the whole group.
+*/
+func WithStartConcurrency(limit int) Option {
+	return func(o *groupOptions) {
+		o.startConcurrency = limit
+	}
+}
+
+/*
+WithPanicRecovery sets RecoverPanics in the options of every member of an
+ordered, parallel or dynamic group, so that a member which panics is recorded
+as having exited with an ifrit.PanicError, and the group applies its usual
+failure handling.
+*/
+func WithPanicRecovery() Option {
+	return func(o *groupOptions) {
+		o.recoverPanics = true
+	}
+}
+
+/*
+member returns the options of the named member, with those which are set on
+every member of the group applied.
+*/
+func (o groupOptions) member(name string) MemberOptions {
+	options := o.memberOptions[name]
+	if o.recoverPanics {
+		options.RecoverPanics = true
+	}
+	return options
+}
+
+/*
+errorOrNil returns the trace if any member which is not optional exited with an
+error, and nil otherwise.
+*/
+func (o groupOptions) errorOrNil(trace ErrorTrace) error {
+	for _, exit := range trace {
+		if exit.Err != nil && !o.member(exit.Member.Name).Optional {
+			return trace
+		}
+	}
+	return nil
+}
+
+func (o groupOptions) readyTimer() (<-chan time.Time, func()) {
+	if o.readyTimeout <= 0 {
+		return nil, func() {}
+	}
+	timer := time.NewTimer(o.readyTimeout)
+	return timer.C, func() { timer.Stop() }
+}
+
+/*
+ErrReadyTimeout is recorded as the exit error of a member which did not become
+ready within the group's ready timeout.  Err is the error the member exited with
+once it was stopped.
+*/
+type ErrReadyTimeout struct {
+	Member  string
+	Timeout time.Duration
+	Err     error
+}
+
+func (e ErrReadyTimeout) Error() string {
+	return fmt.Sprintf("member %s did not become ready within %s", e.Member, e.Timeout)
+}
+
+func (e ErrReadyTimeout) Unwrap() error {
+	return e.Err
+}
@@ -3,6 +3,7 @@ package grouper
 import (
 	"os"
 	"reflect"
+	"time"
 
 	"github.com/tedsuo/ifrit"
 )
@@ -13,11 +14,15 @@ becomes ready.  On shutdown, it will shut the started processes down in reverse
 Use an ordered group to describe a list of dependent processes, where each process
 depends upon the previous being available in order to function correctly.
 */
-func NewOrdered(terminationSignal os.Signal, members Members) ifrit.Runner {
+func NewOrdered(terminationSignal os.Signal, members Members, opts ...Option) ifrit.Runner {
+	options := newGroupOptions(ShutdownReverse, opts)
 	return &orderedGroup{
 		terminationSignal: terminationSignal,
 		pool:              make(map[string]ifrit.Process),
 		members:           members,
+		options:           options,
+		startTimes:        make(map[string]time.Time),
+		timedOut:          make(map[string]struct{}),
 	}
 }
 
@@ -25,6 +30,10 @@ type orderedGroup struct {
 	terminationSignal os.Signal
 	pool              map[string]ifrit.Process
 	members           Members
+	options           groupOptions
+	startTimes        map[string]time.Time
+	timedOut          map[string]struct{}
+	optionalExits     ErrorTrace
 }
 
 func (g *orderedGroup) Run(signals <-chan os.Signal, ready chan<- struct{}) error {
@@ -33,6 +42,11 @@ func (g *orderedGroup) Run(signals <-chan os.Signal, ready chan<- struct{}) erro
 		return err
 	}
 
+	signals, stopContext := g.options.contextSignals(signals, g.terminationSignal)
+	defer stopContext()
+
+	g.optionalExits = nil
+
 	signal, errTrace := g.orderedStart(signals)
 	if errTrace != nil {
 		return g.stop(g.terminationSignal, signals, errTrace)
@@ -49,59 +63,86 @@ func (g *orderedGroup) Run(signals <-chan os.Signal, ready chan<- struct{}) erro
 }
 
 func (g *orderedGroup) validate() error {
-	return g.members.Validate()
+	err := g.members.Validate()
+	if err != nil {
+		return err
+	}
+	return g.options.validateMembers(g.members)
 }
 
 func (g *orderedGroup) orderedStart(signals <-chan os.Signal) (os.Signal, ErrorTrace) {
-	for _, member := range g.members {
-		p := ifrit.Background(member)
-		cases := make([]reflect.SelectCase, 0, len(g.pool)+3)
-		for i := 0; i < len(g.pool); i++ {
+	for i, member := range g.members {
+		g.startTimes[member.Name] = time.Now()
+		p := ifrit.Background(g.options.runner(member, nil))
+		g.pool[member.Name] = p
+		timeout, stopTimer := g.options.readyTimer()
+
+	Started:
+		for {
+			cases := make([]reflect.SelectCase, 0, i+4)
+			for _, started := range g.members[:i] {
+				cases = append(cases, g.waitCase(started))
+			}
 			cases = append(cases, reflect.SelectCase{
 				Dir:  reflect.SelectRecv,
-				Chan: reflect.ValueOf(g.pool[g.members[i].Name].Wait()),
+				Chan: reflect.ValueOf(p.Ready()),
 			})
-		}
-		cases = append(cases, reflect.SelectCase{
-			Dir:  reflect.SelectRecv,
-			Chan: reflect.ValueOf(p.Ready()),
-		})
 
-		cases = append(cases, reflect.SelectCase{
-			Dir:  reflect.SelectRecv,
-			Chan: reflect.ValueOf(p.Wait()),
-		})
+			cases = append(cases, reflect.SelectCase{
+				Dir:  reflect.SelectRecv,
+				Chan: reflect.ValueOf(p.Wait()),
+			})
 
-		cases = append(cases, reflect.SelectCase{
-			Dir:  reflect.SelectRecv,
-			Chan: reflect.ValueOf(signals),
-		})
+			cases = append(cases, reflect.SelectCase{
+				Dir:  reflect.SelectRecv,
+				Chan: reflect.ValueOf(signals),
+			})
 
-		chosen, recv, _ := reflect.Select(cases)
-		g.pool[member.Name] = p
-		switch chosen {
-		case len(cases) - 1:
-			// signals
-			return recv.Interface().(os.Signal), nil
-		case len(cases) - 2:
-			// p.Wait
-			var err error
-			if !recv.IsNil() {
-				err = recv.Interface().(error)
-			}
-			return nil, ErrorTrace{
-				ExitEvent{Member: member, Err: err},
-			}
-		case len(cases) - 3:
-			// p.Ready
-		default:
-			// other member has exited
-			var err error = nil
-			if e := recv.Interface(); e != nil {
-				err = e.(error)
-			}
-			return nil, ErrorTrace{
-				ExitEvent{Member: g.members[chosen], Err: err},
+			cases = append(cases, reflect.SelectCase{
+				Dir:  reflect.SelectRecv,
+				Chan: reflect.ValueOf(timeout),
+			})
+
+			chosen, recv, _ := reflect.Select(cases)
+			switch chosen {
+			case len(cases) - 1:
+				// ready timeout
+				g.timedOut[member.Name] = struct{}{}
+				if g.options.member(member.Name).Optional {
+					p.Signal(g.stopSignal())
+					break Started
+				}
+				return nil, ErrorTrace{}
+			case len(cases) - 2:
+				// signals
+				stopTimer()
+				return recv.Interface().(os.Signal), nil
+			case len(cases) - 3:
+				// p.Wait
+				stopTimer()
+				err, _ := recv.Interface().(error)
+				if g.options.member(member.Name).Optional {
+					g.optionalExits = append(g.optionalExits, g.exitEvent(member, err))
+					break Started
+				}
+				return nil, ErrorTrace{
+					g.exitEvent(member, err),
+				}
+			case len(cases) - 4:
+				// p.Ready
+				stopTimer()
+				break Started
+			default:
+				// other member has exited
+				err, _ := recv.Interface().(error)
+				if g.options.member(g.members[chosen].Name).Optional {
+					g.optionalExits = append(g.optionalExits, g.exitEvent(g.members[chosen], err))
+					continue
+				}
+				stopTimer()
+				return nil, ErrorTrace{
+					g.exitEvent(g.members[chosen], err),
+				}
 			}
 		}
 	}
@@ -110,80 +151,85 @@ func (g *orderedGroup) orderedStart(signals <-chan os.Signal) (os.Signal, ErrorT
 }
 
 func (g *orderedGroup) waitForSignal(signals <-chan os.Signal, errTrace ErrorTrace) (os.Signal, ErrorTrace) {
-	cases := make([]reflect.SelectCase, 0, len(g.pool)+1)
-	for i := 0; i < len(g.pool); i++ {
+	for {
+		cases := make([]reflect.SelectCase, 0, len(g.pool)+1)
+		for _, member := range g.members {
+			cases = append(cases, g.waitCase(member))
+		}
 		cases = append(cases, reflect.SelectCase{
 			Dir:  reflect.SelectRecv,
-			Chan: reflect.ValueOf(g.pool[g.members[i].Name].Wait()),
+			Chan: reflect.ValueOf(signals),
 		})
-	}
-	cases = append(cases, reflect.SelectCase{
-		Dir:  reflect.SelectRecv,
-		Chan: reflect.ValueOf(signals),
-	})
 
-	chosen, recv, _ := reflect.Select(cases)
-	if chosen == len(cases)-1 {
-		return recv.Interface().(os.Signal), errTrace
-	}
+		chosen, recv, _ := reflect.Select(cases)
+		if chosen == len(cases)-1 {
+			return recv.Interface().(os.Signal), errTrace
+		}
+
+		err, _ := recv.Interface().(error)
+		if g.options.member(g.members[chosen].Name).Optional {
+			g.optionalExits = append(g.optionalExits, g.exitEvent(g.members[chosen], err))
+			continue
+		}
+
+		errTrace = append(errTrace, g.exitEvent(g.members[chosen], err))
 
-	var err error
-	if !recv.IsNil() {
-		err = recv.Interface().(error)
+		return g.terminationSignal, errTrace
 	}
+}
 
-	errTrace = append(errTrace, ExitEvent{
-		Member: g.members[chosen],
-		Err:    err,
-	})
+/*
+waitCase receives the exit of a started member, unless it is an optional
+member which has already exited.
+*/
+func (g *orderedGroup) waitCase(member Member) reflect.SelectCase {
+	for _, exit := range g.optionalExits {
+		if exit.Member.Name == member.Name {
+			return reflect.SelectCase{
+				Dir:  reflect.SelectRecv,
+				Chan: reflect.ValueOf((<-chan error)(nil)),
+			}
+		}
+	}
 
-	return g.terminationSignal, errTrace
+	return reflect.SelectCase{
+		Dir:  reflect.SelectRecv,
+		Chan: reflect.ValueOf(g.pool[member.Name].Wait()),
+	}
 }
 
 func (g *orderedGroup) stop(signal os.Signal, signals <-chan os.Signal, errTrace ErrorTrace) error {
-	errOccurred := false
+	errTrace = append(append(ErrorTrace{}, g.optionalExits...), errTrace...)
+
 	exited := map[string]struct{}{}
-	if len(errTrace) > 0 {
-		for _, exitEvent := range errTrace {
-			exited[exitEvent.Member.Name] = struct{}{}
-			if exitEvent.Err != nil {
-				errOccurred = true
-			}
-		}
+	for _, exitEvent := range errTrace {
+		exited[exitEvent.Member.Name] = struct{}{}
 	}
 
-	for i := len(g.pool) - 1; i >= 0; i-- {
-		m := g.members[i]
-		if _, found := exited[m.Name]; found {
-			continue
-		}
-		if p, ok := g.pool[m.Name]; ok {
-			p.Signal(signal)
-		Exited:
-			for {
-				select {
-				case err := <-p.Wait():
-					errTrace = append(errTrace, ExitEvent{
-						Member: m,
-						Err:    err,
-					})
-					if err != nil {
-						errOccurred = true
-					}
-					break Exited
-				case sig := <-signals:
-					if sig != signal {
-						signal = sig
-						p.Signal(signal)
-					}
-				}
-			}
+	started := make(Members, 0, len(g.pool))
+	for _, m := range g.members[:len(g.pool)] {
+		if _, found := exited[m.Name]; !found {
+			started = append(started, m)
 		}
 	}
 
-	if errOccurred {
-		return errTrace
+	errTrace = g.options.stopMembers(started, g.pool, signal, signals, errTrace, g.exitEvent)
+	return g.options.errorOrNil(errTrace)
+}
+
+/*
+stopSignal is sent to optional members which the group stops while it starts.
+*/
+func (g *orderedGroup) stopSignal() os.Signal {
+	if g.terminationSignal != nil {
+		return g.terminationSignal
 	}
+	return os.Interrupt
+}
 
-	return nil
+func (g *orderedGroup) exitEvent(member Member, err error) ExitEvent {
+	if _, found := g.timedOut[member.Name]; found {
+		err = ErrReadyTimeout{Member: member.Name, Timeout: g.options.readyTimeout, Err: err}
+	}
+	return newExitEvent(member, g.startTimes[member.Name], err)
 }
@@ -164,11 +164,11 @@ var _ = Describe("Ordered Group", func() {
 					It("returns an error indicating which child processes failed", func() {
 						var err error
 						Eventually(groupProcess.Wait()).Should(Receive(&err))
-						errTrace := err.(grouper.ErrorTrace)
+						errTrace := untimed(err)
 						Ω(errTrace).Should(HaveLen(3))
 
-						Ω(errTrace).Should(ContainElement(grouper.ExitEvent{grouper.Member{"child1", childRunner1}, nil}))
-						Ω(errTrace).Should(ContainElement(grouper.ExitEvent{grouper.Member{"child2", childRunner2}, errors.New("Fail")}))
+						Ω(errTrace).Should(ContainElement(grouper.ExitEvent{Member: grouper.Member{Name: "child1", Runner: childRunner1}, Err: nil}))
+						Ω(errTrace).Should(ContainElement(grouper.ExitEvent{Member: grouper.Member{Name: "child2", Runner: childRunner2}, Err: errors.New("Fail")}))
 					})
 				})
 			})
@@ -227,9 +227,9 @@ var _ = Describe("Ordered Group", func() {
 				var err error
 
 				Eventually(groupProcess.Wait()).Should(Receive(&err))
-				errTrace := err.(grouper.ErrorTrace)
-				Ω(errTrace).Should(ContainElement(grouper.ExitEvent{grouper.Member{"child1", childRunner1}, nil}))
-				Ω(errTrace).Should(ContainElement(grouper.ExitEvent{grouper.Member{"child2", childRunner2}, errors.New("Fail")}))
+				errTrace := untimed(err)
+				Ω(errTrace).Should(ContainElement(grouper.ExitEvent{Member: grouper.Member{Name: "child1", Runner: childRunner1}, Err: nil}))
+				Ω(errTrace).Should(ContainElement(grouper.ExitEvent{Member: grouper.Member{Name: "child2", Runner: childRunner2}, Err: errors.New("Fail")}))
 				Ω(exitIndex("child1", errTrace)).Should(BeNumerically(">", exitIndex("child2", errTrace)))
 			})
 		})
@@ -443,6 +443,127 @@ var _ = Describe("Ordered Group", func() {
 			})
 		})
 	})
+
+	Describe("WithReadyTimeout", func() {
+		BeforeEach(func() {
+			childRunner1 = fake_runner.NewTestRunner()
+			childRunner2 = fake_runner.NewTestRunner()
+			childRunner3 = fake_runner.NewTestRunner()
+
+			members = grouper.Members{
+				{Name: "child1", Runner: childRunner1},
+				{Name: "child2", Runner: childRunner2},
+				{Name: "child3", Runner: childRunner3},
+			}
+
+			groupRunner = grouper.NewOrdered(os.Interrupt, members, grouper.WithReadyTimeout(100*time.Millisecond))
+			groupProcess = ifrit.Background(groupRunner)
+		})
+
+		AfterEach(func() {
+			childRunner1.EnsureExit()
+			childRunner2.EnsureExit()
+			childRunner3.EnsureExit()
+
+			groupProcess.Signal(os.Kill)
+			Eventually(groupProcess.Wait()).Should(Receive())
+		})
+
+		It("times each member from when it is started", func() {
+			childRunner1.WaitForCall()
+			time.Sleep(60 * time.Millisecond)
+			childRunner1.TriggerReady()
+
+			childRunner2.WaitForCall()
+			time.Sleep(60 * time.Millisecond)
+			childRunner2.TriggerReady()
+
+			childRunner3.WaitForCall()
+			childRunner3.TriggerReady()
+			Eventually(groupProcess.Ready()).Should(BeClosed())
+		})
+
+		It("stops the started members when a member is not ready in time, naming the member", func() {
+			signal1 := childRunner1.WaitForCall()
+			childRunner1.TriggerReady()
+			signal2 := childRunner2.WaitForCall()
+
+			Eventually(signal2).Should(Receive(Equal(os.Interrupt)))
+			Consistently(signal1, Δ).ShouldNot(Receive())
+			childRunner2.TriggerExit(nil)
+
+			Eventually(signal1).Should(Receive(Equal(os.Interrupt)))
+			childRunner1.TriggerExit(nil)
+
+			var err error
+			Eventually(groupProcess.Wait()).Should(Receive(&err))
+			Ω(untimed(err)).Should(Equal(grouper.ErrorTrace{
+				grouper.ExitEvent{Member: grouper.Member{Name: "child2", Runner: childRunner2}, Err: grouper.ErrReadyTimeout{Member: "child2", Timeout: 100 * time.Millisecond}},
+				grouper.ExitEvent{Member: grouper.Member{Name: "child1", Runner: childRunner1}, Err: nil},
+			}))
+			Ω(childRunner3.RunCallCount()).Should(BeZero())
+		})
+	})
+
+	Describe("WithReadyTimeout and an optional member", func() {
+		BeforeEach(func() {
+			childRunner1 = fake_runner.NewTestRunner()
+			childRunner2 = fake_runner.NewTestRunner()
+
+			members = grouper.Members{
+				{Name: "child1", Runner: childRunner1},
+				{Name: "child2", Runner: childRunner2},
+			}
+
+			groupRunner = grouper.NewOrdered(os.Interrupt, members,
+				grouper.WithReadyTimeout(50*time.Millisecond),
+				grouper.WithMemberOptions("child1", grouper.MemberOptions{Optional: true}),
+			)
+			groupProcess = ifrit.Background(groupRunner)
+		})
+
+		AfterEach(func() {
+			childRunner1.EnsureExit()
+			childRunner2.EnsureExit()
+
+			groupProcess.Signal(os.Kill)
+			Eventually(groupProcess.Wait()).Should(Receive())
+		})
+
+		It("stops the optional member, records its timeout, and starts the rest", func() {
+			signal1 := childRunner1.WaitForCall()
+			Eventually(signal1).Should(Receive(Equal(os.Interrupt)))
+			childRunner1.TriggerExit(nil)
+
+			signal2 := childRunner2.WaitForCall()
+			childRunner2.TriggerReady()
+			Eventually(groupProcess.Ready()).Should(BeClosed())
+
+			groupProcess.Signal(os.Interrupt)
+			Eventually(signal2).Should(Receive(Equal(os.Interrupt)))
+			childRunner2.TriggerExit(nil)
+
+			var err error
+			Eventually(groupProcess.Wait()).Should(Receive(&err))
+			Ω(err).ShouldNot(HaveOccurred())
+		})
+
+		It("records the optional member's timeout if the group fails", func() {
+			signal1 := childRunner1.WaitForCall()
+			Eventually(signal1).Should(Receive(Equal(os.Interrupt)))
+			childRunner1.TriggerExit(nil)
+
+			childRunner2.WaitForCall()
+			childRunner2.TriggerExit(errors.New("Fail"))
+
+			var err error
+			Eventually(groupProcess.Wait()).Should(Receive(&err))
+			Ω(untimed(err)).Should(ConsistOf(
+				grouper.ExitEvent{Member: members[0], Err: grouper.ErrReadyTimeout{Member: "child1", Timeout: 50 * time.Millisecond}},
+				grouper.ExitEvent{Member: members[1], Err: errors.New("Fail")},
+			))
+		})
+	})
 })
 
 func exitIndex(name string, errTrace grouper.ErrorTrace) int {
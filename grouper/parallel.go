@@ -1,8 +1,10 @@
 package grouper
 
 import (
+	"errors"
 	"os"
 	"reflect"
+	"time"
 
 	"github.com/tedsuo/ifrit"
 )
@@ -11,11 +13,15 @@ import (
 NewParallel starts it's members simultaneously.  Use a parallel group to describe a set
 of concurrent but independent processes.
 */
-func NewParallel(terminationSignal os.Signal, members Members) ifrit.Runner {
+func NewParallel(terminationSignal os.Signal, members Members, opts ...Option) ifrit.Runner {
+	options := newGroupOptions(ShutdownParallel, opts)
 	return parallelGroup{
 		terminationSignal: terminationSignal,
 		pool:              make(map[string]ifrit.Process),
 		members:           members,
+		options:           options,
+		startTimes:        make(map[string]time.Time),
+		timedOut:          make(map[string]struct{}),
 	}
 }
 
@@ -23,6 +29,11 @@ type parallelGroup struct {
 	terminationSignal os.Signal
 	pool              map[string]ifrit.Process
 	members           Members
+	options           groupOptions
+	startTimes        map[string]time.Time
+	timedOut          map[string]struct{}
+	toleratedExits    ErrorTrace
+	numDown           int
 }
 
 func (g parallelGroup) Run(signals <-chan os.Signal, ready chan<- struct{}) error {
@@ -31,165 +42,249 @@ func (g parallelGroup) Run(signals <-chan os.Signal, ready chan<- struct{}) erro
 		return err
 	}
 
-	signal, errTrace := g.parallelStart(signals)
+	signals, stopContext := g.options.contextSignals(signals, g.terminationSignal)
+	defer stopContext()
+
+	g.toleratedExits = nil
+	g.numDown = 0
+
+	signal, errTrace := g.parallelStart(signals, ready)
 	if errTrace != nil {
-		return g.stop(g.terminationSignal, signals, errTrace).ErrorOrNil()
+		err := g.stop(g.terminationSignal, signals, errTrace)
+		if err == nil && len(errTrace) == 0 {
+			return ErrNotReady
+		}
+		return err
 	}
 
 	if signal != nil {
-		return g.stop(signal, signals, errTrace).ErrorOrNil()
+		return g.stop(signal, signals, errTrace)
 	}
 
-	close(ready)
-
 	signal, errTrace = g.waitForSignal(signals, errTrace)
-	return g.stop(signal, signals, errTrace).ErrorOrNil()
+	return g.stop(signal, signals, errTrace)
 }
 
 func (o parallelGroup) validate() error {
-	return o.members.Validate()
+	err := o.members.Validate()
+	if err != nil {
+		return err
+	}
+	return o.options.validateMembers(o.members)
 }
 
-func (g *parallelGroup) parallelStart(signals <-chan os.Signal) (os.Signal, ErrorTrace) {
-	numMembers := len(g.members)
-
-	cases := make([]reflect.SelectCase, 2*numMembers+1)
+/*
+ErrNotReady is returned by a parallel group which shuts down before it is ready
+because its members were not ready in time, or exited, without any error the
+group would otherwise return: for instance, because every member exited before
+it was ready, and each exit was tolerated.
+*/
+var ErrNotReady = errors.New("group shut down before it was ready")
 
-	for i, member := range g.members {
-		process := ifrit.Background(member)
+/*
+parallelStart starts the members, and closes ready once enough of them are
+ready.  It returns once every member is ready or has been tolerated exiting.
+A group none of whose members became ready is not ready, and parallelStart
+then returns an empty ErrorTrace.
+*/
+func (g *parallelGroup) parallelStart(signals <-chan os.Signal, ready chan<- struct{}) (os.Signal, ErrorTrace) {
+	numMembers := len(g.members)
 
-		g.pool[member.Name] = process
+	cases := make([]reflect.SelectCase, 2*numMembers+2)
 
+	for i := range g.members {
 		cases[2*i] = reflect.SelectCase{
 			Dir:  reflect.SelectRecv,
-			Chan: reflect.ValueOf(process.Wait()),
+			Chan: reflect.ValueOf((<-chan error)(nil)),
 		}
 
 		cases[2*i+1] = reflect.SelectCase{
 			Dir:  reflect.SelectRecv,
-			Chan: reflect.ValueOf(process.Ready()),
+			Chan: reflect.ValueOf((<-chan struct{})(nil)),
 		}
 	}
 
+	numStarted := 0
+	startNext := func() {
+		member := g.members[numStarted]
+		g.startTimes[member.Name] = time.Now()
+		process := ifrit.Background(g.options.runner(member, nil))
+
+		g.pool[member.Name] = process
+
+		cases[2*numStarted].Chan = reflect.ValueOf(process.Wait())
+		cases[2*numStarted+1].Chan = reflect.ValueOf(process.Ready())
+		numStarted++
+	}
+
+	limit := g.options.startConcurrency
+	if limit <= 0 || limit > numMembers {
+		limit = numMembers
+	}
+	for numStarted < limit {
+		startNext()
+	}
+
 	cases[2*numMembers] = reflect.SelectCase{
 		Dir:  reflect.SelectRecv,
 		Chan: reflect.ValueOf(signals),
 	}
 
-	numReady := 0
+	timeout, stopTimer := g.options.readyTimer()
+	defer stopTimer()
+
+	cases[2*numMembers+1] = reflect.SelectCase{
+		Dir:  reflect.SelectRecv,
+		Chan: reflect.ValueOf(timeout),
+	}
+
+	numReady, numSettled := 0, 0
+	groupReady := false
+	memberSettled := func(memberReady bool) bool {
+		numSettled++
+		if memberReady {
+			numReady++
+		}
+		if numStarted < numMembers {
+			startNext()
+		}
+		if !groupReady && numReady > 0 && g.options.quorumReady(numReady, numSettled, numMembers) {
+			groupReady = true
+			close(ready)
+			cases[2*numMembers+1].Chan = reflect.ValueOf((<-chan time.Time)(nil))
+		}
+		return numSettled == numMembers || (!groupReady && g.options.quorumUnreachable(numReady, numSettled, numMembers))
+	}
+	started := func() (os.Signal, ErrorTrace) {
+		if !groupReady {
+			return nil, ErrorTrace{}
+		}
+		return nil, nil
+	}
+
 	for {
 		chosen, recv, _ := reflect.Select(cases)
 
 		switch {
+		case chosen == 2*numMembers+1:
+			for i, member := range g.members[:numStarted] {
+				if !cases[2*i+1].Chan.IsNil() {
+					g.timedOut[member.Name] = struct{}{}
+				}
+			}
+			return nil, ErrorTrace{}
 		case chosen == 2*numMembers:
 			return recv.Interface().(os.Signal), nil
 		case chosen%2 == 0:
 			recvError, _ := recv.Interface().(error)
-			return nil, ErrorTrace{ExitEvent{Member: g.members[chosen/2], Err: recvError}}
+			member := g.members[chosen/2]
+			if !g.tolerate(member) {
+				return nil, ErrorTrace{g.exitEvent(member, recvError)}
+			}
+
+			g.toleratedExits = append(g.toleratedExits, g.exitEvent(member, recvError))
+			cases[chosen].Chan = reflect.Zero(cases[chosen].Chan.Type())
+			if !cases[chosen+1].Chan.IsNil() {
+				cases[chosen+1].Chan = reflect.Zero(cases[chosen+1].Chan.Type())
+				if memberSettled(false) {
+					return started()
+				}
+			}
 		default:
 			cases[chosen].Chan = reflect.Zero(cases[chosen].Chan.Type())
-			numReady++
-			if numReady == numMembers {
-				return nil, nil
+			if memberSettled(true) {
+				return started()
 			}
 		}
 	}
 }
 
+/*
+tolerate reports whether the group carries on when member exits: optional
+members may always exit, and others while no more than the group's failure
+tolerance are down.
+*/
+func (g *parallelGroup) tolerate(member Member) bool {
+	if g.options.member(member.Name).Optional {
+		return true
+	}
+	g.numDown++
+	return g.numDown <= g.options.failureTolerance
+}
+
 func (g *parallelGroup) waitForSignal(signals <-chan os.Signal, errTrace ErrorTrace) (os.Signal, ErrorTrace) {
-	cases := make([]reflect.SelectCase, 0, len(g.pool)+1)
-	for i := 0; i < len(g.pool); i++ {
+	for {
+		cases := make([]reflect.SelectCase, 0, len(g.pool)+1)
+		for _, member := range g.members {
+			cases = append(cases, g.waitCase(member))
+		}
 		cases = append(cases, reflect.SelectCase{
 			Dir:  reflect.SelectRecv,
-			Chan: reflect.ValueOf(g.pool[g.members[i].Name].Wait()),
+			Chan: reflect.ValueOf(signals),
 		})
-	}
-	cases = append(cases, reflect.SelectCase{
-		Dir:  reflect.SelectRecv,
-		Chan: reflect.ValueOf(signals),
-	})
 
-	chosen, recv, _ := reflect.Select(cases)
-	if chosen == len(cases)-1 {
-		return recv.Interface().(os.Signal), errTrace
-	}
+		chosen, recv, _ := reflect.Select(cases)
+		if chosen == len(cases)-1 {
+			return recv.Interface().(os.Signal), errTrace
+		}
 
-	var err error
-	if !recv.IsNil() {
-		err = recv.Interface().(error)
-	}
+		err, _ := recv.Interface().(error)
+		if g.tolerate(g.members[chosen]) {
+			g.toleratedExits = append(g.toleratedExits, g.exitEvent(g.members[chosen], err))
+			continue
+		}
 
-	errTrace = append(errTrace, ExitEvent{
-		Member: g.members[chosen],
-		Err:    err,
-	})
+		errTrace = append(errTrace, g.exitEvent(g.members[chosen], err))
 
-	return g.terminationSignal, errTrace
+		return g.terminationSignal, errTrace
+	}
 }
 
-func (g *parallelGroup) stop(signal os.Signal, signals <-chan os.Signal, errTrace ErrorTrace) ErrorTrace {
-	errOccurred := false
-	exited := map[string]struct{}{}
-	if len(errTrace) > 0 {
-		for _, exitEvent := range errTrace {
-			exited[exitEvent.Member.Name] = struct{}{}
-			if exitEvent.Err != nil {
-				errOccurred = true
+/*
+waitCase receives the exit of a member, unless it has already exited, and the
+group carried on without it.
+*/
+func (g *parallelGroup) waitCase(member Member) reflect.SelectCase {
+	for _, exit := range g.toleratedExits {
+		if exit.Member.Name == member.Name {
+			return reflect.SelectCase{
+				Dir:  reflect.SelectRecv,
+				Chan: reflect.ValueOf((<-chan error)(nil)),
 			}
 		}
 	}
 
-	cases := make([]reflect.SelectCase, 0, len(g.members))
-	liveMembers := make([]Member, 0, len(g.members))
-	for _, member := range g.members {
-		if _, found := exited[member.Name]; found {
-			continue
-		}
-
-		process := g.pool[member.Name]
-
-		process.Signal(signal)
-
-		cases = append(cases, reflect.SelectCase{
-			Dir:  reflect.SelectRecv,
-			Chan: reflect.ValueOf(process.Wait()),
-		})
-
-		liveMembers = append(liveMembers, member)
+	return reflect.SelectCase{
+		Dir:  reflect.SelectRecv,
+		Chan: reflect.ValueOf(g.pool[member.Name].Wait()),
 	}
+}
 
-	cases = append(cases, reflect.SelectCase{
-		Dir:  reflect.SelectRecv,
-		Chan: reflect.ValueOf(signals),
-	})
+func (g *parallelGroup) stop(signal os.Signal, signals <-chan os.Signal, errTrace ErrorTrace) error {
+	errTrace = append(append(ErrorTrace{}, g.toleratedExits...), errTrace...)
 
-	// account for the signals channel
-	for numExited := 1; numExited < len(cases); numExited++ {
-		chosen, recv, _ := reflect.Select(cases)
-		cases[chosen].Chan = reflect.Zero(cases[chosen].Chan.Type())
-		recvError, _ := recv.Interface().(error)
+	exited := map[string]struct{}{}
+	for _, exitEvent := range errTrace {
+		exited[exitEvent.Member.Name] = struct{}{}
+	}
 
-		if chosen == len(cases)-1 {
-			signal = recv.Interface().(os.Signal)
-			for _, member := range liveMembers {
-				g.pool[member.Name].Signal(signal)
-			}
+	liveMembers := make(Members, 0, len(g.members))
+	for _, member := range g.members {
+		if _, started := g.pool[member.Name]; !started {
 			continue
 		}
-
-		errTrace = append(errTrace, ExitEvent{
-			Member: liveMembers[chosen],
-			Err:    recvError,
-		})
-
-		if recvError != nil {
-			errOccurred = true
+		if _, found := exited[member.Name]; !found {
+			liveMembers = append(liveMembers, member)
 		}
 	}
 
-	if errOccurred {
-		return errTrace
-	}
+	errTrace = g.options.stopMembers(liveMembers, g.pool, signal, signals, errTrace, g.exitEvent)
+	return g.options.errorOrNil(errTrace)
+}
 
-	return nil
+func (g *parallelGroup) exitEvent(member Member, err error) ExitEvent {
+	if _, found := g.timedOut[member.Name]; found {
+		err = ErrReadyTimeout{Member: member.Name, Timeout: g.options.readyTimeout, Err: err}
+	}
+	return newExitEvent(member, g.startTimes[member.Name], err)
 }
@@ -150,10 +150,10 @@ var _ = Describe("Parallel Group", func() {
 					It("returns an error indicating which child processes failed", func() {
 						var err error
 						Eventually(groupProcess.Wait()).Should(Receive(&err))
-						Ω(err).Should(ConsistOf(
-							grouper.ExitEvent{grouper.Member{"child1", childRunner1}, nil},
-							grouper.ExitEvent{grouper.Member{"child2", childRunner2}, errors.New("Fail")},
-							grouper.ExitEvent{grouper.Member{"child3", childRunner3}, nil},
+						Ω(untimed(err)).Should(ConsistOf(
+							grouper.ExitEvent{Member: grouper.Member{Name: "child1", Runner: childRunner1}, Err: nil},
+							grouper.ExitEvent{Member: grouper.Member{Name: "child2", Runner: childRunner2}, Err: errors.New("Fail")},
+							grouper.ExitEvent{Member: grouper.Member{Name: "child3", Runner: childRunner3}, Err: nil},
 						))
 					})
 				})
@@ -183,10 +183,10 @@ var _ = Describe("Parallel Group", func() {
 					var err error
 
 					Eventually(groupProcess.Wait()).Should(Receive(&err))
-					Ω(err).Should(ConsistOf(
-						grouper.ExitEvent{grouper.Member{"child2", childRunner2}, errors.New("Fail")},
-						grouper.ExitEvent{grouper.Member{"child1", childRunner1}, nil},
-						grouper.ExitEvent{grouper.Member{"child3", childRunner3}, nil},
+					Ω(untimed(err)).Should(ConsistOf(
+						grouper.ExitEvent{Member: grouper.Member{Name: "child2", Runner: childRunner2}, Err: errors.New("Fail")},
+						grouper.ExitEvent{Member: grouper.Member{Name: "child1", Runner: childRunner1}, Err: nil},
+						grouper.ExitEvent{Member: grouper.Member{Name: "child3", Runner: childRunner3}, Err: nil},
 					))
 				})
 			})
@@ -205,13 +205,92 @@ var _ = Describe("Parallel Group", func() {
 
 					Eventually(groupProcess.Wait()).Should(Receive(&err))
 
-					Ω(err).Should(ConsistOf(
-						grouper.ExitEvent{grouper.Member{"child1", childRunner1}, errors.New("Fail")},
-						grouper.ExitEvent{grouper.Member{"child2", childRunner2}, nil},
-						grouper.ExitEvent{grouper.Member{"child3", childRunner3}, nil},
+					Ω(untimed(err)).Should(ConsistOf(
+						grouper.ExitEvent{Member: grouper.Member{Name: "child1", Runner: childRunner1}, Err: errors.New("Fail")},
+						grouper.ExitEvent{Member: grouper.Member{Name: "child2", Runner: childRunner2}, Err: nil},
+						grouper.ExitEvent{Member: grouper.Member{Name: "child3", Runner: childRunner3}, Err: nil},
 					))
 				})
 			})
 		})
 	})
+
+	Describe("WithReadyTimeout", func() {
+		BeforeEach(func() {
+			groupRunner = grouper.NewParallel(os.Interrupt, members, grouper.WithReadyTimeout(100*time.Millisecond))
+			groupProcess = ifrit.Background(groupRunner)
+		})
+
+		It("stops the group when a member is not ready in time, naming the member", func() {
+			signal1 := childRunner1.WaitForCall()
+			signal2 := childRunner2.WaitForCall()
+			signal3 := childRunner3.WaitForCall()
+			childRunner1.TriggerReady()
+			childRunner3.TriggerReady()
+
+			Eventually(signal1).Should(Receive(Equal(os.Interrupt)))
+			Eventually(signal2).Should(Receive(Equal(os.Interrupt)))
+			Eventually(signal3).Should(Receive(Equal(os.Interrupt)))
+			childRunner1.TriggerExit(nil)
+			childRunner2.TriggerExit(nil)
+			childRunner3.TriggerExit(nil)
+
+			var err error
+			Eventually(groupProcess.Wait()).Should(Receive(&err))
+			Ω(untimed(err)).Should(ConsistOf(
+				grouper.ExitEvent{Member: grouper.Member{Name: "child1", Runner: childRunner1}, Err: nil},
+				grouper.ExitEvent{Member: grouper.Member{Name: "child2", Runner: childRunner2}, Err: grouper.ErrReadyTimeout{Member: "child2", Timeout: 100 * time.Millisecond}},
+				grouper.ExitEvent{Member: grouper.Member{Name: "child3", Runner: childRunner3}, Err: nil},
+			))
+			Ω(groupProcess.Ready()).ShouldNot(BeClosed())
+		})
+
+		It("does not stop the group once its members are ready", func() {
+			childRunner1.TriggerReady()
+			childRunner2.TriggerReady()
+			childRunner3.TriggerReady()
+			Eventually(groupProcess.Ready()).Should(BeClosed())
+
+			Consistently(groupProcess.Wait(), 200*time.Millisecond).ShouldNot(Receive())
+		})
+	})
+
+	Describe("WithStartConcurrency", func() {
+		BeforeEach(func() {
+			groupRunner = grouper.NewParallel(os.Interrupt, members, grouper.WithStartConcurrency(2))
+			groupProcess = ifrit.Background(groupRunner)
+		})
+
+		It("starts no more than the limit at once", func() {
+			Eventually(childRunner1.RunCallCount).Should(Equal(1))
+			Eventually(childRunner2.RunCallCount).Should(Equal(1))
+			Consistently(childRunner3.RunCallCount, Δ).Should(Equal(0))
+
+			childRunner2.TriggerReady()
+			Eventually(childRunner3.RunCallCount).Should(Equal(1))
+
+			childRunner3.TriggerReady()
+			Consistently(groupProcess.Ready(), Δ).ShouldNot(BeClosed())
+
+			childRunner1.TriggerReady()
+			Eventually(groupProcess.Ready()).Should(BeClosed())
+		})
+
+		It("stops only the started members when one fails to start", func() {
+			signal1 := childRunner1.WaitForCall()
+			childRunner2.WaitForCall()
+			childRunner2.TriggerExit(errors.New("Fail"))
+
+			Eventually(signal1).Should(Receive(Equal(os.Interrupt)))
+			childRunner1.TriggerExit(nil)
+
+			var err error
+			Eventually(groupProcess.Wait()).Should(Receive(&err))
+			Ω(untimed(err)).Should(Equal(grouper.ErrorTrace{
+				{Member: members[1], Err: errors.New("Fail")},
+				{Member: members[0], Err: nil},
+			}))
+			Ω(childRunner3.RunCallCount()).Should(Equal(0))
+		})
+	})
 })
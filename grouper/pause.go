@@ -0,0 +1,44 @@
+package grouper
+
+import "errors"
+
+/*
+A Pausable Runner cooperates with its group's Pause and Resume, by suspending
+and resuming its work while it keeps running.  Pause and Resume are called from
+the group's event loop, and must return promptly.
+*/
+type Pausable interface {
+	Pause()
+	Resume()
+}
+
+/*
+ErrGroupPaused is returned by ReplaceMember while a dynamic group is paused.
+*/
+var ErrGroupPaused = errors.New("group is paused")
+
+type pauseRequest struct {
+	Pause    bool
+	Response chan error
+}
+
+/*
+Pause pauses or resumes each running member whose Runner is Pausable.
+*/
+func (g *processSet) Pause(pause bool) {
+	for process, member := range g.members {
+		pausable, ok := member.Runner.(Pausable)
+		if !ok {
+			continue
+		}
+		if _, retiring := g.retiring[process]; retiring {
+			continue
+		}
+
+		if pause {
+			pausable.Pause()
+		} else {
+			pausable.Resume()
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package grouper_test
+
+import (
+	"os"
+	"sync"
+	"time"
+
+	"github.com/tedsuo/ifrit"
+	"github.com/tedsuo/ifrit/grouper"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type pausableRunner struct {
+	mutex   sync.Mutex
+	pauses  int
+	resumes int
+}
+
+func (r *pausableRunner) Run(signals <-chan os.Signal, ready chan<- struct{}) error {
+	close(ready)
+	<-signals
+	return nil
+}
+
+func (r *pausableRunner) Pause() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.pauses++
+}
+
+func (r *pausableRunner) Resume() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.resumes++
+}
+
+func (r *pausableRunner) Calls() (int, int) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.pauses, r.resumes
+}
+
+var _ = Describe("Pausing a dynamic group", func() {
+	var (
+		client      grouper.DynamicClient
+		poolProcess ifrit.Process
+
+		Δ time.Duration = 10 * time.Millisecond
+	)
+
+	member := func(name string) grouper.Member {
+		return grouper.Member{Name: name, Runner: ifrit.RunFunc(func(signals <-chan os.Signal, ready chan<- struct{}) error {
+			close(ready)
+			<-signals
+			return nil
+		})}
+	}
+
+	BeforeEach(func() {
+		pool := grouper.NewDynamic(nil, 2, 10)
+		client = pool.Client()
+		poolProcess = ifrit.Invoke(pool)
+	})
+
+	AfterEach(func() {
+		poolProcess.Signal(os.Kill)
+		Eventually(poolProcess.Wait()).Should(Receive())
+	})
+
+	It("admits no new members until it is resumed", func() {
+		Ω(client.Pause()).Should(Succeed())
+		Consistently(client.Inserter(), Δ).ShouldNot(BeSent(member("a")))
+
+		Ω(client.Resume()).Should(Succeed())
+		Eventually(client.Inserter()).Should(BeSent(member("a")))
+		Eventually(func() bool {
+			_, found := client.Get("a")
+			return found
+		}).Should(BeTrue())
+	})
+
+	It("pauses and resumes members which are Pausable", func() {
+		runner := &pausableRunner{}
+		Eventually(client.Inserter()).Should(BeSent(grouper.Member{Name: "a", Runner: runner}))
+		Eventually(func() bool {
+			_, found := client.Get("a")
+			return found
+		}).Should(BeTrue())
+
+		Ω(client.Pause()).Should(Succeed())
+		Ω(client.Pause()).Should(Succeed())
+		pauses, resumes := runner.Calls()
+		Ω(pauses).Should(Equal(1))
+		Ω(resumes).Should(Equal(0))
+
+		Ω(client.Resume()).Should(Succeed())
+		pauses, resumes = runner.Calls()
+		Ω(pauses).Should(Equal(1))
+		Ω(resumes).Should(Equal(1))
+	})
+
+	It("keeps running while paused, even once its members have exited", func() {
+		Eventually(client.Inserter()).Should(BeSent(member("a")))
+		Eventually(func() bool {
+			_, found := client.Get("a")
+			return found
+		}).Should(BeTrue())
+
+		Ω(client.Pause()).Should(Succeed())
+		Ω(client.RemoveMember("a")).Should(Succeed())
+		Consistently(poolProcess.Wait(), Δ).ShouldNot(Receive())
+
+		Ω(client.Resume()).Should(Succeed())
+		Eventually(client.Inserter()).Should(BeSent(member("b")))
+	})
+
+	It("exits once it is closed while paused and its members have exited", func() {
+		Eventually(client.Inserter()).Should(BeSent(member("a")))
+		Eventually(func() bool {
+			_, found := client.Get("a")
+			return found
+		}).Should(BeTrue())
+
+		Ω(client.Pause()).Should(Succeed())
+		client.Close()
+		Ω(client.RemoveMember("a")).Should(Succeed())
+		Eventually(poolProcess.Wait()).Should(Receive(BeNil()))
+	})
+
+	It("refuses to replace members while paused", func() {
+		Eventually(client.Inserter()).Should(BeSent(member("a")))
+		Eventually(func() bool {
+			_, found := client.Get("a")
+			return found
+		}).Should(BeTrue())
+
+		Ω(client.Pause()).Should(Succeed())
+		Ω(client.ReplaceMember(member("a"))).Should(Equal(grouper.ErrGroupPaused))
+	})
+
+	It("returns ErrGroupStopped once the group has exited", func() {
+		poolProcess.Signal(os.Kill)
+		Eventually(poolProcess.Wait()).Should(Receive())
+
+		Ω(client.Pause()).Should(Equal(grouper.ErrGroupStopped))
+		Ω(client.Resume()).Should(Equal(grouper.ErrGroupStopped))
+	})
+})
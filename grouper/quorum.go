@@ -0,0 +1,58 @@
+package grouper
+
+/*
+WithReadyQuorum makes a parallel group ready once quorum of its members are
+ready, rather than waiting for all of them.  The remaining members carry on
+starting, and the group still fails if one of them exits before it is ready,
+unless it is tolerated by WithFailureTolerance.  A ready timeout only bounds how
+long the group waits for its quorum.
+
+If so many members exit that the quorum can no longer be reached, the group
+shuts down.  A quorum of zero, or one larger than the group, waits for every
+member.  Ordered and dynamic groups ignore it.
+*/
+func WithReadyQuorum(quorum int) Option {
+	return func(o *groupOptions) {
+		o.readyQuorum = quorum
+	}
+}
+
+/*
+WithFailureTolerance lets a parallel group carry on when up to tolerance of its
+members have exited, whether before or after they are ready.  The group shuts
+down once more than tolerance members are down.  As with optional members, the
+exits of the members it carried on without are recorded in the ErrorTrace the
+group returns, but unlike optional members, their errors are returned.
+
+Optional members are not counted as down.  A group none of whose members became
+ready, or which could not reach its quorum, fails with the errors of the members
+it tolerated, or with ErrNotReady if none of them exited with an error.  Ordered
+and dynamic groups ignore it.
+*/
+func WithFailureTolerance(tolerance int) Option {
+	return func(o *groupOptions) {
+		o.failureTolerance = tolerance
+	}
+}
+
+/*
+quorumReady reports whether a group is ready, given how many of its members are
+ready, and how many are either ready or have exited.
+*/
+func (o groupOptions) quorumReady(numReady, numSettled, numMembers int) bool {
+	if o.readyQuorum <= 0 || o.readyQuorum > numMembers {
+		return numSettled == numMembers
+	}
+	return numReady >= o.readyQuorum
+}
+
+/*
+quorumUnreachable reports whether too few members are left starting for the
+group to reach its quorum.
+*/
+func (o groupOptions) quorumUnreachable(numReady, numSettled, numMembers int) bool {
+	if o.readyQuorum <= 0 || o.readyQuorum > numMembers {
+		return false
+	}
+	return numReady+numMembers-numSettled < o.readyQuorum
+}
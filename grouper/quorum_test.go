@@ -0,0 +1,179 @@
+package grouper_test
+
+import (
+	"errors"
+	"os"
+	"time"
+
+	"github.com/tedsuo/ifrit"
+	"github.com/tedsuo/ifrit/fake_runner"
+	"github.com/tedsuo/ifrit/ginkgomon"
+	"github.com/tedsuo/ifrit/grouper"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Quorum", func() {
+	var (
+		groupProcess ifrit.Process
+		members      grouper.Members
+
+		worker1 *fake_runner.TestRunner
+		worker2 *fake_runner.TestRunner
+		worker3 *fake_runner.TestRunner
+
+		signal1 <-chan os.Signal
+		signal2 <-chan os.Signal
+		signal3 <-chan os.Signal
+
+		Δ time.Duration = 10 * time.Millisecond
+	)
+
+	BeforeEach(func() {
+		worker1 = fake_runner.NewTestRunner()
+		worker2 = fake_runner.NewTestRunner()
+		worker3 = fake_runner.NewTestRunner()
+
+		members = grouper.Members{
+			{Name: "worker1", Runner: worker1},
+			{Name: "worker2", Runner: worker2},
+			{Name: "worker3", Runner: worker3},
+		}
+	})
+
+	start := func(opts ...grouper.Option) {
+		groupProcess = ifrit.Background(grouper.NewParallel(os.Interrupt, members, opts...))
+		signal1 = worker1.WaitForCall()
+		signal2 = worker2.WaitForCall()
+		signal3 = worker3.WaitForCall()
+	}
+
+	AfterEach(func() {
+		worker1.EnsureExit()
+		worker2.EnsureExit()
+		worker3.EnsureExit()
+
+		ginkgomon.Kill(groupProcess)
+	})
+
+	Describe("WithReadyQuorum", func() {
+		BeforeEach(func() {
+			start(grouper.WithReadyQuorum(2))
+		})
+
+		It("is ready once the quorum of its members are ready", func() {
+			worker1.TriggerReady()
+			Consistently(groupProcess.Ready(), Δ).ShouldNot(BeClosed())
+
+			worker3.TriggerReady()
+			Eventually(groupProcess.Ready()).Should(BeClosed())
+		})
+
+		It("still fails when a member exits before it is ready", func() {
+			worker1.TriggerReady()
+			worker2.TriggerReady()
+			Eventually(groupProcess.Ready()).Should(BeClosed())
+
+			worker3.TriggerExit(errors.New("Fail"))
+			Eventually(signal1).Should(Receive(Equal(os.Interrupt)))
+			Eventually(signal2).Should(Receive(Equal(os.Interrupt)))
+			worker1.TriggerExit(nil)
+			worker2.TriggerExit(nil)
+
+			Eventually(groupProcess.Wait()).Should(Receive(HaveOccurred()))
+		})
+	})
+
+	Describe("WithFailureTolerance", func() {
+		BeforeEach(func() {
+			start(grouper.WithFailureTolerance(1))
+			worker1.TriggerReady()
+			worker2.TriggerReady()
+			worker3.TriggerReady()
+			Eventually(groupProcess.Ready()).Should(BeClosed())
+		})
+
+		It("carries on while no more than the tolerance are down", func() {
+			worker1.TriggerExit(errors.New("Fail"))
+
+			Consistently(signal2, Δ).ShouldNot(Receive())
+			Consistently(signal3, Δ).ShouldNot(Receive())
+			Consistently(groupProcess.Wait(), Δ).ShouldNot(Receive())
+		})
+
+		It("shuts down once more than the tolerance are down, recording every exit", func() {
+			worker1.TriggerExit(errors.New("Fail"))
+			worker2.TriggerExit(nil)
+
+			Eventually(signal3).Should(Receive(Equal(os.Interrupt)))
+			worker3.TriggerExit(nil)
+
+			var err error
+			Eventually(groupProcess.Wait()).Should(Receive(&err))
+			Ω(untimed(err)).Should(ConsistOf(
+				grouper.ExitEvent{Member: members[0], Err: errors.New("Fail")},
+				grouper.ExitEvent{Member: members[1], Err: nil},
+				grouper.ExitEvent{Member: members[2], Err: nil},
+			))
+		})
+	})
+
+	Describe("with a quorum and a failure tolerance", func() {
+		BeforeEach(func() {
+			start(grouper.WithReadyQuorum(2), grouper.WithFailureTolerance(2))
+		})
+
+		It("becomes ready without a member which failed to start", func() {
+			worker1.TriggerExit(errors.New("Fail"))
+			worker2.TriggerReady()
+			worker3.TriggerReady()
+
+			Eventually(groupProcess.Ready()).Should(BeClosed())
+			Consistently(groupProcess.Wait(), Δ).ShouldNot(Receive())
+		})
+
+		It("shuts down once the quorum can no longer be reached", func() {
+			worker1.TriggerExit(errors.New("Fail"))
+			worker2.TriggerExit(errors.New("Fail"))
+
+			Eventually(signal3).Should(Receive(Equal(os.Interrupt)))
+			worker3.TriggerExit(nil)
+
+			Eventually(groupProcess.Wait()).Should(Receive(HaveOccurred()))
+			Ω(groupProcess.Ready()).ShouldNot(BeClosed())
+		})
+	})
+
+	Describe("when every member's exit before it is ready is tolerated", func() {
+		BeforeEach(func() {
+			start(grouper.WithFailureTolerance(3))
+		})
+
+		It("returns ErrNotReady without becoming ready", func() {
+			worker1.TriggerExit(nil)
+			worker2.TriggerExit(nil)
+			worker3.TriggerExit(nil)
+
+			Eventually(groupProcess.Wait()).Should(Receive(Equal(grouper.ErrNotReady)))
+			Ω(groupProcess.Ready()).ShouldNot(BeClosed())
+		})
+	})
+
+	Describe("with a quorum which tolerated exits make unreachable", func() {
+		BeforeEach(func() {
+			start(grouper.WithReadyQuorum(2), grouper.WithFailureTolerance(2))
+		})
+
+		It("returns ErrNotReady without becoming ready", func() {
+			worker1.TriggerExit(nil)
+			worker2.TriggerExit(nil)
+
+			Eventually(signal3).Should(Receive(Equal(os.Interrupt)))
+			worker3.TriggerExit(nil)
+
+			Eventually(groupProcess.Wait()).Should(Receive(Equal(grouper.ErrNotReady)))
+			Ω(groupProcess.Ready()).ShouldNot(BeClosed())
+		})
+	})
+})
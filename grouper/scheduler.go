@@ -0,0 +1,162 @@
+package grouper
+
+import (
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/tedsuo/ifrit"
+)
+
+/*
+A TransitionKind is a step in a member's lifecycle which a group observes.
+*/
+type TransitionKind int
+
+const (
+	// TransitionStart happens before the member's Runner is run.
+	TransitionStart TransitionKind = iota
+	// TransitionReady happens once the member is ready, before the group is told.
+	TransitionReady
+	// TransitionExit happens once the member has exited, before the group is told.
+	TransitionExit
+)
+
+func (k TransitionKind) String() string {
+	switch k {
+	case TransitionStart:
+		return "start"
+	case TransitionReady:
+		return "ready"
+	case TransitionExit:
+		return "exit"
+	default:
+		return fmt.Sprintf("TransitionKind(%d)", int(k))
+	}
+}
+
+/*
+A Transition describes a step of the named member.  Err is the error the member
+exited with, on a TransitionExit.
+*/
+type Transition struct {
+	Member string
+	Kind   TransitionKind
+	Err    error
+}
+
+/*
+A Scheduler decides when each member transition takes effect.  Schedule is
+called from the member's own goroutine, and the transition takes effect once it
+returns, so that a Scheduler which blocks holds the member at that step.
+
+Schedulers are meant for tests: a group's members run concurrently, so the
+order in which their transitions reach the group is otherwise up to the Go
+scheduler.  A test which steps them one at a time sees the same interleaving
+on every run.
+*/
+type Scheduler interface {
+	Schedule(transition Transition)
+}
+
+/*
+WithScheduler passes the transitions of every member of an ordered, parallel or
+dynamic group through scheduler.
+*/
+func WithScheduler(scheduler Scheduler) Option {
+	return func(o *groupOptions) {
+		o.scheduler = scheduler
+	}
+}
+
+func scheduled(runner ifrit.Runner, name string, scheduler Scheduler) ifrit.Runner {
+	return ifrit.RunFunc(func(signals <-chan os.Signal, ready chan<- struct{}) error {
+		scheduler.Schedule(Transition{Member: name, Kind: TransitionStart})
+
+		innerReady := make(chan struct{})
+		errs := make(chan error, 1)
+		go func() {
+			errs <- runner.Run(signals, innerReady)
+		}()
+
+		var err error
+		select {
+		case <-innerReady:
+			scheduler.Schedule(Transition{Member: name, Kind: TransitionReady})
+			close(ready)
+			err = <-errs
+
+		case err = <-errs:
+			// a runner which becomes ready and exits at once is still seen to
+			// become ready first
+			select {
+			case <-innerReady:
+				scheduler.Schedule(Transition{Member: name, Kind: TransitionReady})
+				close(ready)
+			default:
+			}
+		}
+
+		scheduler.Schedule(Transition{Member: name, Kind: TransitionExit, Err: err})
+		return err
+	})
+}
+
+/*
+A StepScheduler holds each transition until the test releases it.  Held
+transitions are delivered on Steps, in the order members reach them.
+*/
+type StepScheduler struct {
+	steps    chan Step
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+/*
+A Step is a held transition.  Release lets it take effect.
+*/
+type Step struct {
+	Transition
+	release chan struct{}
+}
+
+func (s Step) Release() {
+	close(s.release)
+}
+
+func NewStepScheduler() *StepScheduler {
+	return &StepScheduler{
+		steps: make(chan Step),
+		done:  make(chan struct{}),
+	}
+}
+
+/*
+Steps provides the transitions which are being held, one at a time.
+*/
+func (s *StepScheduler) Steps() <-chan Step {
+	return s.steps
+}
+
+/*
+Stop releases every held transition, and lets later ones take effect without
+being held, so that a test can shut its group down.  Stop may be called more
+than once.
+*/
+func (s *StepScheduler) Stop() {
+	s.stopOnce.Do(func() { close(s.done) })
+}
+
+func (s *StepScheduler) Schedule(transition Transition) {
+	step := Step{Transition: transition, release: make(chan struct{})}
+	select {
+	case s.steps <- step:
+	case <-s.done:
+		return
+	}
+
+	select {
+	case <-step.release:
+	case <-s.done:
+	}
+}
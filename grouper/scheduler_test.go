@@ -0,0 +1,143 @@
+package grouper_test
+
+import (
+	"errors"
+	"os"
+	"time"
+
+	"github.com/tedsuo/ifrit"
+	"github.com/tedsuo/ifrit/grouper"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Scheduling member transitions", func() {
+	var (
+		scheduler    *grouper.StepScheduler
+		groupProcess ifrit.Process
+		fail         chan error
+
+		Δ time.Duration = 10 * time.Millisecond
+	)
+
+	runner := func() ifrit.Runner {
+		return ifrit.RunFunc(func(signals <-chan os.Signal, ready chan<- struct{}) error {
+			close(ready)
+			select {
+			case <-signals:
+				return nil
+			case err := <-fail:
+				return err
+			}
+		})
+	}
+
+	step := func() grouper.Step {
+		var step grouper.Step
+		EventuallyWithOffset(1, scheduler.Steps()).Should(Receive(&step))
+		return step
+	}
+
+	release := func(member string, kind grouper.TransitionKind) {
+		step := step()
+		ExpectWithOffset(1, step.Member).Should(Equal(member))
+		ExpectWithOffset(1, step.Kind).Should(Equal(kind))
+		step.Release()
+	}
+
+	BeforeEach(func() {
+		scheduler = grouper.NewStepScheduler()
+		fail = make(chan error)
+	})
+
+	AfterEach(func() {
+		scheduler.Stop()
+		groupProcess.Signal(os.Kill)
+		Eventually(groupProcess.Wait()).Should(Receive())
+	})
+
+	It("steps an ordered group's members one transition at a time", func() {
+		groupProcess = ifrit.Background(grouper.NewOrdered(os.Interrupt, grouper.Members{
+			{Name: "a", Runner: runner()},
+			{Name: "b", Runner: runner()},
+		}, grouper.WithScheduler(scheduler)))
+
+		release("a", grouper.TransitionStart)
+		release("a", grouper.TransitionReady)
+		release("b", grouper.TransitionStart)
+		Consistently(groupProcess.Ready(), Δ).ShouldNot(BeClosed())
+		release("b", grouper.TransitionReady)
+		Eventually(groupProcess.Ready()).Should(BeClosed())
+	})
+
+	It("lets the test choose the order of concurrent transitions", func() {
+		groupProcess = ifrit.Background(grouper.NewParallel(os.Interrupt, grouper.Members{
+			{Name: "a", Runner: runner()},
+			{Name: "b", Runner: runner()},
+		}, grouper.WithScheduler(scheduler)))
+
+		starts := map[string]grouper.Step{}
+		for i := 0; i < 2; i++ {
+			start := step()
+			Ω(start.Kind).Should(Equal(grouper.TransitionStart))
+			starts[start.Member] = start
+		}
+
+		starts["b"].Release()
+		release("b", grouper.TransitionReady)
+		Consistently(scheduler.Steps(), Δ).ShouldNot(Receive())
+		Ω(groupProcess.Ready()).ShouldNot(BeClosed())
+
+		starts["a"].Release()
+		release("a", grouper.TransitionReady)
+		Eventually(groupProcess.Ready()).Should(BeClosed())
+	})
+
+	It("holds an exit until it is released", func() {
+		groupProcess = ifrit.Background(grouper.NewOrdered(os.Interrupt, grouper.Members{
+			{Name: "a", Runner: runner()},
+		}, grouper.WithScheduler(scheduler)))
+
+		release("a", grouper.TransitionStart)
+		release("a", grouper.TransitionReady)
+		Eventually(groupProcess.Ready()).Should(BeClosed())
+
+		fail <- errors.New("Fail")
+		exit := step()
+		Ω(exit.Transition).Should(Equal(grouper.Transition{Member: "a", Kind: grouper.TransitionExit, Err: errors.New("Fail")}))
+		Consistently(groupProcess.Wait(), Δ).ShouldNot(Receive())
+
+		exit.Release()
+		Eventually(groupProcess.Wait()).Should(Receive(HaveOccurred()))
+	})
+
+	It("schedules the members inserted into a dynamic group", func() {
+		pool := grouper.NewDynamic(nil, 1, 10, grouper.WithScheduler(scheduler))
+		groupProcess = ifrit.Background(pool)
+		Eventually(pool.Client().Inserter()).Should(BeSent(grouper.Member{Name: "a", Runner: runner()}))
+
+		release("a", grouper.TransitionStart)
+		release("a", grouper.TransitionReady)
+	})
+
+	It("stops holding transitions once it is stopped", func() {
+		groupProcess = ifrit.Background(grouper.NewOrdered(os.Interrupt, grouper.Members{
+			{Name: "a", Runner: runner()},
+			{Name: "b", Runner: runner()},
+		}, grouper.WithScheduler(scheduler)))
+
+		Eventually(scheduler.Steps()).Should(Receive())
+		scheduler.Stop()
+		Eventually(groupProcess.Ready()).Should(BeClosed())
+	})
+
+	Describe("TransitionKind", func() {
+		It("has a readable name", func() {
+			Ω(grouper.TransitionStart.String()).Should(Equal("start"))
+			Ω(grouper.TransitionReady.String()).Should(Equal("ready"))
+			Ω(grouper.TransitionExit.String()).Should(Equal("exit"))
+			Ω(grouper.TransitionKind(7).String()).Should(Equal("TransitionKind(7)"))
+		})
+	})
+})
@@ -0,0 +1,133 @@
+package grouper
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+
+	"github.com/tedsuo/ifrit"
+)
+
+/*
+A ShutdownOrder determines the order in which an ordered or parallel group
+signals its members when it shuts down.
+*/
+type ShutdownOrder int
+
+const (
+	// ShutdownReverse stops members one at a time, in reverse member order.  It
+	// is the default for ordered groups.
+	ShutdownReverse ShutdownOrder = iota
+	// ShutdownForward stops members one at a time, in member order.
+	ShutdownForward
+	// ShutdownParallel signals all members at once, or up to the limit given by
+	// WithShutdownConcurrency.  It is the default for parallel groups.
+	ShutdownParallel
+)
+
+func (o ShutdownOrder) String() string {
+	switch o {
+	case ShutdownReverse:
+		return "reverse"
+	case ShutdownForward:
+		return "forward"
+	case ShutdownParallel:
+		return "parallel"
+	default:
+		return fmt.Sprintf("ShutdownOrder(%d)", int(o))
+	}
+}
+
+/*
+WithShutdownOrder sets the order in which the group stops its members.  Each
+member is sent the shutdown signal once it is its turn to stop, and any later
+signals until it exits.
+*/
+func WithShutdownOrder(order ShutdownOrder) Option {
+	return func(o *groupOptions) {
+		o.shutdownOrder = order
+	}
+}
+
+/*
+WithShutdownConcurrency bounds how many members a ShutdownParallel group stops
+at once.  Members are signaled in member order as earlier ones exit.  A limit of
+zero stops every member at once.
+*/
+func WithShutdownConcurrency(limit int) Option {
+	return func(o *groupOptions) {
+		o.shutdownConcurrency = limit
+	}
+}
+
+/*
+stopMembers signals each of members in the group's shutdown order, and appends
+their exits to errTrace.  Members are given in member order.
+*/
+func (o groupOptions) stopMembers(
+	members Members,
+	pool map[string]ifrit.Process,
+	signal os.Signal,
+	signals <-chan os.Signal,
+	errTrace ErrorTrace,
+	exitEvent func(Member, error) ExitEvent,
+) ErrorTrace {
+	queue := make(Members, 0, len(members))
+	limit := 1
+	switch o.shutdownOrder {
+	case ShutdownReverse:
+		for i := len(members) - 1; i >= 0; i-- {
+			queue = append(queue, members[i])
+		}
+	case ShutdownParallel:
+		queue = append(queue, members...)
+		limit = o.shutdownConcurrency
+		if limit <= 0 {
+			limit = len(members)
+		}
+	default:
+		queue = append(queue, members...)
+	}
+
+	active := make(Members, 0, limit)
+	for len(queue) > 0 || len(active) > 0 {
+		for len(queue) > 0 && len(active) < limit {
+			pool[queue[0].Name].Signal(signal)
+			active = append(active, queue[0])
+			queue = queue[1:]
+		}
+
+		cases := make([]reflect.SelectCase, 0, len(active)+1)
+		for _, member := range active {
+			cases = append(cases, reflect.SelectCase{
+				Dir:  reflect.SelectRecv,
+				Chan: reflect.ValueOf(pool[member.Name].Wait()),
+			})
+		}
+		cases = append(cases, reflect.SelectCase{
+			Dir:  reflect.SelectRecv,
+			Chan: reflect.ValueOf(signals),
+		})
+
+		chosen, recv, _ := reflect.Select(cases)
+		if chosen == len(cases)-1 {
+			sig := recv.Interface().(os.Signal)
+			// members stopped one at a time are only sent signals which differ
+			// from the last
+			if o.shutdownOrder != ShutdownParallel && sig == signal {
+				continue
+			}
+			signal = sig
+			for _, member := range active {
+				pool[member.Name].Signal(signal)
+			}
+			continue
+		}
+
+		err, _ := recv.Interface().(error)
+		errTrace = append(errTrace, exitEvent(active[chosen], err))
+		active = append(active[:chosen], active[chosen+1:]...)
+	}
+
+	return errTrace
+}
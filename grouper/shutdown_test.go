@@ -0,0 +1,146 @@
+package grouper_test
+
+import (
+	"os"
+	"syscall"
+	"time"
+
+	"github.com/tedsuo/ifrit"
+	"github.com/tedsuo/ifrit/fake_runner"
+	"github.com/tedsuo/ifrit/ginkgomon"
+	"github.com/tedsuo/ifrit/grouper"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Shutdown order", func() {
+	var (
+		groupProcess ifrit.Process
+		members      grouper.Members
+
+		childRunner1 *fake_runner.TestRunner
+		childRunner2 *fake_runner.TestRunner
+		childRunner3 *fake_runner.TestRunner
+
+		signal1 <-chan os.Signal
+		signal2 <-chan os.Signal
+		signal3 <-chan os.Signal
+
+		Δ time.Duration = 10 * time.Millisecond
+	)
+
+	BeforeEach(func() {
+		childRunner1 = fake_runner.NewTestRunner()
+		childRunner2 = fake_runner.NewTestRunner()
+		childRunner3 = fake_runner.NewTestRunner()
+
+		members = grouper.Members{
+			{Name: "child1", Runner: childRunner1},
+			{Name: "child2", Runner: childRunner2},
+			{Name: "child3", Runner: childRunner3},
+		}
+	})
+
+	start := func(groupRunner ifrit.Runner) {
+		groupProcess = ifrit.Background(groupRunner)
+
+		signal1 = childRunner1.WaitForCall()
+		childRunner1.TriggerReady()
+		signal2 = childRunner2.WaitForCall()
+		childRunner2.TriggerReady()
+		signal3 = childRunner3.WaitForCall()
+		childRunner3.TriggerReady()
+
+		Eventually(groupProcess.Ready()).Should(BeClosed())
+	}
+
+	AfterEach(func() {
+		childRunner1.EnsureExit()
+		childRunner2.EnsureExit()
+		childRunner3.EnsureExit()
+
+		ginkgomon.Kill(groupProcess)
+	})
+
+	Context("when an ordered group stops in forward order", func() {
+		BeforeEach(func() {
+			start(grouper.NewOrdered(os.Interrupt, members, grouper.WithShutdownOrder(grouper.ShutdownForward)))
+		})
+
+		It("stops each member once the one before it has exited", func() {
+			groupProcess.Signal(syscall.SIGTERM)
+
+			Eventually(signal1).Should(Receive(Equal(syscall.SIGTERM)))
+			Consistently(signal2, Δ).ShouldNot(Receive())
+			childRunner1.TriggerExit(nil)
+
+			Eventually(signal2).Should(Receive(Equal(syscall.SIGTERM)))
+			Consistently(signal3, Δ).ShouldNot(Receive())
+			childRunner2.TriggerExit(nil)
+
+			Eventually(signal3).Should(Receive(Equal(syscall.SIGTERM)))
+			childRunner3.TriggerExit(nil)
+
+			Eventually(groupProcess.Wait()).Should(Receive(BeNil()))
+		})
+	})
+
+	Context("when a parallel group stops in reverse order", func() {
+		BeforeEach(func() {
+			start(grouper.NewParallel(os.Interrupt, members, grouper.WithShutdownOrder(grouper.ShutdownReverse)))
+		})
+
+		It("stops each member once the one after it has exited", func() {
+			groupProcess.Signal(syscall.SIGTERM)
+
+			Eventually(signal3).Should(Receive(Equal(syscall.SIGTERM)))
+			Consistently(signal2, Δ).ShouldNot(Receive())
+			childRunner3.TriggerExit(nil)
+
+			Eventually(signal2).Should(Receive(Equal(syscall.SIGTERM)))
+			Consistently(signal1, Δ).ShouldNot(Receive())
+			childRunner2.TriggerExit(nil)
+
+			Eventually(signal1).Should(Receive(Equal(syscall.SIGTERM)))
+			childRunner1.TriggerExit(nil)
+
+			Eventually(groupProcess.Wait()).Should(Receive(BeNil()))
+		})
+	})
+
+	Context("when a group stops in parallel with a concurrency limit", func() {
+		BeforeEach(func() {
+			start(grouper.NewOrdered(os.Interrupt, members,
+				grouper.WithShutdownOrder(grouper.ShutdownParallel),
+				grouper.WithShutdownConcurrency(2),
+			))
+		})
+
+		It("stops up to the limit at once", func() {
+			groupProcess.Signal(syscall.SIGTERM)
+
+			Eventually(signal1).Should(Receive(Equal(syscall.SIGTERM)))
+			Eventually(signal2).Should(Receive(Equal(syscall.SIGTERM)))
+			Consistently(signal3, Δ).ShouldNot(Receive())
+
+			childRunner2.TriggerExit(nil)
+			Eventually(signal3).Should(Receive(Equal(syscall.SIGTERM)))
+
+			groupProcess.Signal(syscall.SIGUSR2)
+			Eventually(signal1).Should(Receive(Equal(syscall.SIGUSR2)))
+			Eventually(signal3).Should(Receive(Equal(syscall.SIGUSR2)))
+
+			childRunner1.TriggerExit(nil)
+			childRunner3.TriggerExit(nil)
+			Eventually(groupProcess.Wait()).Should(Receive(BeNil()))
+		})
+	})
+})
+
+var _ = Describe("ShutdownOrder", func() {
+	It("names each order", func() {
+		Ω(grouper.ShutdownForward.String()).Should(Equal("forward"))
+		Ω(grouper.ShutdownOrder(7).String()).Should(Equal("ShutdownOrder(7)"))
+	})
+})
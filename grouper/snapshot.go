@@ -0,0 +1,123 @@
+package grouper
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+/*
+A MemberState describes where a member is in its lifecycle.
+*/
+type MemberState int
+
+const (
+	// MemberPending has not yet been started.
+	MemberPending MemberState = iota
+	// MemberStarting has been started, but is not yet ready.
+	MemberStarting
+	// MemberReady is running, and ready.
+	MemberReady
+	// MemberStopping has been signaled to stop, and has not yet exited.
+	MemberStopping
+	// MemberRestarting has exited, and is waiting to be restarted.
+	MemberRestarting
+	// MemberExited has exited, and will not be restarted.
+	MemberExited
+)
+
+func (s MemberState) String() string {
+	switch s {
+	case MemberPending:
+		return "pending"
+	case MemberStarting:
+		return "starting"
+	case MemberReady:
+		return "ready"
+	case MemberStopping:
+		return "stopping"
+	case MemberRestarting:
+		return "restarting"
+	case MemberExited:
+		return "exited"
+	default:
+		return fmt.Sprintf("MemberState(%d)", int(s))
+	}
+}
+
+/*
+A MemberStatus describes a member at the time of a snapshot.  Err is the error
+the member last exited with.  StartTime is when the member's current or last
+run started, and Uptime is how long it has been running, which is zero unless
+the member is running.  Labels are the member's labels, which a Selector can
+match to pick out the members of interest.
+
+Restarts is how many times a Supervisor has restarted the member after it
+exited, and TotalUptime is how long the member has run in all of its runs, so
+that a member which is flapping stands out.  Members of dynamic groups are not
+restarted, so their TotalUptime is their Uptime.
+*/
+type MemberStatus struct {
+	Name        string
+	Labels      Labels
+	State       MemberState
+	Err         error
+	StartTime   time.Time
+	Uptime      time.Duration
+	TotalUptime time.Duration
+	Restarts    int
+}
+
+func (s MemberStatus) running() bool {
+	return s.State == MemberStarting || s.State == MemberReady || s.State == MemberStopping
+}
+
+func newMemberStatus(name string, state MemberState, err error, startTime time.Time, restarts int, now time.Time) MemberStatus {
+	status := MemberStatus{
+		Name:      name,
+		State:     state,
+		Err:       err,
+		StartTime: startTime,
+		Restarts:  restarts,
+	}
+	if status.running() {
+		status.Uptime = now.Sub(startTime)
+	}
+	status.TotalUptime = status.Uptime
+	return status
+}
+
+/*
+Snapshot describes each of the Supervisor's members, in member order.
+*/
+func (s *Supervisor) Snapshot() ([]MemberStatus, error) {
+	response := make(chan []MemberStatus, 1)
+	err := s.group.do(func(r *groupRun) {
+		response <- r.snapshot()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return <-response, nil
+}
+
+func (r *groupRun) snapshot() []MemberStatus {
+	now := time.Now()
+	statuses := make([]MemberStatus, 0, len(r.members))
+	for _, m := range r.members {
+		status := newMemberStatus(m.member.Name, m.state, m.lastErr, m.startTime, m.restarts, now)
+		status.Labels = m.options.Labels
+		status.TotalUptime += m.uptime
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+func sortStatuses(statuses []MemberStatus) {
+	sort.Slice(statuses, func(i, j int) bool {
+		if !statuses[i].StartTime.Equal(statuses[j].StartTime) {
+			return statuses[i].StartTime.Before(statuses[j].StartTime)
+		}
+		return statuses[i].Name < statuses[j].Name
+	})
+}
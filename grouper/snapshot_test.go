@@ -0,0 +1,142 @@
+package grouper_test
+
+import (
+	"errors"
+	"os"
+	"time"
+
+	"github.com/tedsuo/ifrit"
+	"github.com/tedsuo/ifrit/fake_runner"
+	"github.com/tedsuo/ifrit/ginkgomon"
+	"github.com/tedsuo/ifrit/grouper"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Snapshot", func() {
+	Describe("Supervisor", func() {
+		var (
+			supervisor   *grouper.Supervisor
+			groupProcess ifrit.Process
+			flaky        *flakyRunner
+			slow         *fake_runner.TestRunner
+		)
+
+		BeforeEach(func() {
+			flaky = newFlakyRunner()
+			slow = fake_runner.NewTestRunner()
+			supervisor = grouper.NewSupervisor(os.Interrupt, grouper.Members{
+				{Name: "flaky", Runner: flaky},
+				{Name: "slow", Runner: slow},
+			}, grouper.WithMemberOptions("flaky", grouper.MemberOptions{Restart: grouper.RestartPolicy{Mode: grouper.RestartOnFailure, Backoff: time.Hour}}))
+			groupProcess = ifrit.Background(supervisor)
+			slow.WaitForCall()
+		})
+
+		AfterEach(func() {
+			slow.EnsureExit()
+			ginkgomon.Kill(groupProcess)
+		})
+
+		It("describes each member", func() {
+			Eventually(func() grouper.MemberState {
+				snapshot, err := supervisor.Snapshot()
+				Ω(err).ShouldNot(HaveOccurred())
+				return snapshot[0].State
+			}).Should(Equal(grouper.MemberReady))
+
+			snapshot, err := supervisor.Snapshot()
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(snapshot).Should(HaveLen(2))
+			Ω(snapshot[0].Name).Should(Equal("flaky"))
+			Ω(snapshot[0].Uptime).Should(BeNumerically(">", 0))
+			Ω(snapshot[1].Name).Should(Equal("slow"))
+			Ω(snapshot[1].State).Should(Equal(grouper.MemberStarting))
+
+			flaky.exits <- errors.New("Fail")
+			Eventually(func() grouper.MemberState {
+				snapshot, _ := supervisor.Snapshot()
+				return snapshot[0].State
+			}).Should(Equal(grouper.MemberRestarting))
+
+			snapshot, err = supervisor.Snapshot()
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(snapshot[0].Err).Should(MatchError("Fail"))
+			Ω(snapshot[0].Uptime).Should(BeZero())
+			Ω(snapshot[0].TotalUptime).Should(BeNumerically(">", 0))
+			Ω(snapshot[0].Restarts).Should(BeZero())
+		})
+
+		It("returns ErrGroupStopped once the supervisor has exited", func() {
+			groupProcess.Signal(os.Interrupt)
+			slow.TriggerExit(nil)
+			Eventually(groupProcess.Wait()).Should(Receive())
+
+			_, err := supervisor.Snapshot()
+			Ω(err).Should(Equal(grouper.ErrGroupStopped))
+		})
+	})
+
+	Describe("DynamicGroup", func() {
+		var (
+			client       grouper.DynamicClient
+			poolProcess  ifrit.Process
+			childRunner1 *fake_runner.TestRunner
+			childRunner2 *fake_runner.TestRunner
+		)
+
+		BeforeEach(func() {
+			childRunner1 = fake_runner.NewTestRunner()
+			childRunner2 = fake_runner.NewTestRunner()
+
+			pool := grouper.NewDynamic(nil, 2, 2)
+			client = pool.Client()
+			poolProcess = ifrit.Invoke(pool)
+
+			Eventually(client.Inserter()).Should(BeSent(grouper.Member{Name: "child1", Runner: childRunner1}))
+			childRunner1.WaitForCall()
+			Eventually(client.Inserter()).Should(BeSent(grouper.Member{Name: "child2", Runner: childRunner2}))
+			childRunner2.WaitForCall()
+		})
+
+		AfterEach(func() {
+			poolProcess.Signal(os.Kill)
+			childRunner1.EnsureExit()
+			childRunner2.EnsureExit()
+			Eventually(poolProcess.Wait()).Should(Receive())
+		})
+
+		It("describes each running member, in the order they were started", func() {
+			childRunner1.TriggerReady()
+			Eventually(func() grouper.MemberState {
+				return client.Snapshot()[0].State
+			}).Should(Equal(grouper.MemberReady))
+
+			snapshot := client.Snapshot()
+			Ω(snapshot).Should(HaveLen(2))
+			Ω(snapshot[0].Name).Should(Equal("child1"))
+			Ω(snapshot[1].Name).Should(Equal("child2"))
+			Ω(snapshot[1].State).Should(Equal(grouper.MemberStarting))
+
+			childRunner1.TriggerExit(nil)
+			Eventually(client.Snapshot).Should(HaveLen(1))
+		})
+
+		It("returns nil once the group has exited", func() {
+			poolProcess.Signal(os.Kill)
+			childRunner1.TriggerExit(nil)
+			childRunner2.TriggerExit(nil)
+			Eventually(poolProcess.Wait()).Should(Receive())
+
+			Ω(client.Snapshot()).Should(BeNil())
+		})
+	})
+})
+
+var _ = Describe("MemberState", func() {
+	It("names each state", func() {
+		Ω(grouper.MemberRestarting.String()).Should(Equal("restarting"))
+		Ω(grouper.MemberState(9).String()).Should(Equal("MemberState(9)"))
+	})
+})
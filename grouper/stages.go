@@ -0,0 +1,50 @@
+package grouper
+
+import (
+	"os"
+
+	"github.com/tedsuo/ifrit"
+)
+
+/*
+Stages are a list of sets of members, which are started one stage after
+another.
+*/
+type Stages []Members
+
+/*
+NewStages starts its stages in order: the members of each stage start in
+parallel once every member of the previous stage is ready.  The group is ready
+once all of its members are ready.  On shutdown, the stages are stopped in
+reverse order, each once every member of the next stage has exited.
+
+Use a staged group to boot layers of processes, such as infrastructure, then
+the services which use it, then the ingress in front of them.  Member names
+must be unique across all stages.
+*/
+func NewStages(terminationSignal os.Signal, stages Stages, opts ...Option) ifrit.Runner {
+	members := Members{}
+	dependencies := Dependencies{}
+
+	var previous Members
+	for _, stage := range stages {
+		if len(stage) == 0 {
+			continue
+		}
+
+		for _, member := range stage {
+			members = append(members, member)
+			for _, dependency := range previous {
+				dependencies[member.Name] = append(dependencies[member.Name], dependency.Name)
+			}
+		}
+		previous = stage
+	}
+
+	return &group{
+		terminationSignal: terminationSignal,
+		members:           members,
+		dependencies:      dependencies,
+		options:           newGroupOptions(ShutdownReverse, opts),
+	}
+}
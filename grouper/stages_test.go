@@ -0,0 +1,99 @@
+package grouper_test
+
+import (
+	"os"
+	"syscall"
+	"time"
+
+	"github.com/tedsuo/ifrit"
+	"github.com/tedsuo/ifrit/fake_runner"
+	"github.com/tedsuo/ifrit/ginkgomon"
+	"github.com/tedsuo/ifrit/grouper"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Staged Group", func() {
+	var (
+		groupProcess ifrit.Process
+		stages       grouper.Stages
+
+		database *fake_runner.TestRunner
+		cache    *fake_runner.TestRunner
+		api      *fake_runner.TestRunner
+		ingress  *fake_runner.TestRunner
+
+		Δ time.Duration = 10 * time.Millisecond
+	)
+
+	BeforeEach(func() {
+		database = fake_runner.NewTestRunner()
+		cache = fake_runner.NewTestRunner()
+		api = fake_runner.NewTestRunner()
+		ingress = fake_runner.NewTestRunner()
+
+		stages = grouper.Stages{
+			{{Name: "database", Runner: database}, {Name: "cache", Runner: cache}},
+			{},
+			{{Name: "api", Runner: api}},
+			{{Name: "ingress", Runner: ingress}},
+		}
+	})
+
+	JustBeforeEach(func() {
+		groupProcess = ifrit.Background(grouper.NewStages(os.Interrupt, stages))
+	})
+
+	AfterEach(func() {
+		database.EnsureExit()
+		cache.EnsureExit()
+		api.EnsureExit()
+		ingress.EnsureExit()
+
+		ginkgomon.Kill(groupProcess)
+	})
+
+	It("starts each stage once the previous stage is ready, and stops them in reverse", func() {
+		databaseSignals := database.WaitForCall()
+		cacheSignals := cache.WaitForCall()
+		database.TriggerReady()
+		Consistently(api.RunCallCount, Δ).Should(BeZero())
+
+		cache.TriggerReady()
+		apiSignals := api.WaitForCall()
+		Consistently(ingress.RunCallCount, Δ).Should(BeZero())
+
+		api.TriggerReady()
+		ingressSignals := ingress.WaitForCall()
+		ingress.TriggerReady()
+		Eventually(groupProcess.Ready()).Should(BeClosed())
+
+		groupProcess.Signal(syscall.SIGTERM)
+		Eventually(ingressSignals).Should(Receive(Equal(syscall.SIGTERM)))
+		Consistently(apiSignals, Δ).ShouldNot(Receive())
+		ingress.TriggerExit(nil)
+
+		Eventually(apiSignals).Should(Receive(Equal(syscall.SIGTERM)))
+		Consistently(databaseSignals, Δ).ShouldNot(Receive())
+		api.TriggerExit(nil)
+
+		Eventually(databaseSignals).Should(Receive(Equal(syscall.SIGTERM)))
+		Eventually(cacheSignals).Should(Receive(Equal(syscall.SIGTERM)))
+		database.TriggerExit(nil)
+		cache.TriggerExit(nil)
+
+		Eventually(groupProcess.Wait()).Should(Receive(BeNil()))
+	})
+
+	Context("when a name is used in more than one stage", func() {
+		BeforeEach(func() {
+			stages = append(stages, grouper.Members{{Name: "api", Runner: api}})
+		})
+
+		It("fails without starting any members", func() {
+			Eventually(groupProcess.Wait()).Should(Receive(Equal(grouper.ErrDuplicateNames{DuplicateNames: []string{"api"}})))
+			Ω(database.RunCallCount()).Should(BeZero())
+		})
+	})
+})
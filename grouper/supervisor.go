@@ -0,0 +1,276 @@
+package grouper
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"time"
+)
+
+/*
+A RestartMode determines when a Supervisor restarts a member which exits.
+*/
+type RestartMode int
+
+const (
+	// RestartNever never restarts the member; its exit shuts the group down.
+	RestartNever RestartMode = iota
+	// RestartOnFailure restarts the member if it exits with an error.
+	RestartOnFailure
+	// RestartAlways restarts the member whenever it exits.
+	RestartAlways
+)
+
+func (m RestartMode) String() string {
+	switch m {
+	case RestartNever:
+		return "never"
+	case RestartOnFailure:
+		return "on-failure"
+	case RestartAlways:
+		return "always"
+	default:
+		return fmt.Sprintf("RestartMode(%d)", int(m))
+	}
+}
+
+/*
+A RestartPolicy determines whether, and how soon, a Supervisor restarts a
+member which exits before the group is signaled.
+
+The first restart happens after Backoff, and each later one after twice the
+previous delay, up to MaxBackoff if it is set.  Once a member has been
+restarted MaxRestarts times, its next exit shuts the group down, as if its Mode
+were RestartNever.  A MaxRestarts of zero allows unlimited restarts.
+
+The zero RestartPolicy never restarts.
+*/
+type RestartPolicy struct {
+	Mode        RestartMode
+	Backoff     time.Duration
+	MaxBackoff  time.Duration
+	MaxRestarts int
+}
+
+func (p RestartPolicy) shouldRestart(err error, restarts int) bool {
+	if p.MaxRestarts > 0 && restarts >= p.MaxRestarts {
+		return false
+	}
+
+	switch p.Mode {
+	case RestartAlways:
+		return true
+	case RestartOnFailure:
+		return err != nil
+	default:
+		return false
+	}
+}
+
+func (p RestartPolicy) delay(restarts int) time.Duration {
+	delay := p.Backoff
+	for i := 0; i < restarts; i++ {
+		if (p.MaxBackoff > 0 && delay >= p.MaxBackoff) || delay > math.MaxInt64/2 {
+			break
+		}
+		delay *= 2
+	}
+	if p.MaxBackoff > 0 && delay > p.MaxBackoff {
+		delay = p.MaxBackoff
+	}
+	return delay
+}
+
+/*
+A Supervisor starts its members simultaneously, like a parallel group, but
+restarts members which exit according to their RestartPolicy, rather than
+shutting the whole group down.  A member which is not restarted shuts the group
+down with the termination signal, as in other groups.
+
+Exits which lead to a restart are not included in the ErrorTrace the
+Supervisor returns, except for the last exit of a member which is waiting to be
+restarted when the group shuts down.
+
+A Supervisor's methods manage it while it runs.  They wait for it to start,
+and return ErrGroupStopped once it has exited.  A Supervisor can only be run
+once.
+*/
+type Supervisor struct {
+	group *group
+}
+
+/*
+NewSupervisor creates a Supervisor.
+*/
+func NewSupervisor(terminationSignal os.Signal, members Members, opts ...Option) *Supervisor {
+	return &Supervisor{
+		group: &group{
+			terminationSignal: terminationSignal,
+			members:           members,
+			supervised:        true,
+			options:           newGroupOptions(ShutdownReverse, opts),
+			requests:          make(chan func(*groupRun)),
+			stopped:           make(chan struct{}),
+		},
+	}
+}
+
+func (s *Supervisor) Run(signals <-chan os.Signal, ready chan<- struct{}) error {
+	return s.group.Run(signals, ready)
+}
+
+/*
+ErrRollingRestartInProgress is returned by RollingRestart when another rolling
+restart has not yet finished.
+*/
+var ErrRollingRestartInProgress = errors.New("rolling restart already in progress")
+
+/*
+ErrRollingRestartFailed is returned by RollingRestart when a member exits
+before it is ready again.  The member's exit is then handled according to its
+RestartPolicy, as any other exit would be.
+*/
+type ErrRollingRestartFailed struct {
+	Member string
+	Err    error
+}
+
+func (e ErrRollingRestartFailed) Error() string {
+	return fmt.Sprintf("member %s exited before it was ready after being restarted: %v", e.Member, e.Err)
+}
+
+func (e ErrRollingRestartFailed) Unwrap() error {
+	return e.Err
+}
+
+/*
+RollingRestart restarts each running member, up to concurrency of them at a
+time, in member order.  Each member is stopped with the termination signal, or
+os.Interrupt if there is none, and started again once it exits; the next member
+is stopped once it is ready.  Members which are not running are skipped, and
+rolling restarts do not count towards a member's MaxRestarts.
+
+RollingRestart returns once every member has been restarted.  If a member exits
+before it is ready again, no further members are restarted, and RollingRestart
+returns an ErrRollingRestartFailed.  If the Supervisor is signaled, the rolling
+restart stops, and RollingRestart returns ErrGroupStopped.
+*/
+func (s *Supervisor) RollingRestart(concurrency int) error {
+	response := make(chan error, 1)
+	err := s.group.do(func(r *groupRun) {
+		r.rollingRestart(concurrency, response)
+	})
+	if err != nil {
+		return err
+	}
+	return <-response
+}
+
+/*
+SignalMember sends signal to the named member, without signaling the rest of
+the group.  The member's exit, if the signal causes one, is handled according
+to its RestartPolicy, as any other exit would be.  SignalMember returns
+ErrMemberNotFound if there is no such member, or it is not running.
+*/
+func (s *Supervisor) SignalMember(name string, signal os.Signal) error {
+	response := make(chan error, 1)
+	err := s.group.do(func(r *groupRun) {
+		response <- r.signalMember(name, signal)
+	})
+	if err != nil {
+		return err
+	}
+	return <-response
+}
+
+func (r *groupRun) signalMember(name string, signal os.Signal) error {
+	for _, m := range r.members {
+		if m.member.Name != name {
+			continue
+		}
+		if m.state != MemberStarting && m.state != MemberReady && m.state != MemberStopping {
+			break
+		}
+		m.process.Signal(signal)
+		return nil
+	}
+	return ErrMemberNotFound{Name: name}
+}
+
+type rollingRestart struct {
+	queue       []*memberRun
+	active      int
+	concurrency int
+	response    chan error
+}
+
+func (r *groupRun) rollingRestart(concurrency int, response chan error) {
+	if r.stopping {
+		response <- ErrGroupStopped
+		return
+	}
+	if r.rolling != nil {
+		response <- ErrRollingRestartInProgress
+		return
+	}
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	r.rolling = &rollingRestart{
+		queue:       append([]*memberRun{}, r.members...),
+		concurrency: concurrency,
+		response:    response,
+	}
+	r.continueRollingRestart()
+}
+
+func (r *groupRun) continueRollingRestart() {
+	rolling := r.rolling
+	for rolling.active < rolling.concurrency && len(rolling.queue) > 0 {
+		m := rolling.queue[0]
+		rolling.queue = rolling.queue[1:]
+		if m.state != MemberReady {
+			continue
+		}
+
+		m.rolling = true
+		rolling.active++
+		m.process.Signal(r.group.stopSignal())
+	}
+
+	if rolling.active == 0 {
+		rolling.response <- nil
+		r.rolling = nil
+	}
+}
+
+/*
+rolledOver records that m is ready again after being restarted, or that it
+failed to become ready with err.
+*/
+func (r *groupRun) rolledOver(m *memberRun, err error) {
+	m.rolling = false
+	if r.rolling == nil {
+		return
+	}
+
+	r.rolling.active--
+	if err != nil {
+		r.rolling.response <- err
+		r.rolling = nil
+		return
+	}
+	r.continueRollingRestart()
+}
+
+/*
+stopSignal is sent to members which the group stops while it runs.
+*/
+func (g *group) stopSignal() os.Signal {
+	if g.terminationSignal != nil {
+		return g.terminationSignal
+	}
+	return os.Interrupt
+}
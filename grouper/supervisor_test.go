@@ -0,0 +1,377 @@
+package grouper_test
+
+import (
+	"errors"
+	"os"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/tedsuo/ifrit"
+	"github.com/tedsuo/ifrit/fake_runner"
+	"github.com/tedsuo/ifrit/ginkgomon"
+	"github.com/tedsuo/ifrit/grouper"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// flakyRunner becomes ready on each run, and exits with whatever is sent on exits
+type flakyRunner struct {
+	runs  int32
+	exits chan error
+}
+
+func newFlakyRunner() *flakyRunner {
+	return &flakyRunner{exits: make(chan error)}
+}
+
+func (r *flakyRunner) Run(signals <-chan os.Signal, ready chan<- struct{}) error {
+	atomic.AddInt32(&r.runs, 1)
+	close(ready)
+
+	select {
+	case err := <-r.exits:
+		return err
+	case <-signals:
+		return nil
+	}
+}
+
+func (r *flakyRunner) Runs() int {
+	return int(atomic.LoadInt32(&r.runs))
+}
+
+// gatedRunner becomes ready on each run once it is sent on ready, and exits once signaled, or with whatever is sent
+// on exits
+type gatedRunner struct {
+	runs    int32
+	ready   chan struct{}
+	exits   chan error
+	signals chan os.Signal
+}
+
+func newGatedRunner() *gatedRunner {
+	return &gatedRunner{
+		ready:   make(chan struct{}),
+		exits:   make(chan error),
+		signals: make(chan os.Signal, 10),
+	}
+}
+
+func (r *gatedRunner) Run(signals <-chan os.Signal, ready chan<- struct{}) error {
+	atomic.AddInt32(&r.runs, 1)
+
+	select {
+	case <-r.ready:
+		close(ready)
+	case err := <-r.exits:
+		return err
+	case signal := <-signals:
+		r.signals <- signal
+		return nil
+	}
+
+	select {
+	case err := <-r.exits:
+		return err
+	case signal := <-signals:
+		r.signals <- signal
+		return nil
+	}
+}
+
+func (r *gatedRunner) Runs() int {
+	return int(atomic.LoadInt32(&r.runs))
+}
+
+var _ = Describe("Supervisor", func() {
+	var (
+		groupProcess ifrit.Process
+		members      grouper.Members
+
+		flaky  *flakyRunner
+		steady *fake_runner.TestRunner
+		policy grouper.RestartPolicy
+
+		steadySignals <-chan os.Signal
+
+		Δ time.Duration = 10 * time.Millisecond
+	)
+
+	BeforeEach(func() {
+		flaky = newFlakyRunner()
+		steady = fake_runner.NewTestRunner()
+		policy = grouper.RestartPolicy{Mode: grouper.RestartOnFailure}
+	})
+
+	JustBeforeEach(func() {
+		members = grouper.Members{
+			{Name: "flaky", Runner: flaky},
+			{Name: "steady", Runner: steady},
+		}
+		groupProcess = ifrit.Background(grouper.NewSupervisor(os.Interrupt, members,
+			grouper.WithMemberOptions("flaky", grouper.MemberOptions{Restart: policy}),
+		))
+
+		steadySignals = steady.WaitForCall()
+		steady.TriggerReady()
+		Eventually(groupProcess.Ready()).Should(BeClosed())
+	})
+
+	AfterEach(func() {
+		steady.EnsureExit()
+		ginkgomon.Kill(groupProcess)
+	})
+
+	It("restarts a member which fails, without disturbing the others", func() {
+		flaky.exits <- errors.New("Fail")
+		Eventually(flaky.Runs).Should(Equal(2))
+
+		Consistently(steadySignals, Δ).ShouldNot(Receive())
+		Consistently(groupProcess.Wait(), Δ).ShouldNot(Receive())
+	})
+
+	It("shuts the group down when a member exits cleanly", func() {
+		flaky.exits <- nil
+
+		Eventually(steadySignals).Should(Receive(Equal(os.Interrupt)))
+		steady.TriggerExit(nil)
+		Eventually(groupProcess.Wait()).Should(Receive(BeNil()))
+		Ω(flaky.Runs()).Should(Equal(1))
+	})
+
+	It("leaves restarted exits out of the exit trace", func() {
+		flaky.exits <- errors.New("Fail")
+		Eventually(flaky.Runs).Should(Equal(2))
+
+		groupProcess.Signal(syscall.SIGTERM)
+		Eventually(steadySignals).Should(Receive(Equal(syscall.SIGTERM)))
+		steady.TriggerExit(nil)
+		Eventually(groupProcess.Wait()).Should(Receive(BeNil()))
+	})
+
+	Context("when the member restarts always", func() {
+		BeforeEach(func() {
+			policy = grouper.RestartPolicy{Mode: grouper.RestartAlways, MaxRestarts: 2}
+		})
+
+		It("restarts it up to MaxRestarts times", func() {
+			flaky.exits <- nil
+			Eventually(flaky.Runs).Should(Equal(2))
+			flaky.exits <- nil
+			Eventually(flaky.Runs).Should(Equal(3))
+
+			flaky.exits <- errors.New("Fail")
+			Eventually(steadySignals).Should(Receive(Equal(os.Interrupt)))
+			steady.TriggerExit(nil)
+
+			var err error
+			Eventually(groupProcess.Wait()).Should(Receive(&err))
+			Ω(untimed(err)).Should(Equal(grouper.ErrorTrace{
+				{Member: members[0], Err: errors.New("Fail")},
+				{Member: members[1], Err: nil},
+			}))
+			Ω(flaky.Runs()).Should(Equal(3))
+		})
+
+		It("records the member's restarts and total uptime in its exit", func() {
+			flaky.exits <- nil
+			Eventually(flaky.Runs).Should(Equal(2))
+			time.Sleep(Δ)
+			flaky.exits <- nil
+			Eventually(flaky.Runs).Should(Equal(3))
+
+			flaky.exits <- errors.New("Fail")
+			Eventually(steadySignals).Should(Receive(Equal(os.Interrupt)))
+			steady.TriggerExit(nil)
+
+			var err error
+			Eventually(groupProcess.Wait()).Should(Receive(&err))
+			trace := err.(grouper.ErrorTrace)
+			Ω(trace[0].Restarts).Should(Equal(2))
+			Ω(trace[0].TotalUptime).Should(BeNumerically(">=", trace[0].Duration+Δ))
+			Ω(trace[1].Restarts).Should(BeZero())
+			Ω(trace[1].TotalUptime).Should(Equal(trace[1].Duration))
+		})
+	})
+
+	Context("when the policy has a backoff", func() {
+		BeforeEach(func() {
+			policy = grouper.RestartPolicy{Mode: grouper.RestartOnFailure, Backoff: 100 * time.Millisecond}
+		})
+
+		It("waits before restarting", func() {
+			flaky.exits <- errors.New("Fail")
+			Consistently(flaky.Runs, 50*time.Millisecond).Should(Equal(1))
+			Eventually(flaky.Runs).Should(Equal(2))
+		})
+
+		It("records the last exit of a member waiting to restart when the group shuts down", func() {
+			flaky.exits <- errors.New("Fail")
+			groupProcess.Signal(syscall.SIGTERM)
+			Eventually(steadySignals).Should(Receive(Equal(syscall.SIGTERM)))
+			steady.TriggerExit(nil)
+
+			var err error
+			Eventually(groupProcess.Wait()).Should(Receive(&err))
+			Ω(untimed(err)).Should(Equal(grouper.ErrorTrace{
+				{Member: members[0], Err: errors.New("Fail")},
+				{Member: members[1], Err: nil},
+			}))
+			Consistently(flaky.Runs, 150*time.Millisecond).Should(Equal(1))
+		})
+	})
+})
+
+var _ = Describe("Supervisor SignalMember", func() {
+	var (
+		supervisor   *grouper.Supervisor
+		groupProcess ifrit.Process
+		frontend     *gatedRunner
+		backend      *gatedRunner
+
+		Δ time.Duration = 10 * time.Millisecond
+	)
+
+	BeforeEach(func() {
+		frontend = newGatedRunner()
+		backend = newGatedRunner()
+		supervisor = grouper.NewSupervisor(os.Interrupt, grouper.Members{
+			{Name: "frontend", Runner: frontend},
+			{Name: "backend", Runner: backend},
+		}, grouper.WithMemberOptions("frontend", grouper.MemberOptions{Restart: grouper.RestartPolicy{Mode: grouper.RestartAlways}}))
+		groupProcess = ifrit.Background(supervisor)
+
+		frontend.ready <- struct{}{}
+		backend.ready <- struct{}{}
+		Eventually(groupProcess.Ready()).Should(BeClosed())
+	})
+
+	AfterEach(func() {
+		ginkgomon.Kill(groupProcess)
+	})
+
+	It("signals only the named member, and handles its exit by its restart policy", func() {
+		Ω(supervisor.SignalMember("frontend", syscall.SIGHUP)).Should(Succeed())
+
+		Eventually(frontend.signals).Should(Receive(Equal(syscall.SIGHUP)))
+		Eventually(frontend.Runs).Should(Equal(2))
+		Consistently(backend.signals, Δ).ShouldNot(Receive())
+		Consistently(groupProcess.Wait(), Δ).ShouldNot(Receive())
+	})
+
+	It("returns ErrMemberNotFound for an unknown member", func() {
+		Ω(supervisor.SignalMember("blah", syscall.SIGHUP)).Should(Equal(grouper.ErrMemberNotFound{Name: "blah"}))
+	})
+
+	It("returns ErrGroupStopped once the supervisor has exited", func() {
+		ginkgomon.Kill(groupProcess)
+		Ω(supervisor.SignalMember("frontend", syscall.SIGHUP)).Should(Equal(grouper.ErrGroupStopped))
+	})
+})
+
+var _ = Describe("RestartMode", func() {
+	It("names each mode", func() {
+		Ω(grouper.RestartOnFailure.String()).Should(Equal("on-failure"))
+		Ω(grouper.RestartMode(7).String()).Should(Equal("RestartMode(7)"))
+	})
+})
+
+var _ = Describe("Supervisor RollingRestart", func() {
+	var (
+		supervisor   *grouper.Supervisor
+		groupProcess ifrit.Process
+		first        *gatedRunner
+		second       *gatedRunner
+		restarted    chan error
+
+		Δ time.Duration = 10 * time.Millisecond
+	)
+
+	rollingRestart := func(concurrency int) {
+		errs := make(chan error, 1)
+		go func() {
+			errs <- supervisor.RollingRestart(concurrency)
+		}()
+		restarted = errs
+	}
+
+	BeforeEach(func() {
+		first = newGatedRunner()
+		second = newGatedRunner()
+		supervisor = grouper.NewSupervisor(os.Interrupt, grouper.Members{
+			{Name: "first", Runner: first},
+			{Name: "second", Runner: second},
+		}, grouper.WithMemberOptions("first", grouper.MemberOptions{Restart: grouper.RestartPolicy{Mode: grouper.RestartOnFailure}}))
+		groupProcess = ifrit.Background(supervisor)
+
+		first.ready <- struct{}{}
+		second.ready <- struct{}{}
+		Eventually(groupProcess.Ready()).Should(BeClosed())
+	})
+
+	AfterEach(func() {
+		ginkgomon.Kill(groupProcess)
+	})
+
+	It("restarts members one at a time, waiting for each to be ready", func() {
+		rollingRestart(1)
+
+		Eventually(first.signals).Should(Receive(Equal(os.Interrupt)))
+		Eventually(first.Runs).Should(Equal(2))
+		Consistently(second.signals, Δ).ShouldNot(Receive())
+
+		first.ready <- struct{}{}
+		Eventually(second.signals).Should(Receive(Equal(os.Interrupt)))
+		Eventually(second.Runs).Should(Equal(2))
+		Consistently(restarted, Δ).ShouldNot(Receive())
+
+		second.ready <- struct{}{}
+		Eventually(restarted).Should(Receive(BeNil()))
+		Consistently(groupProcess.Wait(), Δ).ShouldNot(Receive())
+	})
+
+	It("restarts up to concurrency members at once", func() {
+		rollingRestart(2)
+
+		Eventually(first.signals).Should(Receive(Equal(os.Interrupt)))
+		Eventually(second.signals).Should(Receive(Equal(os.Interrupt)))
+
+		first.ready <- struct{}{}
+		second.ready <- struct{}{}
+		Eventually(restarted).Should(Receive(BeNil()))
+	})
+
+	It("stops when a member exits before it is ready again", func() {
+		rollingRestart(1)
+
+		Eventually(first.signals).Should(Receive())
+		first.exits <- errors.New("Fail")
+
+		Eventually(restarted).Should(Receive(Equal(grouper.ErrRollingRestartFailed{Member: "first", Err: errors.New("Fail")})))
+		Consistently(second.signals, Δ).ShouldNot(Receive())
+
+		Eventually(first.Runs).Should(Equal(3))
+		Consistently(groupProcess.Wait(), Δ).ShouldNot(Receive())
+	})
+
+	It("refuses to start a second rolling restart while one is in progress", func() {
+		rollingRestart(1)
+		Eventually(first.signals).Should(Receive())
+
+		Ω(supervisor.RollingRestart(1)).Should(Equal(grouper.ErrRollingRestartInProgress))
+	})
+
+	It("returns ErrGroupStopped once the supervisor has been signaled", func() {
+		rollingRestart(1)
+		Eventually(first.signals).Should(Receive())
+
+		groupProcess.Signal(syscall.SIGTERM)
+		Eventually(restarted).Should(Receive(Equal(grouper.ErrGroupStopped)))
+		Eventually(second.signals).Should(Receive(Equal(syscall.SIGTERM)))
+		Eventually(groupProcess.Wait()).Should(Receive())
+
+		Ω(supervisor.RollingRestart(1)).Should(Equal(grouper.ErrGroupStopped))
+	})
+})
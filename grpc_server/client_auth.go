@@ -0,0 +1,98 @@
+package grpc_server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+)
+
+// ErrNoClientCertificate is returned by client certificate verification when a client presents no certificate.
+var ErrNoClientCertificate = errors.New("client did not present a certificate")
+
+// clientAuth describes how client certificates are verified.
+type clientAuth struct {
+	caPool    *x509.CertPool
+	verifiers []func(*x509.Certificate) error
+}
+
+func (c clientAuth) configured() bool {
+	return c.caPool != nil || len(c.verifiers) > 0
+}
+
+// validate checks that verifiers only ever see certificates whose chain has been verified, either against the client
+// CA pool or by tlsConfig itself.
+func (c clientAuth) validate(tlsConfig *tls.Config) error {
+	if len(c.verifiers) == 0 || c.caPool != nil {
+		return nil
+	}
+	if tlsConfig != nil && tlsConfig.ClientCAs != nil && tlsConfig.ClientAuth == tls.RequireAndVerifyClientCert {
+		return nil
+	}
+	return validationError("client certificate verifiers require WithClientCAs")
+}
+
+// apply returns a copy of tlsConfig which requires client certificates, verifies their chain against the client CA
+// pool, and then passes the leaf certificate to each verifier in turn.
+func (c clientAuth) apply(tlsConfig *tls.Config) *tls.Config {
+	if !c.configured() {
+		return tlsConfig
+	}
+
+	if tlsConfig != nil {
+		tlsConfig = tlsConfig.Clone()
+	} else {
+		tlsConfig = &tls.Config{}
+	}
+
+	if c.caPool != nil {
+		tlsConfig.ClientCAs = c.caPool
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	if len(c.verifiers) > 0 {
+		verifyConnection := tlsConfig.VerifyConnection
+		verifiers := c.verifiers
+		tlsConfig.VerifyConnection = func(state tls.ConnectionState) error {
+			if verifyConnection != nil {
+				err := verifyConnection(state)
+				if err != nil {
+					return err
+				}
+			}
+
+			if len(state.PeerCertificates) == 0 {
+				return ErrNoClientCertificate
+			}
+			for _, verify := range verifiers {
+				err := verify(state.PeerCertificates[0])
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}
+
+	return tlsConfig
+}
+
+// spiffeIDVerifier accepts certificates carrying one of ids as a URI SAN.
+func spiffeIDVerifier(ids []string) func(*x509.Certificate) error {
+	allowed := make(map[string]struct{}, len(ids))
+	for _, id := range ids {
+		allowed[id] = struct{}{}
+	}
+
+	return func(cert *x509.Certificate) error {
+		for _, uri := range cert.URIs {
+			if uri.Scheme != "spiffe" {
+				continue
+			}
+			if _, ok := allowed[uri.String()]; ok {
+				return nil
+			}
+		}
+		return fmt.Errorf("client certificate for %q does not carry an allowed SPIFFE ID", cert.Subject.CommonName)
+	}
+}
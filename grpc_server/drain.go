@@ -0,0 +1,176 @@
+package grpc_server
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+/*
+A DrainPolicy describes how the runner drains in-flight RPCs once it has been
+signaled.  The server first stops accepting new RPCs and sends GOAWAY to its
+clients.  RPCs are then given GracePeriod to complete.  If CancelStreams is set,
+the context of every stream still open is then cancelled, and the streams are
+given CancelTimeout to return.  Finally, the server is stopped forcefully.
+
+The runner exits with a DrainError unless every RPC completed within the grace
+period.
+*/
+type DrainPolicy struct {
+	GracePeriod   time.Duration
+	CancelStreams bool
+	OnCancel      func(info *grpc.StreamServerInfo) // optional; invoked for each stream as it is cancelled
+	CancelTimeout time.Duration
+}
+
+// A DrainOutcome describes which stage of a DrainPolicy ended the drain.
+type DrainOutcome int
+
+const (
+	DrainCompleted DrainOutcome = iota // every RPC completed within the grace period
+	DrainCancelled                     // every RPC completed after open streams were cancelled
+	DrainForced                        // the server was stopped forcefully
+)
+
+func (o DrainOutcome) String() string {
+	switch o {
+	case DrainCompleted:
+		return "completed"
+	case DrainCancelled:
+		return "cancelled"
+	case DrainForced:
+		return "forced"
+	default:
+		return fmt.Sprintf("DrainOutcome(%d)", int(o))
+	}
+}
+
+// A DrainError is returned by a runner configured with a DrainPolicy when the drain did not complete within the grace
+// period.  A forced drain matches ErrForcedStop using errors.Is.
+type DrainError struct {
+	Outcome          DrainOutcome
+	CancelledStreams int
+}
+
+func (e DrainError) Error() string {
+	return fmt.Sprintf("grpc server drain %s after cancelling %d streams", e.Outcome, e.CancelledStreams)
+}
+
+func (e DrainError) Is(target error) bool {
+	return target == ErrForcedStop && e.Outcome == DrainForced
+}
+
+func (s *grpcServerRunner) drain(server Server, streams *streamTracker) error {
+	policy := s.drainPolicy
+
+	stopped := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(stopped)
+	}()
+
+	if waitOrTimeout(stopped, policy.GracePeriod) {
+		return nil
+	}
+
+	cancelled := 0
+	if policy.CancelStreams {
+		cancelled = streams.CancelAll(policy.OnCancel)
+		if waitOrTimeout(stopped, policy.CancelTimeout) {
+			return DrainError{Outcome: DrainCancelled, CancelledStreams: cancelled}
+		}
+	}
+
+	server.Stop()
+	<-stopped
+	return DrainError{Outcome: DrainForced, CancelledStreams: cancelled}
+}
+
+// waitOrTimeout reports whether done was closed within timeout.
+func waitOrTimeout(done <-chan struct{}, timeout time.Duration) bool {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-done:
+		return true
+	case <-timer.C:
+		return false
+	}
+}
+
+// newStreamTracker returns a streamTracker for a server, if the runner's DrainPolicy cancels streams, and nil otherwise.
+func (s *grpcServerRunner) newStreamTracker() *streamTracker {
+	if s.drainPolicy == nil || !s.drainPolicy.CancelStreams {
+		return nil
+	}
+	return newStreamTracker()
+}
+
+// streamTracker is a stream interceptor which allows every open stream to be cancelled.
+type streamTracker struct {
+	lock    sync.Mutex
+	nextID  int
+	streams map[int]*trackedStream
+}
+
+type trackedStream struct {
+	grpc.ServerStream
+	ctx       context.Context
+	cancel    context.CancelFunc
+	info      *grpc.StreamServerInfo
+	cancelled bool
+}
+
+func (s *trackedStream) Context() context.Context {
+	return s.ctx
+}
+
+func newStreamTracker() *streamTracker {
+	return &streamTracker{streams: map[int]*trackedStream{}}
+}
+
+func (t *streamTracker) Intercept(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, cancel := context.WithCancel(ss.Context())
+	defer cancel()
+
+	stream := &trackedStream{ServerStream: ss, ctx: ctx, cancel: cancel, info: info}
+
+	t.lock.Lock()
+	id := t.nextID
+	t.nextID++
+	t.streams[id] = stream
+	t.lock.Unlock()
+
+	defer func() {
+		t.lock.Lock()
+		delete(t.streams, id)
+		t.lock.Unlock()
+	}()
+
+	return handler(srv, stream)
+}
+
+// CancelAll cancels every open stream which has not been cancelled yet, invoking onCancel for each, and returns the
+// number of streams cancelled.
+func (t *streamTracker) CancelAll(onCancel func(*grpc.StreamServerInfo)) int {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	cancelled := 0
+	for _, stream := range t.streams {
+		if stream.cancelled {
+			continue
+		}
+		stream.cancelled = true
+		if onCancel != nil {
+			onCancel(stream.info)
+		}
+		stream.cancel()
+		cancelled++
+	}
+	return cancelled
+}
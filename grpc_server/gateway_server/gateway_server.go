@@ -0,0 +1,97 @@
+/*
+The gateway_server package runs a GRPC server alongside a grpc-gateway REST/JSON
+reverse proxy for the same services, as a single ifrit.Runner.
+*/
+package gateway_server
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"os"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/tedsuo/ifrit"
+	"github.com/tedsuo/ifrit/grouper"
+	"github.com/tedsuo/ifrit/grpc_server"
+	"github.com/tedsuo/ifrit/http_server"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// A Registrar is the "RegisterXXXHandlerFromEndpoint" function generated by protoc-gen-grpc-gateway.
+type Registrar func(ctx context.Context, mux *runtime.ServeMux, endpoint string, opts []grpc.DialOption) error
+
+// Config describes the REST/JSON side of the runner.
+type Config struct {
+	ListenAddress string                   // address of the REST/JSON listener
+	TLSConfig     *tls.Config              // optional; if nil the REST/JSON listener runs insecure
+	Registrars    []Registrar              // one for each service exposed over REST/JSON
+	DialOptions   []grpc.DialOption        // used by the gateway to reach the GRPC server; defaults to insecure
+	MuxOptions    []runtime.ServeMuxOption // configure the gateway's *runtime.ServeMux
+}
+
+// New returns an ifrit.Runner which serves a GRPC server, as described by grpc_server.NewGRPCServer, and a
+// grpc-gateway reverse proxy to it, as described by config.
+//
+// The GRPC server is started first, and the gateway is started once the GRPC server is ready.  The runner becomes
+// ready once both are serving.  On shutdown the gateway is drained first, followed by the GRPC server.  If either
+// exits, the other is shut down, and the runner exits with a grouper.ErrorTrace describing both.
+func New(grpcListenAddress string, tlsConfig *tls.Config, handler, serverRegistrar interface{}, config Config, opts ...grpc_server.Option) ifrit.Runner {
+	gateway := &gatewayRunner{config: config}
+
+	opts = append(opts, grpc_server.WithAddressCallback(gateway.setEndpoint))
+	grpcRunner := grpc_server.NewGRPCServer(grpcListenAddress, tlsConfig, handler, serverRegistrar, opts...)
+
+	return grouper.NewOrdered(os.Interrupt, grouper.Members{
+		{Name: "grpc", Runner: grpcRunner},
+		{Name: "gateway", Runner: gateway},
+	})
+}
+
+type gatewayRunner struct {
+	config   Config
+	endpoint string
+}
+
+// setEndpoint is called by the GRPC runner with each address it is bound to, before it becomes ready, which happens
+// before the gateway runner is started.  The gateway uses the first, which is the GRPC listen address.
+func (g *gatewayRunner) setEndpoint(addr net.Addr) {
+	if g.endpoint != "" {
+		return
+	}
+	if addr.Network() == "unix" {
+		g.endpoint = "unix://" + addr.String()
+		return
+	}
+	g.endpoint = addr.String()
+}
+
+func (g *gatewayRunner) Run(signals <-chan os.Signal, ready chan<- struct{}) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	dialOptions := g.config.DialOptions
+	if len(dialOptions) == 0 {
+		dialOptions = []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+	}
+
+	endpoint := g.endpoint
+	g.endpoint = ""
+
+	mux := runtime.NewServeMux(g.config.MuxOptions...)
+	for _, register := range g.config.Registrars {
+		err := register(ctx, mux, endpoint, dialOptions)
+		if err != nil {
+			return err
+		}
+	}
+
+	var server ifrit.Runner
+	if g.config.TLSConfig != nil {
+		server = http_server.NewTLSServer(g.config.ListenAddress, mux, g.config.TLSConfig)
+	} else {
+		server = http_server.New(g.config.ListenAddress, mux)
+	}
+	return server.Run(signals, ready)
+}
@@ -0,0 +1,13 @@
+package gateway_server_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestGatewayServer(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Gateway Server Suite")
+}
@@ -0,0 +1,121 @@
+package gateway_server_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/tedsuo/ifrit"
+	"github.com/tedsuo/ifrit/ginkgomon"
+	"github.com/tedsuo/ifrit/grpc_server/gateway_server"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/examples/helloworld/helloworld"
+)
+
+var _ = Describe("GatewayServer", func() {
+	var (
+		grpcAddress   string
+		restAddress   string
+		config        gateway_server.Config
+		serverProcess ifrit.Process
+	)
+
+	BeforeEach(func() {
+		grpcAddress = fmt.Sprintf("127.0.0.1:%d", 10500+GinkgoParallelNode())
+		restAddress = fmt.Sprintf("127.0.0.1:%d", 10600+GinkgoParallelNode())
+		config = gateway_server.Config{
+			ListenAddress: restAddress,
+			Registrars:    []gateway_server.Registrar{registerGreeterHandler},
+		}
+	})
+
+	Context("when both servers start", func() {
+		JustBeforeEach(func() {
+			runner := gateway_server.New(grpcAddress, nil, &server{}, helloworld.RegisterGreeterServer, config)
+			serverProcess = ginkgomon.Invoke(runner)
+		})
+
+		AfterEach(func() {
+			ginkgomon.Kill(serverProcess)
+		})
+
+		It("serves GRPC requests", func() {
+			conn, err := grpc.Dial(grpcAddress, grpc.WithInsecure())
+			Expect(err).NotTo(HaveOccurred())
+
+			reply, err := helloworld.NewGreeterClient(conn).SayHello(context.Background(), &helloworld.HelloRequest{Name: "Fred"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(reply.Message).To(Equal("Hello Fred"))
+		})
+
+		It("serves REST requests through the gateway", func() {
+			resp, err := http.Get("http://" + restAddress + "/v1/hello/Fred")
+			Expect(err).NotTo(HaveOccurred())
+			defer resp.Body.Close()
+
+			body, err := ioutil.ReadAll(resp.Body)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(body)).To(Equal("Hello Fred"))
+		})
+
+		It("shuts both servers down when signaled", func() {
+			serverProcess.Signal(os.Interrupt)
+			Eventually(serverProcess.Wait()).Should(Receive(BeNil()))
+
+			_, err := http.Get("http://" + restAddress + "/v1/hello/Fred")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("when the gateway fails to register", func() {
+		BeforeEach(func() {
+			config.Registrars = append(config.Registrars, func(context.Context, *runtime.ServeMux, string, []grpc.DialOption) error {
+				return errors.New("registration failed")
+			})
+		})
+
+		It("shuts down the GRPC server and exits with an error", func() {
+			runner := gateway_server.New(grpcAddress, nil, &server{}, helloworld.RegisterGreeterServer, config)
+			process := ifrit.Background(runner)
+
+			var err error
+			Eventually(process.Wait()).Should(Receive(&err))
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("registration failed"))
+		})
+	})
+})
+
+// registerGreeterHandler stands in for the code protoc-gen-grpc-gateway would generate for helloworld.
+func registerGreeterHandler(ctx context.Context, mux *runtime.ServeMux, endpoint string, opts []grpc.DialOption) error {
+	conn, err := grpc.Dial(endpoint, opts...)
+	if err != nil {
+		return err
+	}
+	go func() {
+		<-ctx.Done()
+		conn.Close()
+	}()
+
+	client := helloworld.NewGreeterClient(conn)
+	return mux.HandlePath("GET", "/v1/hello/{name}", func(w http.ResponseWriter, r *http.Request, params map[string]string) {
+		reply, err := client.SayHello(r.Context(), &helloworld.HelloRequest{Name: params["name"]})
+		if err != nil {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte(reply.Message))
+	})
+}
+
+type server struct{}
+
+func (s *server) SayHello(ctx context.Context, in *helloworld.HelloRequest) (*helloworld.HelloReply, error) {
+	return &helloworld.HelloReply{Message: "Hello " + in.Name}, nil
+}
@@ -0,0 +1,32 @@
+package grpc_server
+
+import (
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// newHealthServer returns a grpc.health.v1.Health implementation.  Every service starts out NOT_SERVING until
+// reportServing is called.
+func newHealthServer() *health.Server {
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("", grpc_health_v1.HealthCheckResponse_NOT_SERVING)
+	return healthServer
+}
+
+// reportServing flips the overall status, and the status of every service registered on server, to SERVING.
+func reportServing(server Server, healthServer *health.Server) {
+	setServingStatus(server, healthServer, grpc_health_v1.HealthCheckResponse_SERVING)
+}
+
+// reportNotServing flips the same statuses back to NOT_SERVING, for servers whose listeners stop serving while the
+// runner is running.
+func reportNotServing(server Server, healthServer *health.Server) {
+	setServingStatus(server, healthServer, grpc_health_v1.HealthCheckResponse_NOT_SERVING)
+}
+
+func setServingStatus(server Server, healthServer *health.Server, status grpc_health_v1.HealthCheckResponse_ServingStatus) {
+	healthServer.SetServingStatus("", status)
+	for name := range server.GetServiceInfo() {
+		healthServer.SetServingStatus(name, status)
+	}
+}
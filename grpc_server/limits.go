@@ -0,0 +1,126 @@
+package grpc_server
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// connectionLimiter bounds the number of connections open across all of the runner's listeners.
+type connectionLimiter struct {
+	slots    chan struct{}
+	onReject func(remote net.Addr)
+}
+
+func newConnectionLimiter(max int, onReject func(net.Addr)) *connectionLimiter {
+	return &connectionLimiter{
+		slots:    make(chan struct{}, max),
+		onReject: onReject,
+	}
+}
+
+func (l *connectionLimiter) Wrap(lis net.Listener) net.Listener {
+	return &limitedListener{Listener: lis, limiter: l}
+}
+
+type limitedListener struct {
+	net.Listener
+	limiter *connectionLimiter
+}
+
+// Accept closes connections which arrive while the limit is reached, rather than queueing them, so that clients fail
+// fast and may retry elsewhere.
+func (l *limitedListener) Accept() (net.Conn, error) {
+	for {
+		conn, err := l.Listener.Accept()
+		if err != nil {
+			return nil, err
+		}
+
+		select {
+		case l.limiter.slots <- struct{}{}:
+			return &limitedConn{Conn: conn, release: l.limiter.release}, nil
+		default:
+			if l.limiter.onReject != nil {
+				l.limiter.onReject(conn.RemoteAddr())
+			}
+			conn.Close()
+		}
+	}
+}
+
+func (l *connectionLimiter) release() {
+	<-l.slots
+}
+
+type limitedConn struct {
+	net.Conn
+	release func()
+	once    sync.Once
+}
+
+func (c *limitedConn) Close() error {
+	c.once.Do(c.release)
+	return c.Conn.Close()
+}
+
+// rpcLimiter bounds the number of RPCs in flight across all of the runner's servers.  RPCs beyond the limit fail with
+// RESOURCE_EXHAUSTED.  Health checks are exempt, so that a loaded server is not mistaken for an unhealthy one.
+type rpcLimiter struct {
+	slots    chan struct{}
+	onReject func(fullMethod string)
+}
+
+const healthMethodPrefix = "/grpc.health.v1.Health/"
+
+func newRPCLimiter(max int, onReject func(string)) *rpcLimiter {
+	return &rpcLimiter{
+		slots:    make(chan struct{}, max),
+		onReject: onReject,
+	}
+}
+
+func (l *rpcLimiter) acquire(fullMethod string) (release func(), err error) {
+	if strings.HasPrefix(fullMethod, healthMethodPrefix) {
+		return func() {}, nil
+	}
+
+	select {
+	case l.slots <- struct{}{}:
+		return l.release, nil
+	default:
+		if l.onReject != nil {
+			l.onReject(fullMethod)
+		}
+		return nil, status.Errorf(codes.ResourceExhausted, "too many concurrent RPCs; %s rejected", fullMethod)
+	}
+}
+
+func (l *rpcLimiter) release() {
+	<-l.slots
+}
+
+func (l *rpcLimiter) InterceptUnary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	release, err := l.acquire(info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	return handler(ctx, req)
+}
+
+func (l *rpcLimiter) InterceptStream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	release, err := l.acquire(info.FullMethod)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	return handler(srv, ss)
+}
@@ -0,0 +1,67 @@
+package grpc_server
+
+import (
+	"net"
+	"strings"
+
+	"github.com/tedsuo/ifrit/socket_activation"
+)
+
+const (
+	unixScheme    = "unix://"
+	systemdScheme = "systemd://"
+)
+
+// listen opens a listener for address.  Addresses of the form "unix:///path/to/socket" listen on a Unix domain
+// socket, which is removed once the listener is closed.  Addresses of the form "systemd://" or "systemd://name" use
+// the first socket, or the socket with the given FileDescriptorName=, passed by systemd socket activation.  All other
+// addresses listen on TCP.
+func listen(address string) (net.Listener, error) {
+	if strings.HasPrefix(address, systemdScheme) {
+		return socket_activation.Listener(strings.TrimPrefix(address, systemdScheme))
+	}
+
+	if !strings.HasPrefix(address, unixScheme) {
+		return net.Listen("tcp", address)
+	}
+
+	lis, err := net.Listen("unix", strings.TrimPrefix(address, unixScheme))
+	if err != nil {
+		return nil, err
+	}
+	lis.(*net.UnixListener).SetUnlinkOnClose(true)
+	return lis, nil
+}
+
+// listenAll returns the runner's listener, or a new listener for its listen address, followed by a listener for each
+// additional address.  If any listener cannot be opened, the ones already opened are closed.
+func (s *grpcServerRunner) listenAll() ([]net.Listener, error) {
+	listeners := make([]net.Listener, 0, len(s.extraAddresses)+1)
+
+	lis := s.listener
+	if lis == nil {
+		var err error
+		lis, err = listen(s.listenAddress)
+		if err != nil {
+			return nil, err
+		}
+	}
+	listeners = append(listeners, lis)
+
+	for _, address := range s.extraAddresses {
+		lis, err := listen(address)
+		if err != nil {
+			closeListeners(listeners)
+			return nil, err
+		}
+		listeners = append(listeners, lis)
+	}
+
+	return listeners, nil
+}
+
+func closeListeners(listeners []net.Listener) {
+	for _, lis := range listeners {
+		lis.Close()
+	}
+}
@@ -0,0 +1,243 @@
+package grpc_server
+
+import (
+	"crypto/x509"
+	"net"
+	"os"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/stats"
+)
+
+// An Option configures optional behavior of the runner returned by NewGRPCServer.
+type Option func(*grpcServerRunner)
+
+// WithServerOptions forwards opts to grpc.NewServer when the runner is invoked.  They are
+// applied after the transport credentials derived from the runner's tlsConfig.
+func WithServerOptions(opts ...grpc.ServerOption) Option {
+	return func(s *grpcServerRunner) {
+		s.serverOptions = append(s.serverOptions, opts...)
+	}
+}
+
+// WithUnaryInterceptors appends interceptors to the server's chain of unary interceptors.  Interceptors run in the
+// order they are given, across all uses of this option, with the first being the outermost.
+func WithUnaryInterceptors(interceptors ...grpc.UnaryServerInterceptor) Option {
+	return func(s *grpcServerRunner) {
+		s.unaryChain = append(s.unaryChain, interceptors...)
+	}
+}
+
+// WithStreamInterceptors appends interceptors to the server's chain of stream interceptors.  Interceptors run in the
+// order they are given, across all uses of this option, with the first being the outermost.
+func WithStreamInterceptors(interceptors ...grpc.StreamServerInterceptor) Option {
+	return func(s *grpcServerRunner) {
+		s.streamChain = append(s.streamChain, interceptors...)
+	}
+}
+
+// WithKeepaliveParams configures the server's keepalive and connection age parameters, such as how long an idle
+// connection is kept open.
+func WithKeepaliveParams(params keepalive.ServerParameters) Option {
+	return WithServerOptions(grpc.KeepaliveParams(params))
+}
+
+// WithKeepaliveEnforcementPolicy configures how the server polices keepalive pings sent by clients.  Clients which
+// violate the policy have their connections closed.
+func WithKeepaliveEnforcementPolicy(policy keepalive.EnforcementPolicy) Option {
+	return WithServerOptions(grpc.KeepaliveEnforcementPolicy(policy))
+}
+
+// WithStatsHandler installs handler on the server, to be notified of every connection and RPC.  This is how
+// instrumentation such as otelgrpc.NewServerHandler() is attached.  The option may be given more than once.
+func WithStatsHandler(handler stats.Handler) Option {
+	return WithServerOptions(grpc.StatsHandler(handler))
+}
+
+// WithService registers an additional service on the server.  handler and serverRegistrar follow the same rules, and
+// are type checked in the same manner, as the ones passed to NewGRPCServer.
+func WithService(handler, serverRegistrar interface{}) Option {
+	return func(s *grpcServerRunner) {
+		s.services = append(s.services, service{handler: handler, serverRegistrar: serverRegistrar})
+	}
+}
+
+// WithRegistration invokes register with the *grpc.Server after all services have been registered, and before the
+// server begins serving.  Use it to register services which are not generated by protoc, or to otherwise prepare
+// the server.
+func WithRegistration(register func(*grpc.Server)) Option {
+	return func(s *grpcServerRunner) {
+		s.registrations = append(s.registrations, register)
+	}
+}
+
+// WithHealthCheck registers the standard grpc.health.v1.Health service on the server.  The overall status, and the
+// status of every registered service, is NOT_SERVING until the runner is ready, SERVING while it is running, and
+// NOT_SERVING again once the runner begins a graceful shutdown.
+func WithHealthCheck() Option {
+	return func(s *grpcServerRunner) {
+		s.healthCheck = true
+	}
+}
+
+// WithReflection registers the gRPC server reflection service, allowing tools such as grpcurl to discover the
+// services the server provides.
+func WithReflection() Option {
+	return func(s *grpcServerRunner) {
+		s.reflection = true
+	}
+}
+
+// WithChannelz registers the channelz service, which exposes live connection and RPC state of the process for
+// debugging.
+func WithChannelz() Option {
+	return func(s *grpcServerRunner) {
+		s.channelz = true
+	}
+}
+
+// WithDrainTimeout bounds how long a graceful shutdown may wait for in-flight RPCs to complete.  Once the timeout
+// elapses the server is stopped forcefully, closing all open connections, and the runner exits with ErrForcedStop.
+// By default the runner waits indefinitely.
+func WithDrainTimeout(timeout time.Duration) Option {
+	return func(s *grpcServerRunner) {
+		s.drainTimeout = timeout
+	}
+}
+
+// WithDrainPolicy drains in-flight RPCs according to policy, which may cancel long-lived streams before stopping the
+// server forcefully.  It takes precedence over WithDrainTimeout.
+func WithDrainPolicy(policy DrainPolicy) Option {
+	return func(s *grpcServerRunner) {
+		s.drainPolicy = &policy
+	}
+}
+
+// WithShutdownStartHook invokes hook once the runner begins shutting down, before the server stops accepting new
+// RPCs and begins draining in-flight ones.  The option may be given more than once.
+func WithShutdownStartHook(hook func()) Option {
+	return func(s *grpcServerRunner) {
+		s.shutdownStart = append(s.shutdownStart, hook)
+	}
+}
+
+// WithShutdownCompleteHook invokes hook with the runner's exit error once the server has stopped.  The option may be
+// given more than once.
+func WithShutdownCompleteHook(hook func(err error)) Option {
+	return func(s *grpcServerRunner) {
+		s.shutdownComplete = append(s.shutdownComplete, hook)
+	}
+}
+
+// WithAdditionalAddresses serves the same server on each of addresses, in addition to the listen address.  Addresses
+// take the same forms as the listen address.  The runner becomes ready once every address is bound, and fails if any
+// cannot be bound.
+func WithAdditionalAddresses(addresses ...string) Option {
+	return func(s *grpcServerRunner) {
+		s.extraAddresses = append(s.extraAddresses, addresses...)
+	}
+}
+
+// WithInsecureAddress additionally serves the same services without TLS on address, for instance while clients
+// migrate to TLS.  Address takes the same forms as the listen address.  The insecure server shares every other option
+// with the TLS server, except for client certificate verification, and drains independently of it during shutdown.
+func WithInsecureAddress(address string) Option {
+	return func(s *grpcServerRunner) {
+		s.insecureAddress = address
+	}
+}
+
+// WithAddressCallback invokes callback with each address the server is bound to, starting with the listen address,
+// before the runner becomes ready.  This allows the port chosen for a ":0" listen address to be discovered.  The
+// option may be given more than once.
+func WithAddressCallback(callback func(net.Addr)) Option {
+	return func(s *grpcServerRunner) {
+		s.addressCallbacks = append(s.addressCallbacks, callback)
+	}
+}
+
+// WithCertificateFiles serves the certificate in the certFile/keyFile pair, and reloads it without interrupting the
+// server whenever either file changes.  The files are checked for changes every pollInterval; if pollInterval is
+// zero they are only reloaded when the runner receives its reload signal, see WithReloadSignal.  Connections which are
+// already established keep using the certificate they were opened with.  If the pair cannot be loaded when the
+// runner is invoked, the runner exits with an error; if it cannot be loaded later, the previous certificate remains in
+// use.
+//
+// The server runs with TLS when this option is used, even if no tlsConfig was given.  Any certificates in the
+// tlsConfig are ignored.
+func WithCertificateFiles(certFile, keyFile string, pollInterval time.Duration) Option {
+	return func(s *grpcServerRunner) {
+		s.certFiles = &certificateFiles{
+			certFile:     certFile,
+			keyFile:      keyFile,
+			pollInterval: pollInterval,
+		}
+	}
+}
+
+// WithClientCAs requires clients to present a certificate signed by one of the CAs in pool.  It requires the server
+// to run with TLS, see NewGRPCServer and WithCertificateFiles.
+func WithClientCAs(pool *x509.CertPool) Option {
+	return func(s *grpcServerRunner) {
+		s.clientAuth.caPool = pool
+	}
+}
+
+// WithClientCertificateVerifier requires clients to present a certificate, and calls verify with it once its chain
+// has been verified.  A non-nil error from verify rejects the connection.  The option may be given more than once,
+// in which case every verifier must accept the certificate.  Chains are verified against the pool given by
+// WithClientCAs, or by a TLS config which itself requires and verifies client certificates; without either, the
+// runner exits with a ValidationError.
+func WithClientCertificateVerifier(verify func(cert *x509.Certificate) error) Option {
+	return func(s *grpcServerRunner) {
+		s.clientAuth.verifiers = append(s.clientAuth.verifiers, verify)
+	}
+}
+
+// WithAllowedSPIFFEIDs only accepts clients whose certificate carries one of ids, such as
+// "spiffe://example.org/service", as a URI SAN.  Like WithClientCertificateVerifier, it requires WithClientCAs.
+func WithAllowedSPIFFEIDs(ids ...string) Option {
+	return WithClientCertificateVerifier(spiffeIDVerifier(ids))
+}
+
+// WithReloadSignal designates a signal, typically syscall.SIGHUP, which causes the runner to reload the certificate
+// files given by WithCertificateFiles and continue serving, rather than shut down.
+func WithReloadSignal(signal os.Signal) Option {
+	return func(s *grpcServerRunner) {
+		s.reloadSignal = signal
+	}
+}
+
+// WithServerFactory creates the runner's servers using factory rather than grpc.NewServer.  Services registered on
+// such servers must have registrars which accept a grpc.ServiceRegistrar, and WithRegistration cannot be used.
+func WithServerFactory(factory ServerFactory) Option {
+	return func(s *grpcServerRunner) {
+		s.serverFactory = &factory
+	}
+}
+
+// WithMaxConcurrentStreams limits the number of concurrent streams, and so RPCs, each client connection may open.
+// Clients queue RPCs beyond the limit until a stream becomes available.
+func WithMaxConcurrentStreams(max uint32) Option {
+	return WithServerOptions(grpc.MaxConcurrentStreams(max))
+}
+
+// WithMaxConnections limits the number of client connections open at once, across all of the runner's listeners.
+// Connections beyond the limit are closed as soon as they are accepted, and onReject, if non-nil, is called with
+// the client's address.
+func WithMaxConnections(max int, onReject func(remote net.Addr)) Option {
+	return func(s *grpcServerRunner) {
+		s.connLimiter = newConnectionLimiter(max, onReject)
+	}
+}
+
+// WithMaxConcurrentRPCs limits the number of RPCs in flight at once, across all clients.  RPCs beyond the limit fail
+// immediately with RESOURCE_EXHAUSTED, and onReject, if non-nil, is called with the RPC's full method name.  Health
+// checks are not limited.
+func WithMaxConcurrentRPCs(max int, onReject func(fullMethod string)) Option {
+	return func(s *grpcServerRunner) {
+		s.rpcLimiter = newRPCLimiter(max, onReject)
+	}
+}
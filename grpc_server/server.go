@@ -4,6 +4,7 @@ import (
 	"crypto/tls"
 	"net"
 	"os"
+	"time"
 
 	"fmt"
 	"reflect"
@@ -11,20 +12,70 @@ import (
 	"errors"
 
 	"github.com/tedsuo/ifrit"
+	"github.com/tedsuo/ifrit/certificate_reloader"
 	"google.golang.org/grpc"
+	channelzservice "google.golang.org/grpc/channelz/service"
 	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
 )
 
 type grpcServerRunner struct {
-	listenAddress   string
+	listenAddress    string
+	listener         net.Listener
+	extraAddresses   []string
+	insecureAddress  string
+	handler          interface{}
+	serverRegistrar  interface{}
+	tlsConfig        *tls.Config
+	serverOptions    []grpc.ServerOption
+	unaryChain       []grpc.UnaryServerInterceptor
+	streamChain      []grpc.StreamServerInterceptor
+	services         []service
+	registrations    []func(*grpc.Server)
+	healthCheck      bool
+	reflection       bool
+	channelz         bool
+	drainTimeout     time.Duration
+	drainPolicy      *DrainPolicy
+	addressCallbacks []func(net.Addr)
+	certFiles        *certificateFiles
+	clientAuth       clientAuth
+	reloadSignal     os.Signal
+	shutdownStart    []func()
+	shutdownComplete []func(error)
+	serverFactory    *ServerFactory
+	connLimiter      *connectionLimiter
+	rpcLimiter       *rpcLimiter
+
+	// typedRegistration replaces handler and serverRegistrar for runners created by NewTypedGRPCServer, and
+	// typedRegistrar is the type of the server it registers on.
+	typedRegistration func(grpc.ServiceRegistrar)
+	typedRegistrar    reflect.Type
+}
+
+// ErrForcedStop is returned by the runner when in-flight RPCs did not complete within the drain timeout, and the
+// server was stopped forcefully.  See WithDrainTimeout.
+var ErrForcedStop = errors.New("grpc server did not drain in time and was stopped forcefully")
+
+type certificateFiles struct {
+	certFile     string
+	keyFile      string
+	pollInterval time.Duration
+}
+
+type service struct {
 	handler         interface{}
 	serverRegistrar interface{}
-	tlsConfig       *tls.Config
 }
 
 // NewGRPCServer returns an ifrit.Runner for your GRPC server process, given artifacts normally generated from a
 // protobuf service definition by protoc.
 //
+// listenAddress is a TCP address, a Unix domain socket given as "unix:///path/to/socket", or a socket passed by
+// systemd socket activation given as "systemd://" or "systemd://name".
+//
 // tlsConfig is optional.  If nil the server will run insecure.
 //
 // handler must be an implementation of the interface generated by protoc.
@@ -33,88 +84,423 @@ type grpcServerRunner struct {
 //
 // Type checking occurs at runtime.  Poorly typed `handler` or `serverRegistrar` parameters will result in an error
 // when the Runner is Invoked.
-func NewGRPCServer(listenAddress string, tlsConfig *tls.Config, handler, serverRegistrar interface{}) ifrit.Runner {
-	return &grpcServerRunner{
+//
+// opts are optional, and configure the underlying *grpc.Server; see Option.
+func NewGRPCServer(listenAddress string, tlsConfig *tls.Config, handler, serverRegistrar interface{}, opts ...Option) ifrit.Runner {
+	runner := &grpcServerRunner{
 		listenAddress:   listenAddress,
 		handler:         handler,
 		serverRegistrar: serverRegistrar,
 		tlsConfig:       tlsConfig,
 	}
+	for _, opt := range opts {
+		opt(runner)
+	}
+	return runner
+}
+
+// NewGRPCServerFromListener returns an ifrit.Runner which serves on lis rather than opening a listener of its own.
+// The runner takes ownership of lis, and closes it when it exits.  All other parameters behave as they do for
+// NewGRPCServer.
+func NewGRPCServerFromListener(lis net.Listener, tlsConfig *tls.Config, handler, serverRegistrar interface{}, opts ...Option) ifrit.Runner {
+	runner := NewGRPCServer(lis.Addr().String(), tlsConfig, handler, serverRegistrar, opts...).(*grpcServerRunner)
+	runner.listener = lis
+	return runner
+}
+
+// NewTypedGRPCServer is the type safe equivalent of NewGRPCServer.  Mismatched `handler` and `serverRegistrar`
+// parameters are reported by the compiler, and no reflection is used to register the service.
+//
+// R is the first parameter of the "RegisterXXXServer" function generated by protoc, which is either *grpc.Server or
+// grpc.ServiceRegistrar depending on the version of the generator.  Type inference requires `handler` to have the
+// service interface type, e.g. helloworld.GreeterServer(&server{}), unless the type parameters are given
+// explicitly.
+func NewTypedGRPCServer[R grpc.ServiceRegistrar, S any](listenAddress string, tlsConfig *tls.Config, handler S, serverRegistrar func(R, S), opts ...Option) ifrit.Runner {
+	runner := NewGRPCServer(listenAddress, tlsConfig, nil, nil, opts...).(*grpcServerRunner)
+	runner.typedRegistration = func(server grpc.ServiceRegistrar) {
+		serverRegistrar(server.(R), handler)
+	}
+	runner.typedRegistrar = reflect.TypeOf((*R)(nil)).Elem()
+	return runner
+}
+
+// NewGRPCServerE behaves like NewGRPCServer, but type checks `handler` and `serverRegistrar`, as well as those of
+// any additional services, immediately.  Poorly typed parameters result in a ValidationError rather than a Runner.
+func NewGRPCServerE(listenAddress string, tlsConfig *tls.Config, handler, serverRegistrar interface{}, opts ...Option) (ifrit.Runner, error) {
+	runner := NewGRPCServer(listenAddress, tlsConfig, handler, serverRegistrar, opts...).(*grpcServerRunner)
+	err := runner.Validate()
+	if err != nil {
+		return nil, err
+	}
+	return runner, nil
+}
+
+// A ValidationError describes a `handler` or `serverRegistrar` which cannot be used to register a service, or
+// options which cannot be used together.
+type ValidationError struct {
+	Reason string
+}
+
+func (e ValidationError) Error() string {
+	return "NewGRPCServer: " + e.Reason
+}
+
+func validationError(format string, args ...interface{}) error {
+	return ValidationError{Reason: fmt.Sprintf(format, args...)}
 }
 
 func (s *grpcServerRunner) Validate() error {
-	if s.serverRegistrar == nil || s.handler == nil {
-		return errors.New("NewGRPCServer: `serverRegistrar` and `handler` must be non nil")
+	var err error
+	if s.serverFactory != nil {
+		err = s.validateFactory()
+		if err != nil {
+			return err
+		}
+	}
+
+	if s.typedRegistration == nil {
+		err = s.validateService(s.handler, s.serverRegistrar)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, svc := range s.services {
+		err = s.validateService(svc.handler, svc.serverRegistrar)
+		if err != nil {
+			return err
+		}
+	}
+
+	return s.clientAuth.validate(s.tlsConfig)
+}
+
+func (s *grpcServerRunner) validateService(handler, serverRegistrar interface{}) error {
+	if serverRegistrar == nil || handler == nil {
+		return validationError("`serverRegistrar` and `handler` must be non nil")
 	}
 
-	vServerRegistrar := reflect.ValueOf(s.serverRegistrar)
-	vHandler := reflect.ValueOf(s.handler)
+	vServerRegistrar := reflect.ValueOf(serverRegistrar)
+	vHandler := reflect.ValueOf(handler)
 
 	registrarType := vServerRegistrar.Type()
 	handlerType := vHandler.Type()
 
 	// registrar type must be `func(*grpc.Server, X)`
 	if registrarType.Kind() != reflect.Func {
-		return fmt.Errorf("NewGRPCServer: `serverRegistrar` should be %s but is %s",
+		return validationError("`serverRegistrar` should be %s but is %s",
 			reflect.Func, registrarType.Kind())
 	}
 	if registrarType.NumIn() != 2 {
-		return fmt.Errorf("NewGRPCServer: `serverRegistrar` should have 2 parameters but it has %d parameters",
+		return validationError("`serverRegistrar` should have 2 parameters but it has %d parameters",
 			registrarType.NumIn())
 	}
 	if registrarType.NumOut() != 0 {
-		return fmt.Errorf("NewGRPCServer: `serverRegistrar` should return no value but it returns %d values",
+		return validationError("`serverRegistrar` should return no value but it returns %d values",
 			registrarType.NumOut())
 	}
 
-	// registrar's first parameter type must be a grpc server
-	if reflect.TypeOf((*grpc.Server)(nil)) != registrarType.In(0) {
-		return fmt.Errorf("NewGRPCServer: type of `serverRegistrar`'s first parameter must be `*grpc.Server` but is %s",
+	// registrar's first parameter type must be a grpc server, or any ServiceRegistrar for servers created by a
+	// ServerFactory
+	if s.serverFactory != nil {
+		if serviceRegistrarType != registrarType.In(0) {
+			return validationError("type of `serverRegistrar`'s first parameter must be `grpc.ServiceRegistrar` when using a ServerFactory but is %s",
+				registrarType.In(0))
+		}
+	} else if reflect.TypeOf((*grpc.Server)(nil)) != registrarType.In(0) {
+		return validationError("type of `serverRegistrar`'s first parameter must be `*grpc.Server` but is %s",
 			registrarType.In(0))
 	}
 
 	// registrar's second parameter type must be implemented by handler type.
 	if registrarType.In(1).Kind() != reflect.Interface || !handlerType.Implements(registrarType.In(1)) {
-		return fmt.Errorf("NewGRPCServer: type of `serverRegistrar`'s second parameter %s is not implemented by `handler` type %s",
+		return validationError("type of `serverRegistrar`'s second parameter %s is not implemented by `handler` type %s",
 			registrarType.In(1), handlerType)
 	}
 	return nil
 }
 
 func (s *grpcServerRunner) Run(signals <-chan os.Signal, ready chan<- struct{}) error {
+	// a listener the runner was given is closed if the runner fails before it listens; listenAll closes it once it
+	// has been handed over
+	ownsListener := s.listener != nil
+	defer func() {
+		if ownsListener {
+			s.listener.Close()
+		}
+	}()
+
 	err := s.Validate()
 	if err != nil {
 		return err
 	}
 
-	vServerRegistrar := reflect.ValueOf(s.serverRegistrar)
-	vHandler := reflect.ValueOf(s.handler)
+	tlsConfig := s.tlsConfig
+	var reloader *certificate_reloader.Reloader
+	if s.certFiles != nil {
+		reloader, err = certificate_reloader.New(s.certFiles.certFile, s.certFiles.keyFile)
+		if err != nil {
+			return err
+		}
+		tlsConfig = reloader.TLSConfig(tlsConfig)
 
-	lis, err := net.Listen("tcp", s.listenAddress)
+		if s.certFiles.pollInterval > 0 {
+			done := make(chan struct{})
+			defer close(done)
+			go reloader.Watch(s.certFiles.pollInterval, done)
+		}
+	}
+	tlsConfig = s.clientAuth.apply(tlsConfig)
+
+	ownsListener = false
+	listeners, err := s.listenAll()
 	if err != nil {
 		return err
 	}
 
+	var insecureListener net.Listener
+	if s.insecureAddress != "" {
+		insecureListener, err = listen(s.insecureAddress)
+		if err != nil {
+			closeListeners(listeners)
+			return err
+		}
+	}
+
+	if s.connLimiter != nil {
+		for i, lis := range listeners {
+			listeners[i] = s.connLimiter.Wrap(lis)
+		}
+		if insecureListener != nil {
+			insecureListener = s.connLimiter.Wrap(insecureListener)
+		}
+	}
+
+	var healthServer *health.Server
+	if s.healthCheck {
+		healthServer = newHealthServer()
+	}
+
+	var serving *servingTracker
+	if s.serverFactory != nil && s.serverFactory.ReportsServing {
+		serving = newServingTracker()
+		for _, lis := range listeners {
+			serving.Watch(lis)
+		}
+		if insecureListener != nil {
+			serving.Watch(insecureListener)
+		}
+	}
+
+	// each server has its own streams, so that draining one cancels only the streams it serves
+	streams := s.newStreamTracker()
+	server, err := s.newServer(tlsConfig, healthServer, streams, serving)
+	if err != nil {
+		closeListeners(listeners)
+		if insecureListener != nil {
+			insecureListener.Close()
+		}
+		return err
+	}
+	servers := []Server{server}
+	trackers := []*streamTracker{streams}
+	errCh := make(chan error, len(listeners)+1)
+	for _, lis := range listeners {
+		go serve(server, lis, errCh)
+	}
+
+	if insecureListener != nil {
+		insecureStreams := s.newStreamTracker()
+		insecureServer, err := s.newServer(nil, healthServer, insecureStreams, serving)
+		if err != nil {
+			server.Stop()
+			insecureListener.Close()
+			return err
+		}
+		servers = append(servers, insecureServer)
+		trackers = append(trackers, insecureStreams)
+		go serve(insecureServer, insecureListener, errCh)
+		listeners = append(listeners, insecureListener)
+	}
+
+	for _, lis := range listeners {
+		for _, callback := range s.addressCallbacks {
+			callback(lis.Addr())
+		}
+	}
+
+	var servingChanged <-chan struct{}
+	if serving != nil {
+		servingChanged = serving.Changed()
+	} else {
+		if healthServer != nil {
+			reportServing(server, healthServer)
+		}
+		close(ready)
+	}
+
+	for stopping := false; !stopping; {
+		select {
+		case <-servingChanged:
+			if !serving.AllServing() {
+				if healthServer != nil {
+					reportNotServing(server, healthServer)
+				}
+				continue
+			}
+			if healthServer != nil {
+				reportServing(server, healthServer)
+			}
+			if ready != nil {
+				close(ready)
+				ready = nil
+			}
+		case signal := <-signals:
+			if s.reloadSignal != nil && signal == s.reloadSignal {
+				if reloader != nil {
+					reloader.Reload()
+				}
+				continue
+			}
+			stopping = true
+		case err = <-errCh:
+			stopping = true
+		}
+	}
+
+	for _, hook := range s.shutdownStart {
+		hook()
+	}
+	if healthServer != nil {
+		healthServer.Shutdown()
+	}
+	stopErr := s.stopAll(servers, trackers)
+	if err == nil {
+		err = stopErr
+	}
+	for _, hook := range s.shutdownComplete {
+		hook(err)
+	}
+	return err
+}
+
+// newServer creates a server with the runner's options and registers every service on it.  If tlsConfig is nil, the
+// server runs insecure.  If streams is non-nil, it intercepts every stream ahead of the configured interceptors, behind
+// only the RPC limiter.  If the runner has a ServerFactory, the server is created by it and reports to serving.
+func (s *grpcServerRunner) newServer(tlsConfig *tls.Config, healthServer *health.Server, streams *streamTracker, serving *servingTracker) (Server, error) {
 	opts := []grpc.ServerOption{}
-	if s.tlsConfig != nil {
-		opts = append(opts, grpc.Creds(credentials.NewTLS(s.tlsConfig)))
+	if tlsConfig != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+	unaryChain := s.unaryChain
+	streamChain := s.streamChain
+	if streams != nil {
+		streamChain = append([]grpc.StreamServerInterceptor{streams.Intercept}, streamChain...)
+	}
+	if s.rpcLimiter != nil {
+		unaryChain = append([]grpc.UnaryServerInterceptor{s.rpcLimiter.InterceptUnary}, unaryChain...)
+		streamChain = append([]grpc.StreamServerInterceptor{s.rpcLimiter.InterceptStream}, streamChain...)
+	}
+	if len(unaryChain) > 0 {
+		opts = append(opts, grpc.ChainUnaryInterceptor(unaryChain...))
+	}
+	if len(streamChain) > 0 {
+		opts = append(opts, grpc.ChainStreamInterceptor(streamChain...))
+	}
+	opts = append(opts, s.serverOptions...)
+
+	var server Server
+	if s.serverFactory != nil {
+		setServing := func(net.Addr, bool) {}
+		if serving != nil {
+			setServing = serving.SetServing
+		}
+
+		var err error
+		server, err = s.serverFactory.New(opts, setServing)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		server = grpc.NewServer(opts...)
+	}
+
+	if s.typedRegistration != nil {
+		s.typedRegistration(server)
+	} else {
+		register(server, s.handler, s.serverRegistrar)
+	}
+	for _, svc := range s.services {
+		register(server, svc.handler, svc.serverRegistrar)
+	}
+	for _, registration := range s.registrations {
+		registration(server.(*grpc.Server))
 	}
-	server := grpc.NewServer(opts...)
-	args := []reflect.Value{reflect.ValueOf(server), vHandler}
-	vServerRegistrar.Call(args)
+	if healthServer != nil {
+		grpc_health_v1.RegisterHealthServer(server, healthServer)
+	}
+	if s.reflection {
+		reflection.Register(server)
+	}
+	if s.channelz {
+		channelzservice.RegisterChannelzServiceToServer(server)
+	}
+	return server, nil
+}
+
+func serve(server Server, lis net.Listener, errCh chan<- error) {
+	errCh <- server.Serve(lis)
+}
 
-	errCh := make(chan error)
+// stopAll stops each server independently, so that one server waiting on its connections to drain does not hold up
+// the others.  trackers holds the streamTracker of each server.
+func (s *grpcServerRunner) stopAll(servers []Server, trackers []*streamTracker) error {
+	errs := make(chan error, len(servers))
+	for i, server := range servers {
+		go func(server Server, streams *streamTracker) {
+			errs <- s.stop(server, streams)
+		}(server, trackers[i])
+	}
+
+	var err error
+	for range servers {
+		stopErr := <-errs
+		if err == nil {
+			err = stopErr
+		}
+	}
+	return err
+}
+
+func (s *grpcServerRunner) stop(server Server, streams *streamTracker) error {
+	if s.drainPolicy != nil {
+		return s.drain(server, streams)
+	}
+	if s.drainTimeout <= 0 {
+		server.GracefulStop()
+		return nil
+	}
+
+	stopped := make(chan struct{})
 	go func() {
-		errCh <- server.Serve(lis)
+		server.GracefulStop()
+		close(stopped)
 	}()
 
-	close(ready)
+	timer := time.NewTimer(s.drainTimeout)
+	defer timer.Stop()
 
 	select {
-	case <-signals:
-	case err = <-errCh:
+	case <-stopped:
+		return nil
+	case <-timer.C:
+		server.Stop()
+		<-stopped
+		return ErrForcedStop
 	}
+}
 
-	server.GracefulStop()
-	return err
+func register(server Server, handler, serverRegistrar interface{}) {
+	args := []reflect.Value{reflect.ValueOf(server), reflect.ValueOf(handler)}
+	reflect.ValueOf(serverRegistrar).Call(args)
 }
@@ -0,0 +1,95 @@
+package grpc_server
+
+import (
+	"net"
+	"reflect"
+	"sync"
+
+	"google.golang.org/grpc"
+)
+
+// A Server is the subset of *grpc.Server used by the runner.  It allows the runner to serve alternative
+// implementations, such as the xDS-enabled server in google.golang.org/grpc/xds.
+type Server interface {
+	grpc.ServiceRegistrar
+	GetServiceInfo() map[string]grpc.ServiceInfo
+	Serve(lis net.Listener) error
+	GracefulStop()
+	Stop()
+}
+
+// A ServerFactory creates the servers used by the runner in place of grpc.NewServer.  See WithServerFactory.
+type ServerFactory struct {
+	// New creates a server from the options assembled by the runner.  If ReportsServing is set, the server must call
+	// setServing whenever the listener with address addr begins or stops serving RPCs.
+	New func(opts []grpc.ServerOption, setServing func(addr net.Addr, serving bool)) (Server, error)
+
+	// ReportsServing withholds readiness, and the SERVING health status, until every listener is reported as
+	// serving.
+	ReportsServing bool
+}
+
+var serviceRegistrarType = reflect.TypeOf((*grpc.ServiceRegistrar)(nil)).Elem()
+
+// validateFactory checks that every service can be registered on a server which is not a *grpc.Server.
+func (s *grpcServerRunner) validateFactory() error {
+	if s.typedRegistration != nil && s.typedRegistrar != serviceRegistrarType {
+		return validationError("type of `serverRegistrar`'s first parameter must be `grpc.ServiceRegistrar` when using a ServerFactory but is %s",
+			s.typedRegistrar)
+	}
+	if len(s.registrations) > 0 {
+		return validationError("WithRegistration cannot be used with a ServerFactory")
+	}
+	return nil
+}
+
+// servingTracker records which listeners a server created by a ServerFactory is serving on.
+type servingTracker struct {
+	lock    sync.Mutex
+	serving map[string]bool
+	changed chan struct{}
+}
+
+func newServingTracker() *servingTracker {
+	return &servingTracker{
+		serving: map[string]bool{},
+		changed: make(chan struct{}, 1),
+	}
+}
+
+// Watch starts tracking lis, which is not serving until reported otherwise.
+func (t *servingTracker) Watch(lis net.Listener) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.serving[lis.Addr().String()] = false
+}
+
+func (t *servingTracker) SetServing(addr net.Addr, serving bool) {
+	t.lock.Lock()
+	t.serving[addr.String()] = serving
+	t.lock.Unlock()
+
+	select {
+	case t.changed <- struct{}{}:
+	default:
+	}
+}
+
+// Changed receives whenever the serving status of a listener may have changed.
+func (t *servingTracker) Changed() <-chan struct{} {
+	return t.changed
+}
+
+// AllServing reports whether every tracked listener is serving.
+func (t *servingTracker) AllServing() bool {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	for _, serving := range t.serving {
+		if !serving {
+			return false
+		}
+	}
+	return true
+}
@@ -2,13 +2,19 @@ package grpc_server_test
 
 import (
 	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"fmt"
+	"io/ioutil"
+	"net"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
 
 	"os"
 	"path"
+	"syscall"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -16,7 +22,16 @@ import (
 	"github.com/tedsuo/ifrit/ginkgomon"
 	"github.com/tedsuo/ifrit/grpc_server"
 	"golang.org/x/net/context"
+	"google.golang.org/grpc/channelz/grpc_channelz_v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
 	"google.golang.org/grpc/examples/helloworld/helloworld"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/stats"
+	"google.golang.org/grpc/status"
 )
 
 var _ = Describe("GRPCServer", func() {
@@ -105,6 +120,947 @@ var _ = Describe("GRPCServer", func() {
 
 	})
 
+	Context("when listening on a unix socket", func() {
+		var socketPath string
+
+		BeforeEach(func() {
+			tmpdir, err := ioutil.TempDir("", "grpc-server-test")
+			Expect(err).NotTo(HaveOccurred())
+			socketPath = path.Join(tmpdir, "grpc.sock")
+
+			runner = grpc_server.NewGRPCServer("unix://"+socketPath, nil, &server{}, helloworld.RegisterGreeterServer)
+		})
+		JustBeforeEach(func() {
+			serverProcess = ginkgomon.Invoke(runner)
+		})
+
+		AfterEach(func() {
+			ginkgomon.Kill(serverProcess)
+			os.RemoveAll(path.Dir(socketPath))
+		})
+
+		It("serves on the socket", func() {
+			conn, err := grpc.Dial("unix://"+socketPath, grpc.WithInsecure())
+			Expect(err).NotTo(HaveOccurred())
+
+			helloClient := helloworld.NewGreeterClient(conn)
+			_, err = helloClient.SayHello(context.Background(), &helloworld.HelloRequest{Name: "Fred"})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("removes the socket file on exit", func() {
+			Expect(socketPath).To(BeAnExistingFile())
+			ginkgomon.Interrupt(serverProcess)
+			Expect(socketPath).NotTo(BeAnExistingFile())
+		})
+	})
+
+	Context("when constructed from an existing listener", func() {
+		var lis net.Listener
+
+		BeforeEach(func() {
+			var err error
+			lis, err = net.Listen("tcp", "127.0.0.1:0")
+			Expect(err).NotTo(HaveOccurred())
+
+			runner = grpc_server.NewGRPCServerFromListener(lis, nil, &server{}, helloworld.RegisterGreeterServer)
+		})
+		JustBeforeEach(func() {
+			serverProcess = ginkgomon.Invoke(runner)
+		})
+
+		AfterEach(func() {
+			ginkgomon.Kill(serverProcess)
+		})
+
+		It("serves on the listener", func() {
+			conn, err := grpc.Dial(lis.Addr().String(), grpc.WithInsecure())
+			Expect(err).NotTo(HaveOccurred())
+
+			helloClient := helloworld.NewGreeterClient(conn)
+			_, err = helloClient.SayHello(context.Background(), &helloworld.HelloRequest{Name: "Fred"})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("closes the listener on exit", func() {
+			ginkgomon.Interrupt(serverProcess)
+			_, err := net.Dial("tcp", lis.Addr().String())
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("when constructed from an existing listener and it fails to start", func() {
+		It("closes the listener", func() {
+			lis, err := net.Listen("tcp", "127.0.0.1:0")
+			Expect(err).NotTo(HaveOccurred())
+
+			runner = grpc_server.NewGRPCServerFromListener(lis, nil, &server{}, 42)
+			err = <-ifrit.Background(runner).Wait()
+			Expect(err).To(BeAssignableToTypeOf(grpc_server.ValidationError{}))
+
+			_, err = lis.Accept()
+			Expect(err).To(MatchError(net.ErrClosed))
+		})
+	})
+
+	Context("when shutdown hooks are provided", func() {
+		var events chan string
+
+		BeforeEach(func() {
+			events = make(chan string, 3)
+			runner = grpc_server.NewGRPCServer(listenAddress, nil, &server{}, helloworld.RegisterGreeterServer,
+				grpc_server.WithHealthCheck(),
+				grpc_server.WithShutdownStartHook(func() {
+					events <- "start"
+				}),
+				grpc_server.WithShutdownCompleteHook(func(err error) {
+					events <- fmt.Sprintf("complete: %v", err)
+				}),
+			)
+		})
+		JustBeforeEach(func() {
+			serverProcess = ginkgomon.Invoke(runner)
+		})
+
+		AfterEach(func() {
+			ginkgomon.Kill(serverProcess)
+		})
+
+		It("invokes them around the drain", func() {
+			conn, err := grpc.Dial(listenAddress, grpc.WithInsecure())
+			Expect(err).NotTo(HaveOccurred())
+
+			ctx, cancel := context.WithCancel(context.Background())
+			stream, err := grpc_health_v1.NewHealthClient(conn).Watch(ctx, &grpc_health_v1.HealthCheckRequest{})
+			Expect(err).NotTo(HaveOccurred())
+			_, err = stream.Recv()
+			Expect(err).NotTo(HaveOccurred())
+
+			Consistently(events).ShouldNot(Receive())
+			serverProcess.Signal(os.Interrupt)
+			Eventually(events).Should(Receive(Equal("start")))
+			Consistently(events).ShouldNot(Receive())
+
+			cancel()
+			Eventually(events).Should(Receive(Equal("complete: <nil>")))
+		})
+	})
+
+	Context("when additional addresses are provided", func() {
+		var (
+			secondAddress string
+			busyListener  net.Listener
+		)
+
+		BeforeEach(func() {
+			secondAddress = fmt.Sprintf("127.0.0.1:%d", 10100+GinkgoParallelNode())
+			busyListener = nil
+			runner = grpc_server.NewGRPCServer(listenAddress, nil, &server{}, helloworld.RegisterGreeterServer,
+				grpc_server.WithAdditionalAddresses(secondAddress),
+			)
+		})
+
+		AfterEach(func() {
+			if busyListener != nil {
+				busyListener.Close()
+			}
+		})
+
+		It("serves on every address", func() {
+			serverProcess = ginkgomon.Invoke(runner)
+			defer ginkgomon.Kill(serverProcess)
+
+			for _, address := range []string{listenAddress, secondAddress} {
+				conn, err := grpc.Dial(address, grpc.WithInsecure())
+				Expect(err).NotTo(HaveOccurred())
+
+				helloClient := helloworld.NewGreeterClient(conn)
+				_, err = helloClient.SayHello(context.Background(), &helloworld.HelloRequest{Name: "Fred"})
+				Expect(err).NotTo(HaveOccurred())
+			}
+		})
+
+		It("fails, releasing the other addresses, when any address cannot be bound", func() {
+			var err error
+			busyListener, err = net.Listen("tcp", secondAddress)
+			Expect(err).NotTo(HaveOccurred())
+
+			process := ifrit.Background(runner)
+			Eventually(process.Wait()).Should(Receive(&err))
+			Expect(err).To(HaveOccurred())
+
+			lis, err := net.Listen("tcp", listenAddress)
+			Expect(err).NotTo(HaveOccurred())
+			lis.Close()
+		})
+	})
+
+	Context("when an insecure address is provided", func() {
+		var insecureAddress string
+
+		BeforeEach(func() {
+			insecureAddress = fmt.Sprintf("127.0.0.1:%d", 10200+GinkgoParallelNode())
+			runner = grpc_server.NewGRPCServer(listenAddress, tlsConfig, &server{}, helloworld.RegisterGreeterServer,
+				grpc_server.WithInsecureAddress(insecureAddress),
+				grpc_server.WithHealthCheck(),
+			)
+		})
+		JustBeforeEach(func() {
+			serverProcess = ginkgomon.Invoke(runner)
+		})
+
+		AfterEach(func() {
+			ginkgomon.Kill(serverProcess)
+		})
+
+		It("serves TLS on the listen address", func() {
+			conn, err := grpc.Dial(listenAddress, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
+			Expect(err).NotTo(HaveOccurred())
+
+			helloClient := helloworld.NewGreeterClient(conn)
+			_, err = helloClient.SayHello(context.Background(), &helloworld.HelloRequest{Name: "Fred"})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("serves plaintext on the insecure address", func() {
+			conn, err := grpc.Dial(insecureAddress, grpc.WithInsecure())
+			Expect(err).NotTo(HaveOccurred())
+
+			helloClient := helloworld.NewGreeterClient(conn)
+			_, err = helloClient.SayHello(context.Background(), &helloworld.HelloRequest{Name: "Fred"})
+			Expect(err).NotTo(HaveOccurred())
+
+			resp, err := grpc_health_v1.NewHealthClient(conn).Check(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.Status).To(Equal(grpc_health_v1.HealthCheckResponse_SERVING))
+		})
+
+		It("stops serving on both addresses when signaled", func() {
+			ginkgomon.Interrupt(serverProcess)
+
+			for _, address := range []string{listenAddress, insecureAddress} {
+				lis, err := net.Listen("tcp", address)
+				Expect(err).NotTo(HaveOccurred())
+				lis.Close()
+			}
+		})
+	})
+
+	Context("when listening on an ephemeral port", func() {
+		var boundAddress net.Addr
+
+		BeforeEach(func() {
+			boundAddress = nil
+			runner = grpc_server.NewGRPCServer("127.0.0.1:0", nil, &server{}, helloworld.RegisterGreeterServer,
+				grpc_server.WithAddressCallback(func(addr net.Addr) {
+					boundAddress = addr
+				}),
+			)
+		})
+		JustBeforeEach(func() {
+			serverProcess = ginkgomon.Invoke(runner)
+		})
+
+		AfterEach(func() {
+			ginkgomon.Kill(serverProcess)
+		})
+
+		It("reports the bound address before becoming ready", func() {
+			Expect(boundAddress).NotTo(BeNil())
+			Expect(boundAddress.(*net.TCPAddr).Port).NotTo(BeZero())
+
+			conn, err := grpc.Dial(boundAddress.String(), grpc.WithInsecure())
+			Expect(err).NotTo(HaveOccurred())
+
+			helloClient := helloworld.NewGreeterClient(conn)
+			_, err = helloClient.SayHello(context.Background(), &helloworld.HelloRequest{Name: "Fred"})
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	Context("when constructed with a typed handler", func() {
+		BeforeEach(func() {
+			runner = grpc_server.NewTypedGRPCServer(listenAddress, nil, helloworld.GreeterServer(&server{}), helloworld.RegisterGreeterServer)
+		})
+		JustBeforeEach(func() {
+			serverProcess = ginkgomon.Invoke(runner)
+		})
+
+		AfterEach(func() {
+			ginkgomon.Kill(serverProcess)
+		})
+
+		It("serves on the listen address", func() {
+			conn, err := grpc.Dial(listenAddress, grpc.WithInsecure())
+			Expect(err).NotTo(HaveOccurred())
+
+			helloClient := helloworld.NewGreeterClient(conn)
+			_, err = helloClient.SayHello(context.Background(), &helloworld.HelloRequest{Name: "Fred"})
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	Context("when server options are provided", func() {
+		BeforeEach(func() {
+			runner = grpc_server.NewGRPCServer(listenAddress, nil, &server{}, helloworld.RegisterGreeterServer,
+				grpc_server.WithServerOptions(grpc.MaxRecvMsgSize(1)),
+			)
+		})
+		JustBeforeEach(func() {
+			serverProcess = ginkgomon.Invoke(runner)
+		})
+
+		AfterEach(func() {
+			ginkgomon.Kill(serverProcess)
+		})
+
+		It("configures the server with them", func() {
+			conn, err := grpc.Dial(listenAddress, grpc.WithInsecure())
+			Expect(err).NotTo(HaveOccurred())
+
+			helloClient := helloworld.NewGreeterClient(conn)
+			_, err = helloClient.SayHello(context.Background(), &helloworld.HelloRequest{Name: "Fred"})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("larger than max"))
+		})
+	})
+
+	Context("when keepalive parameters are provided", func() {
+		BeforeEach(func() {
+			runner = grpc_server.NewGRPCServer(listenAddress, nil, &server{}, helloworld.RegisterGreeterServer,
+				grpc_server.WithKeepaliveParams(keepalive.ServerParameters{MaxConnectionIdle: 50 * time.Millisecond}),
+				grpc_server.WithKeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{MinTime: time.Minute}),
+			)
+		})
+		JustBeforeEach(func() {
+			serverProcess = ginkgomon.Invoke(runner)
+		})
+
+		AfterEach(func() {
+			ginkgomon.Kill(serverProcess)
+		})
+
+		It("closes idle connections", func() {
+			conn, err := grpc.Dial(listenAddress, grpc.WithInsecure())
+			Expect(err).NotTo(HaveOccurred())
+			defer conn.Close()
+
+			helloClient := helloworld.NewGreeterClient(conn)
+			_, err = helloClient.SayHello(context.Background(), &helloworld.HelloRequest{Name: "Fred"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Eventually(conn.GetState).Should(Equal(connectivity.Idle))
+		})
+	})
+
+	Context("when interceptors are provided", func() {
+		var calls chan string
+
+		recordingInterceptor := func(name string) grpc.UnaryServerInterceptor {
+			return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+				calls <- name
+				return handler(ctx, req)
+			}
+		}
+
+		BeforeEach(func() {
+			calls = make(chan string, 3)
+			runner = grpc_server.NewGRPCServer(listenAddress, nil, &server{}, helloworld.RegisterGreeterServer,
+				grpc_server.WithUnaryInterceptors(recordingInterceptor("first"), recordingInterceptor("second")),
+				grpc_server.WithUnaryInterceptors(recordingInterceptor("third")),
+			)
+		})
+		JustBeforeEach(func() {
+			serverProcess = ginkgomon.Invoke(runner)
+		})
+
+		AfterEach(func() {
+			ginkgomon.Kill(serverProcess)
+		})
+
+		It("invokes them in order", func() {
+			conn, err := grpc.Dial(listenAddress, grpc.WithInsecure())
+			Expect(err).NotTo(HaveOccurred())
+
+			helloClient := helloworld.NewGreeterClient(conn)
+			_, err = helloClient.SayHello(context.Background(), &helloworld.HelloRequest{Name: "Fred"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(<-calls).To(Equal("first"))
+			Expect(<-calls).To(Equal("second"))
+			Expect(<-calls).To(Equal("third"))
+		})
+	})
+
+	Context("when a stats handler is provided", func() {
+		var handler *statsHandler
+
+		BeforeEach(func() {
+			handler = &statsHandler{rpcs: make(chan string, 1)}
+			runner = grpc_server.NewGRPCServer(listenAddress, nil, &server{}, helloworld.RegisterGreeterServer,
+				grpc_server.WithStatsHandler(handler),
+			)
+		})
+		JustBeforeEach(func() {
+			serverProcess = ginkgomon.Invoke(runner)
+		})
+
+		AfterEach(func() {
+			ginkgomon.Kill(serverProcess)
+		})
+
+		It("notifies it of RPCs", func() {
+			conn, err := grpc.Dial(listenAddress, grpc.WithInsecure())
+			Expect(err).NotTo(HaveOccurred())
+
+			helloClient := helloworld.NewGreeterClient(conn)
+			_, err = helloClient.SayHello(context.Background(), &helloworld.HelloRequest{Name: "Fred"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(handler.rpcs).To(Receive(Equal("/helloworld.Greeter/SayHello")))
+		})
+	})
+
+	Context("when additional registrations are provided", func() {
+		BeforeEach(func() {
+			runner = grpc_server.NewGRPCServer(listenAddress, nil, &server{}, helloworld.RegisterGreeterServer,
+				grpc_server.WithRegistration(func(s *grpc.Server) {
+					grpc_health_v1.RegisterHealthServer(s, health.NewServer())
+				}),
+			)
+		})
+		JustBeforeEach(func() {
+			serverProcess = ginkgomon.Invoke(runner)
+		})
+
+		AfterEach(func() {
+			ginkgomon.Kill(serverProcess)
+		})
+
+		It("serves every registered service", func() {
+			conn, err := grpc.Dial(listenAddress, grpc.WithInsecure())
+			Expect(err).NotTo(HaveOccurred())
+
+			helloClient := helloworld.NewGreeterClient(conn)
+			_, err = helloClient.SayHello(context.Background(), &helloworld.HelloRequest{Name: "Fred"})
+			Expect(err).NotTo(HaveOccurred())
+
+			healthClient := grpc_health_v1.NewHealthClient(conn)
+			resp, err := healthClient.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.Status).To(Equal(grpc_health_v1.HealthCheckResponse_SERVING))
+		})
+	})
+
+	Context("when health checking is enabled", func() {
+		BeforeEach(func() {
+			runner = grpc_server.NewGRPCServer(listenAddress, nil, &server{}, helloworld.RegisterGreeterServer,
+				grpc_server.WithHealthCheck(),
+			)
+		})
+		JustBeforeEach(func() {
+			serverProcess = ginkgomon.Invoke(runner)
+		})
+
+		AfterEach(func() {
+			ginkgomon.Kill(serverProcess)
+		})
+
+		It("reports SERVING for the server and its services once ready", func() {
+			conn, err := grpc.Dial(listenAddress, grpc.WithInsecure())
+			Expect(err).NotTo(HaveOccurred())
+
+			healthClient := grpc_health_v1.NewHealthClient(conn)
+			for _, service := range []string{"", "helloworld.Greeter"} {
+				resp, err := healthClient.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{Service: service})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resp.Status).To(Equal(grpc_health_v1.HealthCheckResponse_SERVING))
+			}
+		})
+
+		It("reports NOT_SERVING once shutdown begins", func() {
+			conn, err := grpc.Dial(listenAddress, grpc.WithInsecure())
+			Expect(err).NotTo(HaveOccurred())
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			stream, err := grpc_health_v1.NewHealthClient(conn).Watch(ctx, &grpc_health_v1.HealthCheckRequest{})
+			Expect(err).NotTo(HaveOccurred())
+
+			resp, err := stream.Recv()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.Status).To(Equal(grpc_health_v1.HealthCheckResponse_SERVING))
+
+			serverProcess.Signal(os.Interrupt)
+
+			resp, err = stream.Recv()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.Status).To(Equal(grpc_health_v1.HealthCheckResponse_NOT_SERVING))
+		})
+	})
+
+	Context("when reflection is enabled", func() {
+		BeforeEach(func() {
+			runner = grpc_server.NewGRPCServer(listenAddress, nil, &server{}, helloworld.RegisterGreeterServer,
+				grpc_server.WithReflection(),
+			)
+		})
+		JustBeforeEach(func() {
+			serverProcess = ginkgomon.Invoke(runner)
+		})
+
+		AfterEach(func() {
+			ginkgomon.Kill(serverProcess)
+		})
+
+		It("lists the registered services", func() {
+			conn, err := grpc.Dial(listenAddress, grpc.WithInsecure())
+			Expect(err).NotTo(HaveOccurred())
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			stream, err := grpc_reflection_v1.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			err = stream.Send(&grpc_reflection_v1.ServerReflectionRequest{
+				MessageRequest: &grpc_reflection_v1.ServerReflectionRequest_ListServices{},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			resp, err := stream.Recv()
+			Expect(err).NotTo(HaveOccurred())
+
+			names := []string{}
+			for _, service := range resp.GetListServicesResponse().GetService() {
+				names = append(names, service.Name)
+			}
+			Expect(names).To(ContainElement("helloworld.Greeter"))
+		})
+	})
+
+	Context("when channelz is enabled", func() {
+		BeforeEach(func() {
+			runner = grpc_server.NewGRPCServer(listenAddress, nil, &server{}, helloworld.RegisterGreeterServer,
+				grpc_server.WithChannelz(),
+			)
+		})
+		JustBeforeEach(func() {
+			serverProcess = ginkgomon.Invoke(runner)
+		})
+
+		AfterEach(func() {
+			ginkgomon.Kill(serverProcess)
+		})
+
+		It("reports on the running server", func() {
+			conn, err := grpc.Dial(listenAddress, grpc.WithInsecure())
+			Expect(err).NotTo(HaveOccurred())
+
+			resp, err := grpc_channelz_v1.NewChannelzClient(conn).GetServers(context.Background(), &grpc_channelz_v1.GetServersRequest{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.Server).NotTo(BeEmpty())
+		})
+	})
+
+	Context("when a drain timeout is set", func() {
+		BeforeEach(func() {
+			runner = grpc_server.NewGRPCServer(listenAddress, nil, &server{}, helloworld.RegisterGreeterServer,
+				grpc_server.WithHealthCheck(),
+				grpc_server.WithDrainTimeout(100*time.Millisecond),
+			)
+		})
+		JustBeforeEach(func() {
+			serverProcess = ginkgomon.Invoke(runner)
+		})
+
+		AfterEach(func() {
+			ginkgomon.Kill(serverProcess)
+		})
+
+		It("exits cleanly when there is nothing to drain", func() {
+			serverProcess.Signal(os.Interrupt)
+			Eventually(serverProcess.Wait()).Should(Receive(BeNil()))
+		})
+
+		It("stops forcefully once a stream outlives the timeout", func() {
+			conn, err := grpc.Dial(listenAddress, grpc.WithInsecure())
+			Expect(err).NotTo(HaveOccurred())
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			stream, err := grpc_health_v1.NewHealthClient(conn).Watch(ctx, &grpc_health_v1.HealthCheckRequest{})
+			Expect(err).NotTo(HaveOccurred())
+			_, err = stream.Recv()
+			Expect(err).NotTo(HaveOccurred())
+
+			serverProcess.Signal(os.Interrupt)
+			Consistently(serverProcess.Wait(), 50*time.Millisecond).ShouldNot(Receive())
+			Eventually(serverProcess.Wait()).Should(Receive(Equal(grpc_server.ErrForcedStop)))
+		})
+	})
+
+	Context("when a drain policy is set", func() {
+		var (
+			policy    grpc_server.DrainPolicy
+			cancelled chan string
+		)
+
+		watch := func() grpc_health_v1.Health_WatchClient {
+			conn, err := grpc.Dial(listenAddress, grpc.WithInsecure())
+			Expect(err).NotTo(HaveOccurred())
+
+			stream, err := grpc_health_v1.NewHealthClient(conn).Watch(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+			Expect(err).NotTo(HaveOccurred())
+			_, err = stream.Recv()
+			Expect(err).NotTo(HaveOccurred())
+			return stream
+		}
+
+		BeforeEach(func() {
+			cancelled = make(chan string, 10)
+			policy = grpc_server.DrainPolicy{
+				GracePeriod:   50 * time.Millisecond,
+				CancelStreams: true,
+				OnCancel: func(info *grpc.StreamServerInfo) {
+					cancelled <- info.FullMethod
+				},
+				CancelTimeout: time.Second,
+			}
+		})
+
+		JustBeforeEach(func() {
+			runner = grpc_server.NewGRPCServer(listenAddress, nil, &server{}, helloworld.RegisterGreeterServer,
+				grpc_server.WithHealthCheck(),
+				grpc_server.WithDrainPolicy(policy),
+			)
+			serverProcess = ginkgomon.Invoke(runner)
+		})
+
+		AfterEach(func() {
+			ginkgomon.Kill(serverProcess)
+		})
+
+		It("exits cleanly when there is nothing to drain", func() {
+			serverProcess.Signal(os.Interrupt)
+			Eventually(serverProcess.Wait()).Should(Receive(BeNil()))
+			Expect(cancelled).NotTo(Receive())
+		})
+
+		It("cancels streams which outlive the grace period", func() {
+			watch()
+
+			serverProcess.Signal(os.Interrupt)
+			Consistently(serverProcess.Wait(), 25*time.Millisecond).ShouldNot(Receive())
+
+			var err error
+			Eventually(serverProcess.Wait()).Should(Receive(&err))
+			Expect(err).To(Equal(grpc_server.DrainError{Outcome: grpc_server.DrainCancelled, CancelledStreams: 1}))
+			Expect(errors.Is(err, grpc_server.ErrForcedStop)).To(BeFalse())
+			Expect(cancelled).To(Receive(Equal("/grpc.health.v1.Health/Watch")))
+		})
+
+		Context("when streams are not cancelled", func() {
+			BeforeEach(func() {
+				policy.CancelStreams = false
+			})
+
+			It("stops forcefully once the grace period elapses", func() {
+				watch()
+
+				serverProcess.Signal(os.Interrupt)
+
+				var err error
+				Eventually(serverProcess.Wait()).Should(Receive(&err))
+				Expect(err).To(Equal(grpc_server.DrainError{Outcome: grpc_server.DrainForced}))
+				Expect(errors.Is(err, grpc_server.ErrForcedStop)).To(BeTrue())
+				Expect(cancelled).NotTo(Receive())
+			})
+		})
+	})
+
+	Context("when a drain policy cancels streams on secure and insecure servers", func() {
+		var (
+			insecureAddress string
+			cancelled       chan string
+		)
+
+		watch := func(address string, creds grpc.DialOption) {
+			conn, err := grpc.Dial(address, creds)
+			Expect(err).NotTo(HaveOccurred())
+
+			stream, err := grpc_health_v1.NewHealthClient(conn).Watch(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+			Expect(err).NotTo(HaveOccurred())
+			_, err = stream.Recv()
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		BeforeEach(func() {
+			insecureAddress = fmt.Sprintf("127.0.0.1:%d", 10300+GinkgoParallelNode())
+			cancelled = make(chan string, 10)
+			runner = grpc_server.NewGRPCServer(listenAddress, tlsConfig, &server{}, helloworld.RegisterGreeterServer,
+				grpc_server.WithInsecureAddress(insecureAddress),
+				grpc_server.WithHealthCheck(),
+				grpc_server.WithDrainPolicy(grpc_server.DrainPolicy{
+					GracePeriod:   50 * time.Millisecond,
+					CancelStreams: true,
+					OnCancel: func(info *grpc.StreamServerInfo) {
+						cancelled <- info.FullMethod
+					},
+					CancelTimeout: time.Second,
+				}),
+			)
+			serverProcess = ginkgomon.Invoke(runner)
+		})
+
+		AfterEach(func() {
+			ginkgomon.Kill(serverProcess)
+		})
+
+		It("cancels each server's streams as that server drains", func() {
+			watch(listenAddress, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
+			watch(insecureAddress, grpc.WithInsecure())
+
+			serverProcess.Signal(os.Interrupt)
+
+			var err error
+			Eventually(serverProcess.Wait()).Should(Receive(&err))
+			Expect(err).To(Equal(grpc_server.DrainError{Outcome: grpc_server.DrainCancelled, CancelledStreams: 1}))
+			Expect(cancelled).To(HaveLen(2))
+		})
+	})
+
+	Context("when serving certificates from files", func() {
+		var (
+			tmpdir   string
+			basePath string
+		)
+
+		copyPair := func(name string) {
+			for _, ext := range []string{".crt", ".key"} {
+				contents, err := ioutil.ReadFile(path.Join(basePath, name+ext))
+				Expect(err).NotTo(HaveOccurred())
+				err = ioutil.WriteFile(path.Join(tmpdir, "cert"+ext), contents, 0600)
+				Expect(err).NotTo(HaveOccurred())
+			}
+		}
+
+		servedCommonName := func() string {
+			conn, err := tls.Dial("tcp", listenAddress, &tls.Config{InsecureSkipVerify: true, NextProtos: []string{"h2"}})
+			Expect(err).NotTo(HaveOccurred())
+			defer conn.Close()
+			return conn.ConnectionState().PeerCertificates[0].Subject.CommonName
+		}
+
+		BeforeEach(func() {
+			var err error
+			basePath = path.Join(os.Getenv("GOPATH"), "src", "github.com", "tedsuo", "ifrit", "http_server", "test_certs")
+			tmpdir, err = ioutil.TempDir("", "grpc-server-test")
+			Expect(err).NotTo(HaveOccurred())
+			copyPair("server")
+
+			runner = grpc_server.NewGRPCServer(listenAddress, nil, &server{}, helloworld.RegisterGreeterServer,
+				grpc_server.WithCertificateFiles(path.Join(tmpdir, "cert.crt"), path.Join(tmpdir, "cert.key"), 10*time.Millisecond),
+			)
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(tmpdir)
+		})
+
+		Context("when the files are valid", func() {
+			JustBeforeEach(func() {
+				serverProcess = ginkgomon.Invoke(runner)
+			})
+
+			AfterEach(func() {
+				ginkgomon.Kill(serverProcess)
+			})
+
+			It("serves the certificate", func() {
+				Expect(servedCommonName()).To(Equal("bbs.service.cf.internal"))
+			})
+
+			It("serves the new certificate once the files change", func() {
+				time.Sleep(20 * time.Millisecond)
+				copyPair("client")
+				Eventually(servedCommonName).Should(Equal("bbs client"))
+			})
+		})
+
+		Context("when files are reloaded on a signal", func() {
+			BeforeEach(func() {
+				runner = grpc_server.NewGRPCServer(listenAddress, nil, &server{}, helloworld.RegisterGreeterServer,
+					grpc_server.WithCertificateFiles(path.Join(tmpdir, "cert.crt"), path.Join(tmpdir, "cert.key"), 0),
+					grpc_server.WithReloadSignal(syscall.SIGHUP),
+				)
+			})
+			JustBeforeEach(func() {
+				serverProcess = ginkgomon.Invoke(runner)
+			})
+
+			AfterEach(func() {
+				ginkgomon.Kill(serverProcess)
+			})
+
+			It("serves the new certificate once signaled, without exiting", func() {
+				copyPair("client")
+				Consistently(servedCommonName, 50*time.Millisecond).Should(Equal("bbs.service.cf.internal"))
+
+				serverProcess.Signal(syscall.SIGHUP)
+				Eventually(servedCommonName).Should(Equal("bbs client"))
+				Consistently(serverProcess.Wait()).ShouldNot(Receive())
+			})
+		})
+
+		Context("when the files cannot be loaded", func() {
+			BeforeEach(func() {
+				os.Remove(path.Join(tmpdir, "cert.key"))
+			})
+
+			It("exits with an error", func() {
+				var err error
+				process := ifrit.Background(runner)
+				Eventually(process.Wait()).Should(Receive(&err))
+				Expect(err).To(HaveOccurred())
+			})
+		})
+	})
+
+	Context("when client certificates are required", func() {
+		var (
+			ca           *testCA
+			serverConfig *tls.Config
+			clientCAs    *x509.CertPool
+			extraOptions []grpc_server.Option
+			sayHelloAs   func(clientCert *tls.Certificate) error
+		)
+
+		BeforeEach(func() {
+			ca = newTestCA()
+			clientCAs = ca.Pool
+			extraOptions = nil
+			serverConfig = &tls.Config{Certificates: []tls.Certificate{ca.Issue("server")}}
+
+			sayHelloAs = func(clientCert *tls.Certificate) error {
+				clientConfig := &tls.Config{RootCAs: ca.Pool}
+				if clientCert != nil {
+					clientConfig.Certificates = []tls.Certificate{*clientCert}
+				}
+				conn, err := grpc.Dial(listenAddress, grpc.WithTransportCredentials(credentials.NewTLS(clientConfig)))
+				Expect(err).NotTo(HaveOccurred())
+				defer conn.Close()
+
+				_, err = helloworld.NewGreeterClient(conn).SayHello(context.Background(), &helloworld.HelloRequest{Name: "Fred"})
+				return err
+			}
+		})
+
+		JustBeforeEach(func() {
+			opts := append([]grpc_server.Option{grpc_server.WithClientCAs(clientCAs)}, extraOptions...)
+			runner = grpc_server.NewGRPCServer(listenAddress, serverConfig, &server{}, helloworld.RegisterGreeterServer, opts...)
+			serverProcess = ginkgomon.Invoke(runner)
+		})
+
+		AfterEach(func() {
+			ginkgomon.Kill(serverProcess)
+		})
+
+		It("accepts clients with a certificate signed by a client CA", func() {
+			clientCert := ca.Issue("client")
+			Expect(sayHelloAs(&clientCert)).To(Succeed())
+		})
+
+		It("rejects clients without a certificate", func() {
+			Expect(sayHelloAs(nil)).NotTo(Succeed())
+		})
+
+		It("rejects clients with a certificate signed by another CA", func() {
+			clientCert := newTestCA().Issue("client")
+			Expect(sayHelloAs(&clientCert)).NotTo(Succeed())
+		})
+
+		Context("with a client certificate verifier", func() {
+			BeforeEach(func() {
+				extraOptions = append(extraOptions, grpc_server.WithClientCertificateVerifier(func(cert *x509.Certificate) error {
+					if cert.Subject.CommonName != "trusted" {
+						return errors.New("untrusted client")
+					}
+					return nil
+				}))
+			})
+
+			It("accepts the clients it verifies", func() {
+				clientCert := ca.Issue("trusted")
+				Expect(sayHelloAs(&clientCert)).To(Succeed())
+			})
+
+			It("rejects the clients it does not verify", func() {
+				clientCert := ca.Issue("untrusted")
+				Expect(sayHelloAs(&clientCert)).NotTo(Succeed())
+			})
+		})
+
+		Context("with a SPIFFE ID allow-list", func() {
+			BeforeEach(func() {
+				extraOptions = append(extraOptions, grpc_server.WithAllowedSPIFFEIDs("spiffe://example.org/allowed"))
+			})
+
+			It("accepts clients with an allowed ID", func() {
+				clientCert := ca.Issue("client", "spiffe://example.org/allowed")
+				Expect(sayHelloAs(&clientCert)).To(Succeed())
+			})
+
+			It("rejects clients with another ID", func() {
+				clientCert := ca.Issue("client", "spiffe://example.org/denied")
+				Expect(sayHelloAs(&clientCert)).NotTo(Succeed())
+			})
+
+			It("rejects clients without an ID", func() {
+				clientCert := ca.Issue("client")
+				Expect(sayHelloAs(&clientCert)).NotTo(Succeed())
+			})
+		})
+	})
+
+	Context("when a client certificate verifier is given without client CAs", func() {
+		var ca *testCA
+
+		BeforeEach(func() {
+			ca = newTestCA()
+			serverConfig := &tls.Config{Certificates: []tls.Certificate{ca.Issue("server")}}
+			runner = grpc_server.NewGRPCServer(listenAddress, serverConfig, &server{}, helloworld.RegisterGreeterServer,
+				grpc_server.WithClientCertificateVerifier(func(cert *x509.Certificate) error {
+					return nil
+				}),
+			)
+		})
+
+		It("exits with a validation error rather than accept unverified certificates", func() {
+			var err error
+			process := ifrit.Background(runner)
+			Eventually(process.Wait()).Should(Receive(&err))
+			Expect(err).To(BeAssignableToTypeOf(grpc_server.ValidationError{}))
+
+			selfSigned := newTestCA().Issue("client")
+			clientConfig := &tls.Config{RootCAs: ca.Pool, Certificates: []tls.Certificate{selfSigned}}
+			conn, err := grpc.Dial(listenAddress, grpc.WithTransportCredentials(credentials.NewTLS(clientConfig)))
+			Expect(err).NotTo(HaveOccurred())
+			defer conn.Close()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+			defer cancel()
+			_, err = helloworld.NewGreeterClient(conn).SayHello(ctx, &helloworld.HelloRequest{Name: "Fred"})
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("fails NewGRPCServerE", func() {
+			_, err := grpc_server.NewGRPCServerE(listenAddress, nil, &server{}, helloworld.RegisterGreeterServer,
+				grpc_server.WithAllowedSPIFFEIDs("spiffe://example.org/allowed"),
+			)
+			Expect(err).To(BeAssignableToTypeOf(grpc_server.ValidationError{}))
+		})
+	})
+
 	Context("when the inputs to NewGRPCServer are invalid", func() {
 		var (
 			err error
@@ -205,6 +1161,17 @@ var _ = Describe("GRPCServer", func() {
 			})
 		})
 
+		Context("when an additional service is invalid", func() {
+			BeforeEach(func() {
+				runner = grpc_server.NewGRPCServer(listenAddress, tlsConfig, &server{}, helloworld.RegisterGreeterServer,
+					grpc_server.WithService(&notServer{}, helloworld.RegisterGreeterServer),
+				)
+			})
+			It("fails", func() {
+				Expect(err.Error()).To(ContainSubstring("is not implemented by `handler`"))
+			})
+		})
+
 		Context("when the registrar returns a value", func() {
 			BeforeEach(func() {
 				f := func(a *grpc.Server, b helloworld.GreeterServer) error { return nil }
@@ -215,6 +1182,193 @@ var _ = Describe("GRPCServer", func() {
 			})
 		})
 	})
+
+	Context("when concurrent RPCs are limited", func() {
+		var (
+			greeter  *blockingServer
+			rejected chan string
+		)
+
+		BeforeEach(func() {
+			greeter = &blockingServer{entered: make(chan struct{}, 10), release: make(chan struct{})}
+			rejected = make(chan string, 10)
+			runner = grpc_server.NewGRPCServer(listenAddress, nil, greeter, helloworld.RegisterGreeterServer,
+				grpc_server.WithHealthCheck(),
+				grpc_server.WithMaxConcurrentRPCs(1, func(method string) {
+					rejected <- method
+				}),
+			)
+			serverProcess = ginkgomon.Invoke(runner)
+		})
+
+		AfterEach(func() {
+			ginkgomon.Kill(serverProcess)
+		})
+
+		It("rejects RPCs beyond the limit with RESOURCE_EXHAUSTED", func() {
+			conn, err := grpc.Dial(listenAddress, grpc.WithInsecure())
+			Expect(err).NotTo(HaveOccurred())
+			defer conn.Close()
+			client := helloworld.NewGreeterClient(conn)
+
+			go client.SayHello(context.Background(), &helloworld.HelloRequest{Name: "Fred"})
+			Eventually(greeter.entered).Should(Receive())
+
+			_, err = client.SayHello(context.Background(), &helloworld.HelloRequest{Name: "Barney"})
+			Expect(status.Code(err)).To(Equal(codes.ResourceExhausted))
+			Expect(rejected).To(Receive(Equal("/helloworld.Greeter/SayHello")))
+
+			resp, err := grpc_health_v1.NewHealthClient(conn).Check(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.Status).To(Equal(grpc_health_v1.HealthCheckResponse_SERVING))
+
+			close(greeter.release)
+			Eventually(func() error {
+				_, err := client.SayHello(context.Background(), &helloworld.HelloRequest{Name: "Barney"})
+				return err
+			}).Should(Succeed())
+		})
+	})
+
+	Context("when connections are limited", func() {
+		var rejected chan net.Addr
+
+		BeforeEach(func() {
+			rejected = make(chan net.Addr, 10)
+			runner = grpc_server.NewGRPCServer(listenAddress, nil, &server{}, helloworld.RegisterGreeterServer,
+				grpc_server.WithMaxConnections(1, func(remote net.Addr) {
+					rejected <- remote
+				}),
+			)
+			serverProcess = ginkgomon.Invoke(runner)
+		})
+
+		AfterEach(func() {
+			ginkgomon.Kill(serverProcess)
+		})
+
+		It("closes connections beyond the limit", func() {
+			conn, err := grpc.Dial(listenAddress, grpc.WithInsecure())
+			Expect(err).NotTo(HaveOccurred())
+			_, err = helloworld.NewGreeterClient(conn).SayHello(context.Background(), &helloworld.HelloRequest{Name: "Fred"})
+			Expect(err).NotTo(HaveOccurred())
+
+			extra, err := net.Dial("tcp", listenAddress)
+			Expect(err).NotTo(HaveOccurred())
+			defer extra.Close()
+			_, err = extra.Read(make([]byte, 1))
+			Expect(err).To(HaveOccurred())
+			Eventually(rejected).Should(Receive(Equal(extra.LocalAddr())))
+
+			conn.Close()
+			Eventually(func() error {
+				conn, err := grpc.Dial(listenAddress, grpc.WithInsecure(), grpc.WithBlock(), grpc.WithTimeout(100*time.Millisecond))
+				if err != nil {
+					return err
+				}
+				return conn.Close()
+			}).Should(Succeed())
+		})
+	})
+
+	Context("when a server factory is used", func() {
+		var (
+			factory    grpc_server.ServerFactory
+			setServing chan func(net.Addr, bool)
+			addr       net.Addr
+		)
+
+		checkHealth := func() grpc_health_v1.HealthCheckResponse_ServingStatus {
+			conn, err := grpc.Dial(listenAddress, grpc.WithInsecure())
+			Expect(err).NotTo(HaveOccurred())
+			defer conn.Close()
+
+			resp, err := grpc_health_v1.NewHealthClient(conn).Check(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+			Expect(err).NotTo(HaveOccurred())
+			return resp.Status
+		}
+
+		BeforeEach(func() {
+			setServing = make(chan func(net.Addr, bool), 1)
+			factory = grpc_server.ServerFactory{
+				New: func(opts []grpc.ServerOption, set func(net.Addr, bool)) (grpc_server.Server, error) {
+					setServing <- set
+					return grpc.NewServer(opts...), nil
+				},
+				ReportsServing: true,
+			}
+		})
+
+		JustBeforeEach(func() {
+			runner = grpc_server.NewGRPCServer(listenAddress, nil, grpc_channelz_v1.UnimplementedChannelzServer{}, grpc_channelz_v1.RegisterChannelzServer,
+				grpc_server.WithServerFactory(factory),
+				grpc_server.WithHealthCheck(),
+				grpc_server.WithAddressCallback(func(a net.Addr) {
+					addr = a
+				}),
+			)
+			serverProcess = ifrit.Background(runner)
+		})
+
+		AfterEach(func() {
+			ginkgomon.Kill(serverProcess)
+		})
+
+		It("becomes ready once every listener is reported serving", func() {
+			var set func(net.Addr, bool)
+			Eventually(setServing).Should(Receive(&set))
+			Consistently(serverProcess.Ready()).ShouldNot(BeClosed())
+			Expect(checkHealth()).To(Equal(grpc_health_v1.HealthCheckResponse_NOT_SERVING))
+
+			set(addr, true)
+			Eventually(serverProcess.Ready()).Should(BeClosed())
+			Eventually(checkHealth).Should(Equal(grpc_health_v1.HealthCheckResponse_SERVING))
+
+			set(addr, false)
+			Eventually(checkHealth).Should(Equal(grpc_health_v1.HealthCheckResponse_NOT_SERVING))
+		})
+
+		Context("when the factory fails", func() {
+			BeforeEach(func() {
+				factory.New = func([]grpc.ServerOption, func(net.Addr, bool)) (grpc_server.Server, error) {
+					return nil, errors.New("no server")
+				}
+			})
+
+			It("exits with the error", func() {
+				Eventually(serverProcess.Wait()).Should(Receive(MatchError("no server")))
+			})
+		})
+
+		It("rejects registrars which require a *grpc.Server", func() {
+			_, err := grpc_server.NewGRPCServerE(listenAddress, nil, &server{}, helloworld.RegisterGreeterServer,
+				grpc_server.WithServerFactory(factory),
+			)
+			Expect(err).To(MatchError(ContainSubstring("must be `grpc.ServiceRegistrar` when using a ServerFactory")))
+		})
+	})
+
+	Describe("NewGRPCServerE", func() {
+		It("returns a runner when the inputs are valid", func() {
+			runner, err := grpc_server.NewGRPCServerE(listenAddress, nil, &server{}, helloworld.RegisterGreeterServer)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(runner).NotTo(BeNil())
+		})
+
+		It("returns a ValidationError when the inputs are invalid", func() {
+			runner, err := grpc_server.NewGRPCServerE(listenAddress, nil, &server{}, 42)
+			Expect(runner).To(BeNil())
+			Expect(err).To(BeAssignableToTypeOf(grpc_server.ValidationError{}))
+			Expect(err.Error()).To(ContainSubstring("should be func but is int"))
+		})
+
+		It("validates additional services", func() {
+			_, err := grpc_server.NewGRPCServerE(listenAddress, nil, &server{}, helloworld.RegisterGreeterServer,
+				grpc_server.WithService(nil, helloworld.RegisterGreeterServer),
+			)
+			Expect(err).To(BeAssignableToTypeOf(grpc_server.ValidationError{}))
+		})
+	})
 })
 
 // server is used to implement helloworld.GreeterServer.
@@ -225,6 +1379,36 @@ func (s *server) SayHello(ctx context.Context, in *helloworld.HelloRequest) (*he
 	return &helloworld.HelloReply{Message: "Hello " + in.Name}, nil
 }
 
+// blockingServer holds every SayHello until release is closed.
+type blockingServer struct {
+	entered chan struct{}
+	release chan struct{}
+}
+
+func (s *blockingServer) SayHello(ctx context.Context, in *helloworld.HelloRequest) (*helloworld.HelloReply, error) {
+	s.entered <- struct{}{}
+	<-s.release
+	return &helloworld.HelloReply{Message: "Hello " + in.Name}, nil
+}
+
+// statsHandler records the method name of every RPC it is notified of.
+type statsHandler struct {
+	rpcs chan string
+}
+
+func (h *statsHandler) TagRPC(ctx context.Context, info *stats.RPCTagInfo) context.Context {
+	h.rpcs <- info.FullMethodName
+	return ctx
+}
+
+func (h *statsHandler) HandleRPC(context.Context, stats.RPCStats) {}
+
+func (h *statsHandler) TagConn(ctx context.Context, _ *stats.ConnTagInfo) context.Context {
+	return ctx
+}
+
+func (h *statsHandler) HandleConn(context.Context, stats.ConnStats) {}
+
 // notServer doesn't implement anything
 type notServer struct{}
 
@@ -0,0 +1,91 @@
+/*
+The xds_server package runs a GRPC server as an ifrit.Runner using the xDS-enabled
+server from google.golang.org/grpc/xds, so that services in a service mesh such as
+Istio or Traffic Director are configured by the mesh's control plane.
+
+The xDS bootstrap configuration is read by the GRPC library from the file named by
+the GRPC_XDS_BOOTSTRAP environment variable, or from the contents of the
+GRPC_XDS_BOOTSTRAP_CONFIG environment variable.  It must include a
+server_listener_resource_name_template.
+*/
+package xds_server
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+
+	"github.com/tedsuo/ifrit"
+	"github.com/tedsuo/ifrit/grpc_server"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	xdscreds "google.golang.org/grpc/credentials/xds"
+	"google.golang.org/grpc/xds"
+)
+
+// Config describes the xDS specific behaviour of the runner.
+type Config struct {
+	// XDSCredentials uses the security configuration provided by the control plane.  tlsConfig, or plaintext if it
+	// is nil, is used when the control plane provides none.
+	XDSCredentials bool
+
+	// OnServingModeChange is optional, and is called whenever a listener's serving mode changes.  It must not
+	// block.
+	OnServingModeChange xds.ServingModeCallbackFunc
+}
+
+// New returns an ifrit.Runner which serves a GRPC server, as described by grpc_server.NewGRPCServer, using an
+// xDS-enabled server.
+//
+// serverRegistrar, and the registrar of any additional service, must accept a grpc.ServiceRegistrar.
+//
+// A listener only serves RPCs once the control plane has provided its Listener resource.  The runner becomes ready,
+// and reports SERVING if health checks are enabled, once every listener is serving.  If the control plane later
+// moves a listener back to not serving, health checks report NOT_SERVING until it recovers.
+func New(listenAddress string, tlsConfig *tls.Config, handler, serverRegistrar interface{}, config Config, opts ...grpc_server.Option) ifrit.Runner {
+	var creds credentials.TransportCredentials
+	if config.XDSCredentials {
+		creds = insecure.NewCredentials()
+		if tlsConfig != nil {
+			creds = credentials.NewTLS(tlsConfig)
+		}
+		tlsConfig = nil
+	}
+
+	factory := grpc_server.ServerFactory{
+		New: func(serverOpts []grpc.ServerOption, setServing func(net.Addr, bool)) (grpc_server.Server, error) {
+			return newServer(serverOpts, creds, config.OnServingModeChange, setServing)
+		},
+		ReportsServing: true,
+	}
+
+	opts = append(opts, grpc_server.WithServerFactory(factory))
+	return grpc_server.NewGRPCServer(listenAddress, tlsConfig, handler, serverRegistrar, opts...)
+}
+
+// newServer creates an xDS-enabled server.  If fallback is non-nil, the server uses xDS credentials which fall back
+// to it.
+func newServer(opts []grpc.ServerOption, fallback credentials.TransportCredentials, onChange xds.ServingModeCallbackFunc, setServing func(net.Addr, bool)) (grpc_server.Server, error) {
+	if fallback != nil {
+		creds, err := xdscreds.NewServerCredentials(xdscreds.ServerOptions{FallbackCreds: fallback})
+		if err != nil {
+			return nil, fmt.Errorf("xds_server: creating xDS credentials: %w", err)
+		}
+		opts = append(opts, grpc.Creds(creds))
+	}
+
+	opts = append(opts, xds.ServingModeCallback(func(addr net.Addr, args xds.ServingModeChangeArgs) {
+		setServing(addr, args.Mode == connectivity.ServingModeServing)
+		if onChange != nil {
+			onChange(addr, args)
+		}
+	}))
+
+	server, err := xds.NewGRPCServer(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("xds_server: %w", err)
+	}
+	return server, nil
+}
@@ -0,0 +1,13 @@
+package xds_server_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestXDSServer(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "XDS Server Suite")
+}
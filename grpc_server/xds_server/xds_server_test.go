@@ -0,0 +1,99 @@
+package xds_server_test
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/tedsuo/ifrit"
+	"github.com/tedsuo/ifrit/grpc_server"
+	"github.com/tedsuo/ifrit/grpc_server/xds_server"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/xds"
+)
+
+var _ = Describe("XDSServer", func() {
+	var (
+		listenAddress string
+		config        xds_server.Config
+		opts          []grpc_server.Option
+		process       ifrit.Process
+	)
+
+	BeforeEach(func() {
+		listenAddress = fmt.Sprintf("127.0.0.1:%d", 10700+GinkgoParallelNode())
+		config = xds_server.Config{}
+		opts = nil
+	})
+
+	JustBeforeEach(func() {
+		runner := xds_server.New(listenAddress, nil, health.NewServer(), grpc_health_v1.RegisterHealthServer, config, opts...)
+		process = ifrit.Background(runner)
+	})
+
+	AfterEach(func() {
+		process.Signal(os.Kill)
+		Eventually(process.Wait()).Should(Receive())
+	})
+
+	Context("without a bootstrap configuration", func() {
+		BeforeEach(func() {
+			os.Unsetenv("GRPC_XDS_BOOTSTRAP")
+			os.Unsetenv("GRPC_XDS_BOOTSTRAP_CONFIG")
+		})
+
+		It("exits with an error", func() {
+			var err error
+			Eventually(process.Wait()).Should(Receive(&err))
+			Expect(err).To(MatchError(HavePrefix("xds_server: ")))
+		})
+
+		It("does not listen", func() {
+			Eventually(process.Wait()).Should(Receive())
+			lis, err := net.Listen("tcp", listenAddress)
+			Expect(err).NotTo(HaveOccurred())
+			lis.Close()
+		})
+	})
+
+	Context("when the control plane has not provided a listener", func() {
+		BeforeEach(func() {
+			bootstrap := `{
+				"xds_servers": [{"server_uri": "127.0.0.1:1", "channel_creds": [{"type": "insecure"}], "server_features": ["xds_v3"]}],
+				"node": {"id": "ifrit-test"},
+				"server_listener_resource_name_template": "grpc/server?xds.resource.listening_address=%s"
+			}`
+			opts = []grpc_server.Option{
+				grpc_server.WithServerOptions(xds.BootstrapContentsForTesting([]byte(bootstrap))),
+			}
+		})
+
+		It("does not become ready", func() {
+			Consistently(process.Ready(), 200*time.Millisecond).ShouldNot(BeClosed())
+		})
+
+		It("exits cleanly when signaled", func() {
+			process.Signal(os.Interrupt)
+			Eventually(process.Wait()).Should(Receive(BeNil()))
+		})
+	})
+
+	Context("when a registrar requires a *grpc.Server", func() {
+		BeforeEach(func() {
+			opts = []grpc_server.Option{
+				grpc_server.WithService(health.NewServer(), func(*grpc.Server, grpc_health_v1.HealthServer) {}),
+			}
+		})
+
+		It("exits with a ValidationError", func() {
+			var err error
+			Eventually(process.Wait()).Should(Receive(&err))
+			Expect(err).To(BeAssignableToTypeOf(grpc_server.ValidationError{}))
+		})
+	})
+})
@@ -0,0 +1,84 @@
+package ifrit
+
+import "os"
+
+/*
+Hooks are callbacks invoked by a Runner returned by WithHooks as the Runner it
+wraps moves through its lifecycle.  Every hook is optional, and is called from
+the goroutine running the Runner, so a slow hook delays the Runner.
+*/
+type Hooks struct {
+	// BeforeRun is called before the Runner starts running.
+	BeforeRun func()
+
+	// OnReady is called once the Runner is ready, before its readiness is
+	// reported.
+	OnReady func()
+
+	// OnSignal is called with each signal the Runner is sent, before the Runner
+	// receives it.
+	OnSignal func(os.Signal)
+
+	// OnExit is called with the error the Runner exited with.
+	OnExit func(error)
+}
+
+/*
+WithHooks returns a Runner which runs r, invoking hooks at each stage of its
+lifecycle.
+*/
+func WithHooks(r Runner, hooks Hooks) Runner {
+	return RunFunc(func(signals <-chan os.Signal, ready chan<- struct{}) error {
+		if hooks.BeforeRun != nil {
+			hooks.BeforeRun()
+		}
+
+		innerSignals := make(chan os.Signal)
+		innerReady := make(chan struct{})
+		errs := make(chan error, 1)
+		go func() {
+			errs <- r.Run(innerSignals, innerReady)
+		}()
+
+		becameReady := func() {
+			innerReady = nil
+			if hooks.OnReady != nil {
+				hooks.OnReady()
+			}
+			close(ready)
+		}
+
+		exit := func(err error) error {
+			select {
+			case <-innerReady:
+				becameReady()
+			default:
+			}
+
+			if hooks.OnExit != nil {
+				hooks.OnExit(err)
+			}
+			return err
+		}
+
+		for {
+			select {
+			case <-innerReady:
+				becameReady()
+
+			case signal := <-signals:
+				if hooks.OnSignal != nil {
+					hooks.OnSignal(signal)
+				}
+				select {
+				case innerSignals <- signal:
+				case err := <-errs:
+					return exit(err)
+				}
+
+			case err := <-errs:
+				return exit(err)
+			}
+		}
+	})
+}
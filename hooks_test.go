@@ -0,0 +1,81 @@
+package ifrit_test
+
+import (
+	"errors"
+	"os"
+	"syscall"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/tedsuo/ifrit"
+	"github.com/tedsuo/ifrit/test_helpers"
+)
+
+var _ = Describe("WithHooks", func() {
+	var (
+		events chan string
+		hooks  ifrit.Hooks
+	)
+
+	BeforeEach(func() {
+		events = make(chan string, 10)
+		hooks = ifrit.Hooks{
+			BeforeRun: func() { events <- "before run" },
+			OnReady:   func() { events <- "ready" },
+			OnSignal:  func(signal os.Signal) { events <- "signal " + signal.String() },
+			OnExit: func(err error) {
+				if err != nil {
+					events <- "exit " + err.Error()
+				} else {
+					events <- "exit"
+				}
+			},
+		}
+	})
+
+	It("invokes the hooks at each stage of the lifecycle", func() {
+		recorder := test_helpers.NewSignalRecorder()
+		process := ifrit.Invoke(ifrit.WithHooks(recorder, hooks))
+		Ω(process.Ready()).Should(BeClosed())
+		Ω(events).Should(Receive(Equal("before run")))
+		Ω(events).Should(Receive(Equal("ready")))
+
+		process.Signal(syscall.SIGHUP)
+		Eventually(events).Should(Receive(Equal("signal hangup")))
+		Eventually(recorder.ReceivedSignals).Should(Equal([]os.Signal{syscall.SIGHUP}))
+
+		process.Signal(os.Interrupt)
+		Eventually(process.Wait()).Should(Receive(BeNil()))
+		Ω(events).Should(Receive(Equal("signal interrupt")))
+		Ω(events).Should(Receive(Equal("exit")))
+	})
+
+	It("reports the error of a runner which exits by itself", func() {
+		process := ifrit.Invoke(ifrit.WithHooks(test_helpers.NoReadyRunner, hooks))
+		Eventually(process.Wait()).Should(Receive(Equal(test_helpers.NoReadyExitedNormally)))
+		Ω(events).Should(Receive(Equal("before run")))
+		Ω(events).Should(Receive(Equal("exit " + test_helpers.NoReadyExitedNormally.Error())))
+		Ω(process.Ready()).ShouldNot(BeClosed())
+	})
+
+	It("reports readiness of a runner which exits as soon as it is ready", func() {
+		exitErr := errors.New("done")
+		runner := ifrit.RunFunc(func(signals <-chan os.Signal, ready chan<- struct{}) error {
+			close(ready)
+			return exitErr
+		})
+
+		process := ifrit.Background(ifrit.WithHooks(runner, hooks))
+		Eventually(process.Wait()).Should(Receive(Equal(exitErr)))
+		Ω(process.Ready()).Should(BeClosed())
+		Ω(events).Should(Receive(Equal("before run")))
+		Ω(events).Should(Receive(Equal("ready")))
+		Ω(events).Should(Receive(Equal("exit done")))
+	})
+
+	It("allows hooks to be omitted", func() {
+		process := ifrit.Invoke(ifrit.WithHooks(test_helpers.NewSignalRecorder(), ifrit.Hooks{}))
+		process.Signal(os.Interrupt)
+		Eventually(process.Wait()).Should(Receive(BeNil()))
+	})
+})
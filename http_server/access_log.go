@@ -0,0 +1,119 @@
+package http_server
+
+import (
+	"bufio"
+	"context"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+)
+
+// An AccessLogEntry describes a request served by the runner's handler.
+type AccessLogEntry struct {
+	Method     string
+	Path       string
+	Proto      string
+	RemoteAddr string
+	Status     int
+	Bytes      int64 // bytes written in the response body
+	Latency    time.Duration
+}
+
+// An AccessLogger records an entry for every request, once its handler returns.  It is called from the request's
+// goroutine.
+type AccessLogger interface {
+	LogAccess(AccessLogEntry)
+}
+
+// AccessLoggerFunc adapts a function to an AccessLogger, for example to log with lager:
+//
+//	http_server.AccessLoggerFunc(func(e http_server.AccessLogEntry) {
+//		logger.Info("request", lager.Data{"method": e.Method, "path": e.Path, "status": e.Status})
+//	})
+type AccessLoggerFunc func(AccessLogEntry)
+
+func (f AccessLoggerFunc) LogAccess(entry AccessLogEntry) {
+	f(entry)
+}
+
+// SlogAccessLogger logs each request to logger at slog.LevelInfo.
+func SlogAccessLogger(logger *slog.Logger) AccessLogger {
+	return AccessLoggerFunc(func(e AccessLogEntry) {
+		logger.LogAttrs(context.Background(), slog.LevelInfo, "request",
+			slog.String("method", e.Method),
+			slog.String("path", e.Path),
+			slog.String("proto", e.Proto),
+			slog.String("remote_addr", e.RemoteAddr),
+			slog.Int("status", e.Status),
+			slog.Int64("bytes", e.Bytes),
+			slog.Duration("latency", e.Latency),
+		)
+	})
+}
+
+// AccessLogHandler records an entry with logger for every request served by handler.
+func AccessLogHandler(logger AccessLogger, handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		recorder := &responseRecorder{ResponseWriter: w}
+		handler.ServeHTTP(recorder, r)
+
+		status := recorder.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		logger.LogAccess(AccessLogEntry{
+			Method:     r.Method,
+			Path:       r.URL.Path,
+			Proto:      r.Proto,
+			RemoteAddr: r.RemoteAddr,
+			Status:     status,
+			Bytes:      recorder.bytes,
+			Latency:    time.Since(start),
+		})
+	})
+}
+
+// responseRecorder records the status and size of a response.  Hijacked connections are recorded as switching
+// protocols.
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int64
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	n, err := r.ResponseWriter.Write(b)
+	r.bytes += int64(n)
+	return n, err
+}
+
+func (r *responseRecorder) Flush() {
+	if flusher, ok := r.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (r *responseRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, rw, err := http.NewResponseController(r.ResponseWriter).Hijack()
+	if err == nil && r.status == 0 {
+		r.status = http.StatusSwitchingProtocols
+	}
+	return conn, rw, err
+}
+
+// Unwrap allows http.ResponseController to reach the underlying ResponseWriter.
+func (r *responseRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
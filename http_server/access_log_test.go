@@ -0,0 +1,83 @@
+package http_server_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/tedsuo/ifrit/http_server"
+)
+
+var _ = Describe("AccessLogHandler", func() {
+	var entries []http_server.AccessLogEntry
+
+	logger := http_server.AccessLoggerFunc(func(e http_server.AccessLogEntry) {
+		entries = append(entries, e)
+	})
+
+	BeforeEach(func() {
+		entries = nil
+	})
+
+	It("records the request and its response", func() {
+		handler := http_server.AccessLogHandler(logger, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(10 * time.Millisecond)
+			w.WriteHeader(http.StatusTeapot)
+			w.Write([]byte("short and stout"))
+		}))
+
+		req := httptest.NewRequest("POST", "http://example.com/teapot?brew=1", nil)
+		req.RemoteAddr = "192.0.2.1:56324"
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+
+		Ω(entries).Should(HaveLen(1))
+		e := entries[0]
+		Ω(e.Method).Should(Equal("POST"))
+		Ω(e.Path).Should(Equal("/teapot"))
+		Ω(e.Proto).Should(Equal("HTTP/1.1"))
+		Ω(e.RemoteAddr).Should(Equal("192.0.2.1:56324"))
+		Ω(e.Status).Should(Equal(http.StatusTeapot))
+		Ω(e.Bytes).Should(Equal(int64(len("short and stout"))))
+		Ω(e.Latency).Should(BeNumerically(">=", 10*time.Millisecond))
+	})
+
+	It("records an implicit OK", func() {
+		handler := http_server.AccessLogHandler(logger, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+		Ω(entries[0].Status).Should(Equal(http.StatusOK))
+	})
+
+	It("preserves the response writer's optional interfaces", func() {
+		handler := http_server.AccessLogHandler(logger, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, ok := w.(http.Flusher)
+			Ω(ok).Should(BeTrue())
+			_, ok = w.(http.Hijacker)
+			Ω(ok).Should(BeTrue())
+		}))
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	})
+
+	Describe("SlogAccessLogger", func() {
+		It("logs each request as an info record", func() {
+			buffer := new(bytes.Buffer)
+			slogger := http_server.SlogAccessLogger(slog.New(slog.NewJSONHandler(buffer, nil)))
+			handler := http_server.AccessLogHandler(slogger, http.NotFoundHandler())
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/missing", nil))
+
+			var record map[string]interface{}
+			Ω(json.NewDecoder(bufio.NewReader(buffer)).Decode(&record)).Should(Succeed())
+			Ω(record).Should(HaveKeyWithValue("level", "INFO"))
+			Ω(record).Should(HaveKeyWithValue("msg", "request"))
+			Ω(record).Should(HaveKeyWithValue("method", "GET"))
+			Ω(record).Should(HaveKeyWithValue("path", "/missing"))
+			Ω(record).Should(HaveKeyWithValue("status", float64(http.StatusNotFound)))
+			Ω(record).Should(HaveKey("latency"))
+		})
+	})
+})
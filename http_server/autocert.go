@@ -0,0 +1,70 @@
+package http_server
+
+import (
+	"net"
+	"net/http"
+
+	"github.com/tedsuo/ifrit"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// DefaultChallengeAddress is where an autocert server answers HTTP-01 challenges when no other address is given.
+// Certificate authorities only send challenges to port 80.
+const DefaultChallengeAddress = ":80"
+
+// AutocertConfig describes how an autocert server obtains its certificates.
+type AutocertConfig struct {
+	Manager          *autocert.Manager
+	ChallengeAddress string       // optional; defaults to DefaultChallengeAddress
+	Fallback         http.Handler // optional; serves non-challenge requests on the challenge address, which are otherwise redirected to https
+}
+
+// NewAutocertServer returns a runner which serves handler over TLS on address, with certificates obtained and
+// renewed by config.Manager.  The runner also listens on the challenge address, without TLS, to answer the HTTP-01
+// challenges of the certificate authority; both listeners are opened before the runner is ready, and both are
+// closed when it exits.
+//
+// Certificates are requested for the host named by each TLS handshake, so config.Manager.HostPolicy should be set
+// to avoid requesting certificates for arbitrary hosts.
+func NewAutocertServer(address string, handler http.Handler, config AutocertConfig, opts ...Option) ifrit.Runner {
+	challengeAddress := config.ChallengeAddress
+	if challengeAddress == "" {
+		challengeAddress = DefaultChallengeAddress
+	}
+
+	server := newServerWithListener(TCP, address, handler, config.Manager.TLSConfig(), opts).(*httpServer)
+	server.companion = &companionServer{
+		address: challengeAddress,
+		handler: config.Manager.HTTPHandler(config.Fallback),
+	}
+	return server
+}
+
+// A companionServer serves a handler of its own alongside the runner's, for as long as the runner is running.  Each
+// run serves with a new http.Server, since one which has been closed cannot serve again.
+type companionServer struct {
+	address string
+	handler http.Handler
+	server  *http.Server
+}
+
+func (c *companionServer) start(errs chan<- error) error {
+	listener, err := net.Listen(TCP, c.address)
+	if err != nil {
+		return err
+	}
+
+	server := &http.Server{Handler: c.handler}
+	c.server = server
+	go func() {
+		err := server.Serve(listener)
+		if err != http.ErrServerClosed {
+			errs <- err
+		}
+	}()
+	return nil
+}
+
+func (c *companionServer) stop() {
+	c.server.Close()
+}
@@ -0,0 +1,121 @@
+package http_server_test
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"syscall"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/tedsuo/ifrit"
+	"github.com/tedsuo/ifrit/http_server"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+var _ = Describe("NewAutocertServer", func() {
+	var (
+		address          string
+		challengeAddress string
+		config           http_server.AutocertConfig
+		runner           ifrit.Runner
+		process          ifrit.Process
+	)
+
+	BeforeEach(func() {
+		address = fmt.Sprintf("127.0.0.1:%d", 8300+GinkgoParallelNode())
+		challengeAddress = fmt.Sprintf("127.0.0.1:%d", 8400+GinkgoParallelNode())
+		config = http_server.AutocertConfig{
+			Manager: &autocert.Manager{
+				Prompt:     autocert.AcceptTOS,
+				HostPolicy: autocert.HostWhitelist("example.com"),
+			},
+			ChallengeAddress: challengeAddress,
+		}
+	})
+
+	Context("when it starts successfully", func() {
+		BeforeEach(func() {
+			runner = http_server.NewAutocertServer(address, http.NotFoundHandler(), config)
+			process = ifrit.Invoke(runner)
+		})
+
+		AfterEach(func() {
+			process.Signal(syscall.SIGINT)
+			Eventually(process.Wait()).Should(Receive())
+		})
+
+		get := func(client *http.Client, path string) (*http.Response, error) {
+			req, err := http.NewRequest("GET", "http://"+challengeAddress+path, nil)
+			Ω(err).ShouldNot(HaveOccurred())
+			req.Host = "example.com"
+			return client.Do(req)
+		}
+
+		It("answers challenges for hosts within the host policy on the challenge address", func() {
+			resp, err := get(http.DefaultClient, "/.well-known/acme-challenge/unknown-token")
+			Ω(err).ShouldNot(HaveOccurred())
+			resp.Body.Close()
+			Ω(resp.StatusCode).Should(Equal(http.StatusNotFound))
+		})
+
+		It("redirects other requests on the challenge address to https", func() {
+			client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			}}
+			resp, err := get(client, "/some/path")
+			Ω(err).ShouldNot(HaveOccurred())
+			resp.Body.Close()
+			Ω(resp.StatusCode).Should(Equal(http.StatusFound))
+			Ω(resp.Header.Get("Location")).Should(Equal("https://example.com/some/path"))
+		})
+
+		It("refuses handshakes for hosts outside the host policy", func() {
+			_, err := tls.Dial("tcp", address, &tls.Config{ServerName: "example.org"})
+			Ω(err).Should(HaveOccurred())
+		})
+
+		It("closes the challenge address when it exits", func() {
+			process.Signal(syscall.SIGINT)
+			Eventually(process.Wait()).Should(Receive())
+
+			_, err := net.Dial("tcp", challengeAddress)
+			Ω(err).Should(HaveOccurred())
+		})
+
+		It("answers challenges again when it is run again", func() {
+			process.Signal(syscall.SIGINT)
+			Eventually(process.Wait()).Should(Receive())
+
+			process = ifrit.Invoke(runner)
+			resp, err := get(http.DefaultClient, "/.well-known/acme-challenge/unknown-token")
+			Ω(err).ShouldNot(HaveOccurred())
+			resp.Body.Close()
+			Ω(resp.StatusCode).Should(Equal(http.StatusNotFound))
+			Consistently(process.Wait()).ShouldNot(Receive())
+		})
+	})
+
+	Context("when the challenge address cannot be listened on", func() {
+		var listener net.Listener
+
+		BeforeEach(func() {
+			var err error
+			listener, err = net.Listen("tcp", challengeAddress)
+			Ω(err).ShouldNot(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			listener.Close()
+		})
+
+		It("exits with an error and closes its TLS address", func() {
+			process = ifrit.Invoke(http_server.NewAutocertServer(address, http.NotFoundHandler(), config))
+			Eventually(process.Wait()).Should(Receive(HaveOccurred()))
+
+			_, err := net.Dial("tcp", address)
+			Ω(err).Should(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,29 @@
+package http_server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net/http"
+)
+
+// ErrClientCAWithoutCertificate is returned by a runner configured with WithClientCAFile which has no certificate of
+// its own to serve TLS with.
+var ErrClientCAWithoutCertificate = errors.New("http_server: WithClientCAFile requires a server certificate")
+
+// ClientCertificate returns the certificate the client of r presented and the server verified, or nil if there is
+// none; see WithClientCAFile.
+func ClientCertificate(r *http.Request) *x509.Certificate {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		return nil
+	}
+	return r.TLS.VerifiedChains[0][0]
+}
+
+// hasCertificate reports whether tlsConfig gives the server a certificate to present.
+func hasCertificate(tlsConfig *tls.Config) bool {
+	if tlsConfig == nil {
+		return false
+	}
+	return len(tlsConfig.Certificates) > 0 || tlsConfig.GetCertificate != nil || tlsConfig.GetConfigForClient != nil
+}
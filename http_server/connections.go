@@ -0,0 +1,85 @@
+package http_server
+
+import (
+	"net"
+	"net/http"
+	"sync"
+)
+
+// ConnectionStats counts the server's open connections.
+type ConnectionStats struct {
+	Active   int  // connections serving a request
+	Idle     int  // connections waiting for a request, including new ones
+	Draining bool // whether the runner has begun shutting down
+}
+
+// connTracker records the state of every open connection.  It is updated from the server's connection goroutines,
+// so that they never wait for the goroutine running the server.
+type connTracker struct {
+	mu       sync.Mutex
+	closed   *sync.Cond
+	states   map[net.Conn]http.ConnState
+	stats    ConnectionStats
+	callback func(ConnectionStats)
+}
+
+func newConnTracker(callback func(ConnectionStats)) *connTracker {
+	t := &connTracker{
+		states:   map[net.Conn]http.ConnState{},
+		callback: callback,
+	}
+	t.closed = sync.NewCond(&t.mu)
+	return t
+}
+
+func (t *connTracker) Update(conn net.Conn, state http.ConnState) {
+	t.mu.Lock()
+	if previous, found := t.states[conn]; found {
+		t.count(previous, -1)
+	}
+	switch state {
+	case http.StateHijacked, http.StateClosed:
+		delete(t.states, conn)
+		t.closed.Broadcast()
+	default:
+		t.states[conn] = state
+		t.count(state, 1)
+	}
+	stats := t.stats
+	t.mu.Unlock()
+
+	t.report(stats)
+}
+
+func (t *connTracker) count(state http.ConnState, delta int) {
+	if state == http.StateActive {
+		t.stats.Active += delta
+	} else {
+		t.stats.Idle += delta
+	}
+}
+
+// Drain marks the start of the runner's shutdown.
+func (t *connTracker) Drain() {
+	t.mu.Lock()
+	t.stats.Draining = true
+	stats := t.stats
+	t.mu.Unlock()
+
+	t.report(stats)
+}
+
+// Wait blocks until every connection has been closed or hijacked.
+func (t *connTracker) Wait() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for len(t.states) != 0 {
+		t.closed.Wait()
+	}
+}
+
+func (t *connTracker) report(stats ConnectionStats) {
+	if t.callback != nil {
+		t.callback(stats)
+	}
+}
@@ -0,0 +1,158 @@
+/*
+The http3_server package runs an HTTP/3 server, using quic-go, alongside the TLS
+server of the http_server package.  Both serve the same handler on the same
+address, over UDP and TCP respectively, and responses served over TCP advertise
+the HTTP/3 endpoint with an Alt-Svc header.
+*/
+package http3_server
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/quic-go/quic-go"
+	"github.com/quic-go/quic-go/http3"
+	"github.com/tedsuo/ifrit"
+	"github.com/tedsuo/ifrit/http_server"
+)
+
+// Config describes the HTTP/3 specific behaviour of the runner.
+type Config struct {
+	// QUICConfig is optional; if nil, quic-go's defaults are used.
+	QUICConfig *quic.Config
+
+	// ShutdownTimeout is how long HTTP/3 requests which are in flight when the runner is signaled may take to
+	// complete before their connections are closed.  If zero, the runner waits for them indefinitely.
+	ShutdownTimeout time.Duration
+}
+
+// A ShutdownTimeoutError is returned when HTTP/3 requests did not complete within the shutdown timeout.
+type ShutdownTimeoutError struct {
+	Timeout time.Duration
+}
+
+func (e ShutdownTimeoutError) Error() string {
+	return fmt.Sprintf("http3 server did not shut down within %s and was closed forcefully", e.Timeout)
+}
+
+type http3Server struct {
+	address string
+	server  *http3.Server
+	tcp     ifrit.Runner
+	timeout time.Duration
+	altSvc  *atomic.Value
+}
+
+// New returns a runner which serves handler with HTTP/3 on the UDP port of address, and with the runner returned by
+// http_server.NewTLSServer, configured by opts, on its TCP port.  The runner is ready once both are serving.  When it
+// is signaled both shut down together, and it exits once both have exited.  If either fails, the other is stopped.
+//
+// Every signal shuts the runner down, so WithReloadSignal and WithCertificateFiles are not supported; to rotate
+// certificates, give tlsConfig a GetCertificate function, such as one from the certificate_reloader package.
+func New(address string, handler http.Handler, tlsConfig *tls.Config, config Config, opts ...http_server.Option) ifrit.Runner {
+	server := &http3.Server{
+		Handler:    handler,
+		TLSConfig:  http3.ConfigureTLSConfig(tlsConfig),
+		QUICConfig: config.QUICConfig,
+	}
+
+	altSvc := &atomic.Value{}
+	return &http3Server{
+		address: address,
+		server:  server,
+		tcp:     http_server.NewTLSServer(address, altSvcHandler(altSvc, handler), tlsConfig, opts...),
+		timeout: config.ShutdownTimeout,
+		altSvc:  altSvc,
+	}
+}
+
+// altSvcHandler adds the Alt-Svc header held by altSvc, which the runner sets to advertise the port its UDP listener
+// bound before it starts the TCP server.  The header is built by the runner, rather than by
+// http3.Server.SetQUICHeaders, so that it is present from the runner's first response.
+func altSvcHandler(altSvc *atomic.Value, handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if header, _ := altSvc.Load().(string); header != "" {
+			w.Header().Add("Alt-Svc", header)
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
+
+func (s *http3Server) Run(signals <-chan os.Signal, ready chan<- struct{}) error {
+	conn, err := net.ListenPacket("udp", s.address)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if addr, ok := conn.LocalAddr().(*net.UDPAddr); ok {
+		s.altSvc.Store(fmt.Sprintf(`%s=":%d"; ma=2592000`, http3.NextProtoH3, addr.Port))
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- s.server.Serve(conn)
+	}()
+
+	tcp := ifrit.Background(s.tcp)
+	select {
+	case <-tcp.Ready():
+	case err := <-serveErr:
+		tcp.Signal(os.Kill)
+		<-tcp.Wait()
+		return err
+	case err := <-tcp.Wait():
+		s.close(serveErr)
+		return err
+	}
+
+	close(ready)
+
+	select {
+	case signal := <-signals:
+		tcp.Signal(signal)
+		err := s.shutdown()
+		<-serveErr
+		tcpErr := <-tcp.Wait()
+		if err != nil {
+			return err
+		}
+		return tcpErr
+
+	case err := <-serveErr:
+		tcp.Signal(os.Kill)
+		<-tcp.Wait()
+		return err
+
+	case err := <-tcp.Wait():
+		s.close(serveErr)
+		return err
+	}
+}
+
+// close stops the HTTP/3 server immediately, and waits for it to stop serving.
+func (s *http3Server) close(serveErr <-chan error) {
+	s.server.Close()
+	<-serveErr
+}
+
+func (s *http3Server) shutdown() error {
+	ctx := context.Background()
+	if s.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.timeout)
+		defer cancel()
+	}
+
+	err := s.server.Shutdown(ctx)
+	if err == context.DeadlineExceeded {
+		return ShutdownTimeoutError{Timeout: s.timeout}
+	}
+	return err
+}
@@ -0,0 +1,13 @@
+package http3_server_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestHTTP3Server(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "HTTP3 Server Suite")
+}
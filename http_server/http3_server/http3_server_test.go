@@ -0,0 +1,202 @@
+package http3_server_test
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"syscall"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/quic-go/quic-go/http3"
+	"github.com/tedsuo/ifrit"
+	"github.com/tedsuo/ifrit/http_server"
+	"github.com/tedsuo/ifrit/http_server/http3_server"
+)
+
+var _ = Describe("HTTP3Server", func() {
+	var (
+		address   string
+		tlsConfig *tls.Config
+		config    http3_server.Config
+		process   ifrit.Process
+
+		startedRequest chan struct{}
+		finishRequest  chan struct{}
+
+		h3Client  *http.Client
+		tcpClient *http.Client
+	)
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			startedRequest <- struct{}{}
+			select {
+			case <-finishRequest:
+			case <-r.Context().Done():
+			}
+		}
+		w.Write([]byte(r.Proto))
+	})
+
+	BeforeEach(func() {
+		address = fmt.Sprintf("127.0.0.1:%d", 8500+GinkgoParallelNode())
+
+		basePath := path.Join(os.Getenv("GOPATH"), "src", "github.com", "tedsuo", "ifrit", "http_server", "test_certs")
+		cert, err := tls.LoadX509KeyPair(path.Join(basePath, "server.crt"), path.Join(basePath, "server.key"))
+		Ω(err).ShouldNot(HaveOccurred())
+		tlsConfig = &tls.Config{Certificates: []tls.Certificate{cert}}
+		config = http3_server.Config{}
+
+		startedRequest = make(chan struct{}, 1)
+		finishRequest = make(chan struct{}, 1)
+
+		h3Client = &http.Client{Transport: &http3.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}}
+		tcpClient = &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}}
+	})
+
+	body := func(resp *http.Response) string {
+		defer resp.Body.Close()
+		b, err := ioutil.ReadAll(resp.Body)
+		Ω(err).ShouldNot(HaveOccurred())
+		return string(b)
+	}
+
+	Context("when the runner starts successfully", func() {
+		JustBeforeEach(func() {
+			process = ifrit.Invoke(http3_server.New(address, handler, tlsConfig, config))
+		})
+
+		AfterEach(func() {
+			process.Signal(syscall.SIGINT)
+			Eventually(process.Wait()).Should(Receive())
+		})
+
+		It("serves HTTP/3 requests", func() {
+			resp, err := h3Client.Get("https://" + address + "/")
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(body(resp)).Should(Equal("HTTP/3.0"))
+		})
+
+		It("advertises HTTP/3 from the TCP listener", func() {
+			resp, err := tcpClient.Get("https://" + address + "/")
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(body(resp)).Should(Equal("HTTP/1.1"))
+
+			_, port, _ := net.SplitHostPort(address)
+			Ω(resp.Header.Get("Alt-Svc")).Should(Equal(`h3=":` + port + `"; ma=2592000`))
+		})
+
+		It("stops both listeners when signaled", func() {
+			process.Signal(syscall.SIGINT)
+			Eventually(process.Wait()).Should(Receive(BeNil()))
+
+			_, err := net.Dial("tcp", address)
+			Ω(err).Should(HaveOccurred())
+
+			conn, err := net.ListenPacket("udp", address)
+			Ω(err).ShouldNot(HaveOccurred())
+			conn.Close()
+		})
+
+		It("waits for in-flight HTTP/3 requests", func() {
+			errs := make(chan error, 1)
+			go func() {
+				resp, err := h3Client.Get("https://" + address + "/slow")
+				if err == nil {
+					resp.Body.Close()
+				}
+				errs <- err
+			}()
+			Eventually(startedRequest).Should(Receive())
+
+			process.Signal(syscall.SIGINT)
+			Consistently(process.Wait()).ShouldNot(Receive())
+
+			finishRequest <- struct{}{}
+			Eventually(errs).Should(Receive(BeNil()))
+			Eventually(process.Wait()).Should(Receive(BeNil()))
+		})
+
+		Context("and it has a shutdown timeout", func() {
+			BeforeEach(func() {
+				config.ShutdownTimeout = 100 * time.Millisecond
+			})
+
+			It("closes HTTP/3 connections once the timeout elapses", func() {
+				go h3Client.Get("https://" + address + "/slow")
+				Eventually(startedRequest).Should(Receive())
+
+				process.Signal(syscall.SIGINT)
+				var err error
+				Eventually(process.Wait()).Should(Receive(&err))
+				Ω(err).Should(Equal(http3_server.ShutdownTimeoutError{Timeout: 100 * time.Millisecond}))
+			})
+		})
+	})
+
+	Context("when the address has no port", func() {
+		var tcpAddresses chan net.Addr
+
+		BeforeEach(func() {
+			tcpAddresses = make(chan net.Addr, 1)
+			process = ifrit.Invoke(http3_server.New("127.0.0.1:0", handler, tlsConfig, config,
+				http_server.WithAddressCallback(func(addr net.Addr) {
+					tcpAddresses <- addr
+				}),
+			))
+		})
+
+		AfterEach(func() {
+			process.Signal(syscall.SIGINT)
+			Eventually(process.Wait()).Should(Receive())
+		})
+
+		It("advertises the port the UDP listener bound", func() {
+			var tcpAddress net.Addr
+			Eventually(tcpAddresses).Should(Receive(&tcpAddress))
+
+			resp, err := tcpClient.Get("https://" + tcpAddress.String() + "/")
+			Ω(err).ShouldNot(HaveOccurred())
+			resp.Body.Close()
+
+			altSvc := resp.Header.Get("Alt-Svc")
+			Ω(altSvc).Should(MatchRegexp(`^h3=":[0-9]+"; ma=2592000$`))
+			port := strings.TrimSuffix(strings.TrimPrefix(altSvc, `h3=":`), `"; ma=2592000`)
+			Ω(port).ShouldNot(Equal("0"))
+
+			resp, err = h3Client.Get("https://127.0.0.1:" + port + "/")
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(body(resp)).Should(Equal("HTTP/3.0"))
+		})
+	})
+
+	Context("when the TCP address cannot be listened on", func() {
+		var listener net.Listener
+
+		BeforeEach(func() {
+			var err error
+			listener, err = net.Listen("tcp", address)
+			Ω(err).ShouldNot(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			listener.Close()
+		})
+
+		It("exits with an error and closes the UDP address", func() {
+			process = ifrit.Invoke(http3_server.New(address, handler, tlsConfig, config))
+			Eventually(process.Wait()).Should(Receive(HaveOccurred()))
+
+			conn, err := net.ListenPacket("udp", address)
+			Ω(err).ShouldNot(HaveOccurred())
+			conn.Close()
+		})
+	})
+})
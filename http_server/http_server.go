@@ -1,114 +1,319 @@
 package http_server
 
 import (
+	"context"
 	"crypto/tls"
+	"fmt"
 	"net"
 	"net/http"
 	"os"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/tedsuo/ifrit"
+	"github.com/tedsuo/ifrit/certificate_reloader"
 )
 
 const (
 	TCP  = "tcp"
 	UNIX = "unix"
+
+	unixScheme = "unix://"
 )
 
 type httpServer struct {
 	protocol string
 	address  string
+	listener net.Listener
 	handler  http.Handler
 
+	extraAddresses []string
+
 	connectionWaitGroup   *sync.WaitGroup
 	inactiveConnections   map[net.Conn]struct{}
 	inactiveConnectionsMu *sync.Mutex
 	stoppingChan          chan struct{}
 
 	tlsConfig *tls.Config
+
+	shutdownTimeout   time.Duration
+	h2c               bool
+	http2Config       *http.HTTP2Config
+	certFiles         *certificateFiles
+	reloadSignal      os.Signal
+	addressCallbacks  []func(net.Addr)
+	serverSettings    []func(*http.Server)
+	connStatsCallback func(ConnectionStats)
+	probes            bool
+	proxyProtocol     *proxyProtocol
+	companion         *companionServer
+	connLimit         *ConnectionLimit
+	cancelOnShutdown  bool
+	onShutdown        []func()
+	registry          *ConnectionRegistry
+	registryGrace     time.Duration
+	clientCAFile      *clientCAFile
+	name              string
+	accessLogger      AccessLogger
+}
+
+type certificateFiles struct {
+	certFile     string
+	keyFile      string
+	pollInterval time.Duration
+}
+
+type clientCAFile struct {
+	caFile       string
+	pollInterval time.Duration
 }
 
-func newServerWithListener(protocol, address string, handler http.Handler, tlsConfig *tls.Config) ifrit.Runner {
-	return &httpServer{
+// A ShutdownTimeoutError is returned by a runner configured with WithShutdownTimeout when in-flight requests did not
+// complete within the timeout, and their connections were closed forcefully.
+type ShutdownTimeoutError struct {
+	Timeout time.Duration
+}
+
+func (e ShutdownTimeoutError) Error() string {
+	return fmt.Sprintf("http server did not shut down within %s and was closed forcefully", e.Timeout)
+}
+
+func newServerWithListener(protocol, address string, handler http.Handler, tlsConfig *tls.Config, opts []Option) ifrit.Runner {
+	server := &httpServer{
 		address:   address,
 		handler:   handler,
 		tlsConfig: tlsConfig,
 		protocol:  protocol,
 	}
+	for _, opt := range opts {
+		opt(server)
+	}
+	return server
+}
+
+func NewUnixServer(address string, handler http.Handler, opts ...Option) ifrit.Runner {
+	return newServerWithListener(UNIX, address, handler, nil, opts)
+}
+
+func New(address string, handler http.Handler, opts ...Option) ifrit.Runner {
+	return newServerWithListener(TCP, address, handler, nil, opts)
+}
+
+func NewUnixTLSServer(address string, handler http.Handler, tlsConfig *tls.Config, opts ...Option) ifrit.Runner {
+	return newServerWithListener(UNIX, address, handler, tlsConfig, opts)
+}
+
+func NewTLSServer(address string, handler http.Handler, tlsConfig *tls.Config, opts ...Option) ifrit.Runner {
+	return newServerWithListener(TCP, address, handler, tlsConfig, opts)
 }
 
-func NewUnixServer(address string, handler http.Handler) ifrit.Runner {
-	return newServerWithListener(UNIX, address, handler, nil)
+// NewFromListener returns a runner which serves on lis rather than opening a listener of its own, such as a socket
+// passed by systemd socket activation or inherited from a parent process; see the socket_activation package.  The
+// runner takes ownership of lis, and closes it when it exits.
+func NewFromListener(lis net.Listener, handler http.Handler, opts ...Option) ifrit.Runner {
+	return NewTLSServerFromListener(lis, handler, nil, opts...)
 }
 
-func New(address string, handler http.Handler) ifrit.Runner {
-	return newServerWithListener(TCP, address, handler, nil)
+// NewTLSServerFromListener is the TLS equivalent of NewFromListener.
+func NewTLSServerFromListener(lis net.Listener, handler http.Handler, tlsConfig *tls.Config, opts ...Option) ifrit.Runner {
+	server := newServerWithListener(lis.Addr().Network(), lis.Addr().String(), handler, tlsConfig, opts).(*httpServer)
+	server.listener = lis
+	return server
 }
 
-func NewUnixTLSServer(address string, handler http.Handler, tlsConfig *tls.Config) ifrit.Runner {
-	return newServerWithListener(UNIX, address, handler, tlsConfig)
+// A ServerError is returned by a runner named by WithName, and identifies the runner which failed.
+type ServerError struct {
+	Name    string
+	Address string
+	Err     error
 }
 
-func NewTLSServer(address string, handler http.Handler, tlsConfig *tls.Config) ifrit.Runner {
-	return newServerWithListener(TCP, address, handler, tlsConfig)
+func (e *ServerError) Error() string {
+	return fmt.Sprintf("http server %q (%s): %s", e.Name, e.Address, e.Err)
+}
+
+func (e *ServerError) Unwrap() error {
+	return e.Err
 }
 
 func (s *httpServer) Run(signals <-chan os.Signal, ready chan<- struct{}) error {
+	err := s.run(signals, ready)
+	if err != nil && s.name != "" {
+		return &ServerError{Name: s.name, Address: s.address, Err: err}
+	}
+	return err
+}
+
+func (s *httpServer) run(signals <-chan os.Signal, ready chan<- struct{}) error {
 	s.connectionWaitGroup = new(sync.WaitGroup)
 	s.inactiveConnectionsMu = new(sync.Mutex)
 	s.inactiveConnections = make(map[net.Conn]struct{})
 	s.stoppingChan = make(chan struct{})
 
-	connCountCh := make(chan int)
+	conns := newConnTracker(s.connStatsCallback)
+	if s.registry != nil {
+		s.registry.reset()
+	}
+
+	// a listener the runner was given is closed if the runner fails before it serves; getListeners closes it once it
+	// has been handed over
+	ownsListener := s.listener != nil
+	defer func() {
+		if ownsListener {
+			s.listener.Close()
+		}
+	}()
+
+	tlsConfig := s.tlsConfig
+	var reloader *certificate_reloader.Reloader
+	if s.certFiles != nil {
+		var err error
+		reloader, err = certificate_reloader.New(s.certFiles.certFile, s.certFiles.keyFile)
+		if err != nil {
+			return err
+		}
+		tlsConfig = reloader.TLSConfig(tlsConfig)
+
+		if s.certFiles.pollInterval > 0 {
+			done := make(chan struct{})
+			defer close(done)
+			go reloader.Watch(s.certFiles.pollInterval, done)
+		}
+	}
+
+	var caPool *certificate_reloader.CAPool
+	if s.clientCAFile != nil {
+		var err error
+		caPool, err = certificate_reloader.NewCAPool(s.clientCAFile.caFile)
+		if err != nil {
+			return err
+		}
+
+		if s.clientCAFile.pollInterval > 0 {
+			done := make(chan struct{})
+			defer close(done)
+			go caPool.Watch(s.clientCAFile.pollInterval, done)
+		}
+	}
+
+	handler := s.handler
+	if s.accessLogger != nil {
+		handler = AccessLogHandler(s.accessLogger, handler)
+	}
+
+	var probes *probeHandler
+	if s.probes {
+		probes = &probeHandler{handler: handler}
+		handler = probes
+	}
 
 	server := http.Server{
-		Handler:   s.handler,
-		TLSConfig: s.tlsConfig,
+		Handler:   handler,
+		TLSConfig: s.withHTTP2(tlsConfig),
+		Protocols: s.protocols(),
+		HTTP2:     s.http2Config,
 		ConnState: func(conn net.Conn, state http.ConnState) {
 			switch state {
-			case http.StateNew:
-				connCountCh <- 1
+			case http.StateNew, http.StateIdle:
 				s.addInactiveConnection(conn)
 
-			case http.StateIdle:
-				s.addInactiveConnection(conn)
-
-			case http.StateActive:
-				s.removeInactiveConnection(conn)
-
-			case http.StateHijacked, http.StateClosed:
+			case http.StateActive, http.StateHijacked, http.StateClosed:
 				s.removeInactiveConnection(conn)
-				connCountCh <- -1
 			}
+			conns.Update(conn, state)
 		},
 	}
 
-	listener, err := s.getListener(server.TLSConfig)
+	baseCtx, cancelBaseCtx := context.WithCancel(context.Background())
+	defer cancelBaseCtx()
+	if s.cancelOnShutdown {
+		server.BaseContext = func(net.Listener) context.Context { return baseCtx }
+	}
+
+	for _, setting := range s.serverSettings {
+		setting(&server)
+	}
+
+	if caPool != nil {
+		if !hasCertificate(server.TLSConfig) {
+			return ErrClientCAWithoutCertificate
+		}
+		server.TLSConfig = caPool.ClientTLSConfig(server.TLSConfig)
+	}
+
+	ownsListener = false
+	listeners, err := s.getListeners(server.TLSConfig)
 	if err != nil {
 		return err
 	}
 
-	serverErrChan := make(chan error, 1)
-	go func() {
-		serverErrChan <- server.Serve(listener)
-	}()
+	serverErrChan := make(chan error, len(listeners)+1)
+	if s.companion != nil {
+		err := s.companion.start(serverErrChan)
+		if err != nil {
+			closeListeners(listeners)
+			return err
+		}
+		defer s.companion.stop()
+	}
+
+	for _, listener := range listeners {
+		go func(listener net.Listener) {
+			serverErrChan <- server.Serve(listener)
+		}(listener)
+	}
 
+	for _, listener := range listeners {
+		for _, callback := range s.addressCallbacks {
+			callback(listener.Addr())
+		}
+	}
+	if probes != nil {
+		probes.SetReady(true)
+	}
 	close(ready)
 
-	connCount := 0
 	for {
 		select {
 		case err = <-serverErrChan:
+			closeListeners(listeners)
 			return err
 
-		case delta := <-connCountCh:
-			connCount += delta
+		case signal := <-signals:
+			if s.reloadSignal != nil && signal == s.reloadSignal {
+				if reloader != nil {
+					reloader.Reload()
+				}
+				if caPool != nil {
+					caPool.Reload()
+				}
+				continue
+			}
+
+			if probes != nil {
+				probes.SetReady(false)
+			}
+			cancelBaseCtx()
+			for _, f := range s.onShutdown {
+				go f()
+			}
+			if s.registry != nil {
+				registryDrained := make(chan struct{})
+				go func() {
+					s.registry.drain(s.registryGrace)
+					close(registryDrained)
+				}()
+				defer func() { <-registryDrained }()
+			}
+			conns.Drain()
+			if s.shutdownTimeout > 0 {
+				return s.shutdown(&server)
+			}
 
-		case <-signals:
 			close(s.stoppingChan)
 
-			listener.Close()
+			closeListeners(listeners)
 
 			s.inactiveConnectionsMu.Lock()
 			for c := range s.inactiveConnections {
@@ -116,32 +321,106 @@ func (s *httpServer) Run(signals <-chan os.Signal, ready chan<- struct{}) error
 			}
 			s.inactiveConnectionsMu.Unlock()
 
-			for connCount != 0 {
-				delta := <-connCountCh
-				connCount += delta
-			}
+			conns.Wait()
 
 			return nil
 		}
 	}
 }
 
-func (s *httpServer) getListener(tlsConfig *tls.Config) (net.Listener, error) {
-	listener, err := net.Listen(s.protocol, s.address)
-	if err != nil {
-		return nil, err
+// shutdown stops the server with http.Server.Shutdown, closing it forcefully once the shutdown timeout elapses.
+func (s *httpServer) shutdown(server *http.Server) error {
+	ctx, cancel := context.WithTimeout(context.Background(), s.shutdownTimeout)
+	defer cancel()
+
+	err := server.Shutdown(ctx)
+	if err == context.DeadlineExceeded {
+		server.Close()
+		err = ShutdownTimeoutError{Timeout: s.shutdownTimeout}
 	}
-	if tlsConfig == nil {
-		return listener, nil
+
+	return err
+}
+
+// withHTTP2 advertises HTTP/2 through ALPN when it has been configured, unless tlsConfig already chooses its
+// protocols.
+func (s *httpServer) withHTTP2(tlsConfig *tls.Config) *tls.Config {
+	if tlsConfig == nil || s.http2Config == nil || len(tlsConfig.NextProtos) > 0 {
+		return tlsConfig
 	}
-	switch s.protocol {
-	case TCP:
-		listener = tls.NewListener(tcpKeepAliveListener{listener.(*net.TCPListener)}, tlsConfig)
-	default:
-		listener = tls.NewListener(listener, tlsConfig)
+
+	tlsConfig = tlsConfig.Clone()
+	tlsConfig.NextProtos = []string{"h2", "http/1.1"}
+	return tlsConfig
+}
+
+func (s *httpServer) protocols() *http.Protocols {
+	if !s.h2c {
+		return nil
 	}
 
-	return listener, nil
+	protocols := new(http.Protocols)
+	protocols.SetHTTP1(true)
+	protocols.SetHTTP2(true)
+	protocols.SetUnencryptedHTTP2(true)
+	return protocols
+}
+
+// getListeners returns the runner's listener, or a new listener for its address, followed by a listener for each
+// additional address.  If any listener cannot be opened, the ones already opened are closed.
+func (s *httpServer) getListeners(tlsConfig *tls.Config) ([]net.Listener, error) {
+	listener := s.listener
+	if listener == nil {
+		var err error
+		listener, err = net.Listen(s.protocol, s.address)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	listeners := []net.Listener{listener}
+	for _, address := range s.extraAddresses {
+		protocol := TCP
+		if strings.HasPrefix(address, unixScheme) {
+			protocol, address = UNIX, strings.TrimPrefix(address, unixScheme)
+		}
+
+		listener, err := net.Listen(protocol, address)
+		if err != nil {
+			closeListeners(listeners)
+			return nil, err
+		}
+		listeners = append(listeners, listener)
+	}
+
+	var limiter *connectionLimiter
+	if s.connLimit != nil {
+		limiter = newConnectionLimiter(*s.connLimit)
+	}
+
+	for i, listener := range listeners {
+		if l, ok := listener.(*net.TCPListener); ok && tlsConfig != nil {
+			listener = tcpKeepAliveListener{l}
+		}
+		if limiter != nil {
+			listener = limiter.Wrap(listener)
+		}
+		if s.proxyProtocol != nil {
+			listener = s.proxyProtocol.Wrap(listener)
+		}
+		if tlsConfig != nil {
+			listener = tls.NewListener(listener, tlsConfig)
+		}
+		listeners[i] = listener
+	}
+
+	return listeners, nil
+}
+
+func closeListeners(listeners []net.Listener) {
+	for _, listener := range listeners {
+		listener.Close()
+	}
 }
 
 func (s *httpServer) addInactiveConnection(conn net.Conn) {
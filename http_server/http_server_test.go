@@ -1,16 +1,32 @@
 package http_server_test
 
 import (
+	"bufio"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
 	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
+	"log/slog"
+	"math/big"
+	"net"
 	"net/http"
+	"net/netip"
 	"os"
 	"path"
+	"strings"
 	"syscall"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
 	"github.com/tedsuo/ifrit"
 	"github.com/tedsuo/ifrit/http_server"
 	"github.com/tedsuo/ifrit/http_server/unix_transport"
@@ -146,6 +162,701 @@ var _ = Describe("HttpServer", func() {
 			})
 		})
 
+		Context("when the server has a shutdown timeout", func() {
+			var responses chan error
+
+			BeforeEach(func() {
+				server = http_server.New(address, handler, http_server.WithShutdownTimeout(200*time.Millisecond))
+				process = ifrit.Invoke(server)
+
+				responses = make(chan error, 1)
+				go func() {
+					_, err := httpGet("http://" + address)
+					responses <- err
+				}()
+				Eventually(startedRequestChan).Should(Receive())
+
+				process.Signal(syscall.SIGINT)
+			})
+
+			AfterEach(func() {
+				finishRequestChan <- struct{}{}
+				Eventually(process.Wait()).Should(Receive())
+			})
+
+			It("stops accepting new connections", func() {
+				Eventually(func() error {
+					_, err := httpGet("http://" + address)
+					return err
+				}).Should(HaveOccurred())
+			})
+
+			It("exits cleanly once in-flight requests complete", func() {
+				Consistently(process.Wait(), 100*time.Millisecond).ShouldNot(Receive())
+				finishRequestChan <- struct{}{}
+				Eventually(responses).Should(Receive(BeNil()))
+				Eventually(process.Wait()).Should(Receive(BeNil()))
+			})
+
+			It("closes in-flight requests once the timeout elapses", func() {
+				var err error
+				Eventually(process.Wait()).Should(Receive(&err))
+				Ω(err).Should(Equal(http_server.ShutdownTimeoutError{Timeout: 200 * time.Millisecond}))
+				Eventually(responses).Should(Receive(HaveOccurred()))
+			})
+		})
+
+		Context("when the server serves h2c", func() {
+			BeforeEach(func() {
+				protoHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.Write([]byte(r.Proto))
+				})
+				server = http_server.New(address, protoHandler, http_server.WithH2C())
+				process = ifrit.Invoke(server)
+			})
+
+			AfterEach(func() {
+				process.Signal(syscall.SIGINT)
+				Eventually(process.Wait()).Should(Receive())
+			})
+
+			It("serves HTTP/2 requests without TLS", func() {
+				protocols := new(http.Protocols)
+				protocols.SetUnencryptedHTTP2(true)
+				client := http.Client{Transport: &http.Transport{Protocols: protocols}}
+
+				resp, err := client.Get("http://" + address)
+				Ω(err).ShouldNot(HaveOccurred())
+				body, err := ioutil.ReadAll(resp.Body)
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(body)).Should(Equal("HTTP/2.0"))
+			})
+
+			It("still serves HTTP/1 requests", func() {
+				resp, err := httpGet("http://" + address)
+				Ω(err).ShouldNot(HaveOccurred())
+				body, err := ioutil.ReadAll(resp.Body)
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(body)).Should(Equal("HTTP/1.1"))
+			})
+		})
+
+		Context("when the server serves certificates from files", func() {
+			var tmpdir string
+
+			copyPair := func(name string) {
+				basePath := path.Join(os.Getenv("GOPATH"), "src", "github.com", "tedsuo", "ifrit", "http_server", "test_certs")
+				for _, ext := range []string{".crt", ".key"} {
+					contents, err := ioutil.ReadFile(path.Join(basePath, name+ext))
+					Ω(err).ShouldNot(HaveOccurred())
+					err = ioutil.WriteFile(path.Join(tmpdir, "cert"+ext), contents, 0600)
+					Ω(err).ShouldNot(HaveOccurred())
+				}
+			}
+
+			servedCommonName := func() string {
+				conn, err := tls.Dial("tcp", address, &tls.Config{InsecureSkipVerify: true})
+				Ω(err).ShouldNot(HaveOccurred())
+				defer conn.Close()
+				return conn.ConnectionState().PeerCertificates[0].Subject.CommonName
+			}
+
+			BeforeEach(func() {
+				var err error
+				tmpdir, err = ioutil.TempDir(os.TempDir(), "ifrit-server-test")
+				Ω(err).ShouldNot(HaveOccurred())
+				copyPair("server")
+			})
+
+			AfterEach(func() {
+				process.Signal(syscall.SIGINT)
+				Eventually(process.Wait()).Should(Receive())
+				os.RemoveAll(tmpdir)
+			})
+
+			It("serves the new certificate once the files change", func() {
+				server = http_server.New(address, handler,
+					http_server.WithCertificateFiles(path.Join(tmpdir, "cert.crt"), path.Join(tmpdir, "cert.key"), 10*time.Millisecond),
+				)
+				process = ifrit.Invoke(server)
+				Ω(servedCommonName()).Should(Equal("bbs.service.cf.internal"))
+
+				time.Sleep(20 * time.Millisecond)
+				copyPair("client")
+				Eventually(servedCommonName).Should(Equal("bbs client"))
+			})
+
+			It("serves the new certificate once signaled, without exiting", func() {
+				server = http_server.New(address, handler,
+					http_server.WithCertificateFiles(path.Join(tmpdir, "cert.crt"), path.Join(tmpdir, "cert.key"), 0),
+					http_server.WithReloadSignal(syscall.SIGHUP),
+				)
+				process = ifrit.Invoke(server)
+
+				copyPair("client")
+				Consistently(servedCommonName, 50*time.Millisecond).Should(Equal("bbs.service.cf.internal"))
+
+				process.Signal(syscall.SIGHUP)
+				Eventually(servedCommonName).Should(Equal("bbs client"))
+				Consistently(process.Wait()).ShouldNot(Receive())
+			})
+		})
+
+		Context("when the server is given a listener", func() {
+			var listener net.Listener
+
+			BeforeEach(func() {
+				var err error
+				listener, err = net.Listen("tcp", "127.0.0.1:0")
+				Ω(err).ShouldNot(HaveOccurred())
+
+				yoHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.Write([]byte("yo"))
+				})
+				server = http_server.NewFromListener(listener, yoHandler)
+				process = ifrit.Invoke(server)
+			})
+
+			It("serves requests on the listener", func() {
+				resp, err := httpGet("http://" + listener.Addr().String())
+				Ω(err).ShouldNot(HaveOccurred())
+				body, err := ioutil.ReadAll(resp.Body)
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(body)).Should(Equal("yo"))
+
+				process.Signal(syscall.SIGINT)
+				Eventually(process.Wait()).Should(Receive())
+			})
+
+			It("closes the listener when it exits", func() {
+				process.Signal(syscall.SIGINT)
+				Eventually(process.Wait()).Should(Receive())
+
+				_, err := httpGet("http://" + listener.Addr().String())
+				Ω(err).Should(HaveOccurred())
+			})
+		})
+
+		Context("when the server is given a listener and fails to start", func() {
+			It("closes the listener", func() {
+				listener, err := net.Listen("tcp", "127.0.0.1:0")
+				Ω(err).ShouldNot(HaveOccurred())
+
+				server = http_server.NewFromListener(listener, handler, http_server.WithCertificateFiles("missing.crt", "missing.key", 0))
+				process = ifrit.Invoke(server)
+				Eventually(process.Wait()).Should(Receive(HaveOccurred()))
+
+				_, err = listener.Accept()
+				Ω(err).Should(MatchError(net.ErrClosed))
+			})
+		})
+
+		Context("when the server listens on port 0", func() {
+			var boundAddress net.Addr
+
+			BeforeEach(func() {
+				yoHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.Write([]byte("yo"))
+				})
+				server = http_server.New("127.0.0.1:0", yoHandler, http_server.WithAddressCallback(func(addr net.Addr) {
+					boundAddress = addr
+				}))
+				process = ifrit.Invoke(server)
+			})
+
+			AfterEach(func() {
+				process.Signal(syscall.SIGINT)
+				Eventually(process.Wait()).Should(Receive())
+			})
+
+			It("reports the bound address before becoming ready", func() {
+				Ω(boundAddress).ShouldNot(BeNil())
+				Ω(boundAddress.(*net.TCPAddr).Port).ShouldNot(BeZero())
+
+				resp, err := httpGet("http://" + boundAddress.String())
+				Ω(err).ShouldNot(HaveOccurred())
+				body, err := ioutil.ReadAll(resp.Body)
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(body)).Should(Equal("yo"))
+			})
+		})
+
+		Context("when the server is tuned", func() {
+			var logs *gbytes.Buffer
+
+			BeforeEach(func() {
+				logs = gbytes.NewBuffer()
+				panicHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					if r.URL.Path == "/panic" {
+						panic("boom")
+					}
+					w.Write([]byte("yo"))
+				})
+				server = http_server.New(address, panicHandler,
+					http_server.WithReadHeaderTimeout(50*time.Millisecond),
+					http_server.WithMaxHeaderBytes(1024),
+					http_server.WithErrorLogHandler(slog.NewTextHandler(logs, nil)),
+				)
+				process = ifrit.Invoke(server)
+			})
+
+			AfterEach(func() {
+				process.Signal(syscall.SIGINT)
+				Eventually(process.Wait()).Should(Receive())
+			})
+
+			It("closes connections which do not send headers in time", func() {
+				conn, err := net.Dial("tcp", address)
+				Ω(err).ShouldNot(HaveOccurred())
+				defer conn.Close()
+
+				conn.SetReadDeadline(time.Now().Add(time.Second))
+				_, err = conn.Read(make([]byte, 1))
+				Ω(err).Should(Equal(io.EOF))
+			})
+
+			It("rejects requests with oversized headers", func() {
+				req, err := http.NewRequest("GET", "http://"+address, nil)
+				Ω(err).ShouldNot(HaveOccurred())
+				req.Header.Set("X-Large", strings.Repeat("x", 8192))
+
+				resp, err := http.DefaultClient.Do(req)
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(resp.StatusCode).Should(Equal(http.StatusRequestHeaderFieldsTooLarge))
+			})
+
+			It("logs errors to the configured handler", func() {
+				_, err := httpGet("http://" + address + "/panic")
+				Ω(err).Should(HaveOccurred())
+				Eventually(logs).Should(gbytes.Say("level=ERROR.*panic serving"))
+			})
+		})
+
+		Context("when the server reports connection stats", func() {
+			var stats chan http_server.ConnectionStats
+
+			BeforeEach(func() {
+				stats = make(chan http_server.ConnectionStats, 100)
+				server = http_server.New(address, handler, http_server.WithConnectionStats(func(s http_server.ConnectionStats) {
+					stats <- s
+				}))
+				process = ifrit.Invoke(server)
+			})
+
+			It("reports active connections, and the progress of the drain", func() {
+				go httpGet("http://" + address)
+				Eventually(startedRequestChan).Should(Receive())
+				Eventually(stats).Should(Receive(Equal(http_server.ConnectionStats{Active: 1})))
+
+				process.Signal(syscall.SIGINT)
+				Eventually(stats).Should(Receive(Equal(http_server.ConnectionStats{Active: 1, Draining: true})))
+
+				finishRequestChan <- struct{}{}
+				Eventually(stats).Should(Receive(Equal(http_server.ConnectionStats{Draining: true})))
+				Eventually(process.Wait()).Should(Receive())
+			})
+		})
+
+		Context("when the server listens on additional addresses", func() {
+			var (
+				tmpdir       string
+				socketPath   string
+				extraAddress string
+			)
+
+			BeforeEach(func() {
+				var err error
+				tmpdir, err = ioutil.TempDir(os.TempDir(), "ifrit-server-test")
+				Ω(err).ShouldNot(HaveOccurred())
+				socketPath = path.Join(tmpdir, "ifrit.sock")
+				extraAddress = fmt.Sprintf("127.0.0.1:%d", 8100+GinkgoParallelNode())
+
+				yoHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.Write([]byte("yo"))
+				})
+				server = http_server.New(address, yoHandler, http_server.WithAdditionalAddresses(extraAddress, "unix://"+socketPath))
+				process = ifrit.Invoke(server)
+			})
+
+			AfterEach(func() {
+				process.Signal(syscall.SIGINT)
+				Eventually(process.Wait()).Should(Receive())
+				os.RemoveAll(tmpdir)
+			})
+
+			It("serves the handler on every address", func() {
+				for _, url := range []string{"http://" + address, "http://" + extraAddress} {
+					resp, err := httpGet(url)
+					Ω(err).ShouldNot(HaveOccurred())
+					body, err := ioutil.ReadAll(resp.Body)
+					Ω(err).ShouldNot(HaveOccurred())
+					Ω(string(body)).Should(Equal("yo"))
+				}
+
+				resp, err := httpGetUnix("unix://"+socketPath+"/", socketPath)
+				Ω(err).ShouldNot(HaveOccurred())
+				body, err := ioutil.ReadAll(resp.Body)
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(body)).Should(Equal("yo"))
+			})
+
+			It("stops serving on every address when signaled", func() {
+				process.Signal(syscall.SIGINT)
+				Eventually(process.Wait()).Should(Receive())
+
+				_, err := httpGet("http://" + extraAddress)
+				Ω(err).Should(HaveOccurred())
+				Ω(socketPath).ShouldNot(BeAnExistingFile())
+			})
+		})
+
+		Context("when an additional address cannot be listened on", func() {
+			BeforeEach(func() {
+				server = http_server.New(address, handler, http_server.WithAdditionalAddresses(address))
+			})
+
+			It("exits with an error and releases the other addresses", func() {
+				err := <-ifrit.Invoke(server).Wait()
+				Ω(err).Should(HaveOccurred())
+
+				listener, err := net.Listen("tcp", address)
+				Ω(err).ShouldNot(HaveOccurred())
+				listener.Close()
+			})
+		})
+
+		Context("when the server serves probes", func() {
+			var client http.Client
+
+			probe := func(path string) int {
+				resp, err := client.Get("http://" + address + path)
+				Ω(err).ShouldNot(HaveOccurred())
+				resp.Body.Close()
+				return resp.StatusCode
+			}
+
+			BeforeEach(func() {
+				// h2c multiplexes the probes onto the connection of an in-flight request, which remains open
+				// while the runner drains
+				protocols := new(http.Protocols)
+				protocols.SetUnencryptedHTTP2(true)
+				client = http.Client{Transport: &http.Transport{Protocols: protocols}}
+
+				server = http_server.New(address, handler, http_server.WithProbes(), http_server.WithH2C())
+				process = ifrit.Invoke(server)
+			})
+
+			AfterEach(func() {
+				process.Signal(syscall.SIGINT)
+				Eventually(process.Wait()).Should(Receive())
+			})
+
+			It("reports healthy and ready while running", func() {
+				Ω(probe("/healthz")).Should(Equal(http.StatusOK))
+				Ω(probe("/readyz")).Should(Equal(http.StatusOK))
+			})
+
+			It("reports not ready once shutdown begins", func() {
+				go client.Get("http://" + address + "/")
+				Eventually(startedRequestChan).Should(Receive())
+
+				process.Signal(syscall.SIGINT)
+				Eventually(func() int { return probe("/readyz") }).Should(Equal(http.StatusServiceUnavailable))
+				Ω(probe("/healthz")).Should(Equal(http.StatusOK))
+
+				finishRequestChan <- struct{}{}
+			})
+		})
+
+		Context("when the server reads the PROXY protocol", func() {
+			var trusted netip.Prefix
+
+			remoteAddr := func(header []byte) (int, string) {
+				conn, err := net.Dial("tcp", address)
+				Ω(err).ShouldNot(HaveOccurred())
+				defer conn.Close()
+
+				_, err = conn.Write(append(header, "GET / HTTP/1.0\r\n\r\n"...))
+				Ω(err).ShouldNot(HaveOccurred())
+				resp, err := http.ReadResponse(bufio.NewReader(conn), nil)
+				Ω(err).ShouldNot(HaveOccurred())
+				body, err := ioutil.ReadAll(resp.Body)
+				Ω(err).ShouldNot(HaveOccurred())
+				return resp.StatusCode, string(body)
+			}
+
+			BeforeEach(func() {
+				trusted = netip.MustParsePrefix("127.0.0.0/8")
+			})
+
+			JustBeforeEach(func() {
+				addrHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.Write([]byte(r.RemoteAddr))
+				})
+				server = http_server.New(address, addrHandler, http_server.WithProxyProtocol(trusted))
+				process = ifrit.Invoke(server)
+			})
+
+			AfterEach(func() {
+				process.Signal(syscall.SIGINT)
+				Eventually(process.Wait()).Should(Receive())
+			})
+
+			It("reports the client address from a version 1 header", func() {
+				status, addr := remoteAddr([]byte("PROXY TCP4 192.0.2.1 192.0.2.2 56324 80\r\n"))
+				Ω(status).Should(Equal(http.StatusOK))
+				Ω(addr).Should(Equal("192.0.2.1:56324"))
+
+				status, addr = remoteAddr([]byte("PROXY TCP6 2001:db8::1 2001:db8::2 56324 80\r\n"))
+				Ω(status).Should(Equal(http.StatusOK))
+				Ω(addr).Should(Equal("[2001:db8::1]:56324"))
+			})
+
+			It("reports the client address from a version 2 header", func() {
+				header := []byte("\r\n\r\n\x00\r\nQUIT\n\x21\x11\x00\x0c")
+				header = append(header, 192, 0, 2, 1, 192, 0, 2, 2, 0xdc, 0x04, 0x00, 0x50)
+
+				status, addr := remoteAddr(header)
+				Ω(status).Should(Equal(http.StatusOK))
+				Ω(addr).Should(Equal("192.0.2.1:56324"))
+			})
+
+			It("reports the connection's own address when there is no header", func() {
+				status, addr := remoteAddr(nil)
+				Ω(status).Should(Equal(http.StatusOK))
+				Ω(addr).Should(HavePrefix("127.0.0.1:"))
+			})
+
+			It("closes connections with a malformed header", func() {
+				conn, err := net.Dial("tcp", address)
+				Ω(err).ShouldNot(HaveOccurred())
+				defer conn.Close()
+
+				_, err = conn.Write([]byte("PROXY TCP4 not-an-address\r\nGET / HTTP/1.0\r\n\r\n"))
+				Ω(err).ShouldNot(HaveOccurred())
+				_, err = http.ReadResponse(bufio.NewReader(conn), nil)
+				Ω(err).Should(HaveOccurred())
+			})
+
+			Context("and the client is not trusted", func() {
+				BeforeEach(func() {
+					trusted = netip.MustParsePrefix("10.0.0.0/8")
+				})
+
+				It("does not read the header", func() {
+					status, _ := remoteAddr([]byte("PROXY TCP4 192.0.2.1 192.0.2.2 56324 80\r\n"))
+					Ω(status).Should(Equal(http.StatusBadRequest))
+				})
+			})
+		})
+
+		Context("when the server limits connections", func() {
+			var (
+				limit http_server.ConnectionLimit
+				stats chan http_server.ConnectionLimitStats
+				held  net.Conn
+			)
+
+			BeforeEach(func() {
+				stats = make(chan http_server.ConnectionLimitStats, 100)
+				limit = http_server.ConnectionLimit{
+					Max:      1,
+					OnChange: func(s http_server.ConnectionLimitStats) { stats <- s },
+				}
+			})
+
+			JustBeforeEach(func() {
+				server = http_server.New(address, http.NotFoundHandler(), http_server.WithConnectionLimit(limit))
+				process = ifrit.Invoke(server)
+
+				var err error
+				held, err = net.Dial("tcp", address)
+				Ω(err).ShouldNot(HaveOccurred())
+				Eventually(stats).Should(Receive(Equal(http_server.ConnectionLimitStats{Open: 1})))
+			})
+
+			AfterEach(func() {
+				held.Close()
+				process.Signal(syscall.SIGINT)
+				Eventually(process.Wait()).Should(Receive())
+			})
+
+			It("refuses connections beyond the limit", func() {
+				_, err := httpGet("http://" + address)
+				Ω(err).Should(HaveOccurred())
+				Eventually(stats).Should(Receive(Equal(http_server.ConnectionLimitStats{Open: 1, Refused: 1})))
+			})
+
+			Context("and it queues connections", func() {
+				BeforeEach(func() {
+					limit.QueueTimeout = 200 * time.Millisecond
+				})
+
+				It("serves a queued connection once another closes", func() {
+					errs := make(chan error, 1)
+					go func() {
+						resp, err := httpGet("http://" + address)
+						if err == nil {
+							resp.Body.Close()
+						}
+						errs <- err
+					}()
+					Eventually(stats).Should(Receive(Equal(http_server.ConnectionLimitStats{Open: 1, Queued: 1})))
+
+					held.Close()
+					Eventually(errs).Should(Receive(BeNil()))
+				})
+
+				It("refuses a queued connection once the timeout elapses", func() {
+					_, err := httpGet("http://" + address)
+					Ω(err).Should(HaveOccurred())
+					Eventually(stats).Should(Receive(Equal(http_server.ConnectionLimitStats{Open: 1, Refused: 1})))
+				})
+			})
+		})
+
+		Context("when the server cancels requests on shutdown", func() {
+			var shutdownCalled chan struct{}
+
+			BeforeEach(func() {
+				shutdownCalled = make(chan struct{})
+				ctxHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					startedRequestChan <- struct{}{}
+					<-r.Context().Done()
+				})
+				server = http_server.New(address, ctxHandler,
+					http_server.WithCancelOnShutdown(),
+					http_server.WithOnShutdown(func() { close(shutdownCalled) }),
+				)
+				process = ifrit.Invoke(server)
+			})
+
+			It("cancels in-flight requests and calls the shutdown functions", func() {
+				go httpGet("http://" + address)
+				Eventually(startedRequestChan).Should(Receive())
+				Consistently(shutdownCalled).ShouldNot(BeClosed())
+
+				process.Signal(syscall.SIGINT)
+				Eventually(shutdownCalled).Should(BeClosed())
+				Eventually(process.Wait()).Should(Receive(BeNil()))
+			})
+		})
+
+		Context("when the server requires client certificates", func() {
+			var (
+				tmpdir      string
+				caFile      string
+				first       tls.Certificate
+				firstPEM    []byte
+				second      tls.Certificate
+				secondPEM   []byte
+				clientNamed func(tls.Certificate) (string, error)
+			)
+
+			BeforeEach(func() {
+				var err error
+				tmpdir, err = ioutil.TempDir(os.TempDir(), "ifrit-server-test")
+				Ω(err).ShouldNot(HaveOccurred())
+
+				first, firstPEM = selfSignedCertificate("first")
+				second, secondPEM = selfSignedCertificate("second")
+				caFile = path.Join(tmpdir, "ca.crt")
+				Ω(ioutil.WriteFile(caFile, firstPEM, 0600)).Should(Succeed())
+
+				basePath := path.Join(os.Getenv("GOPATH"), "src", "github.com", "tedsuo", "ifrit", "http_server", "test_certs")
+				serverCert, err := tls.LoadX509KeyPair(path.Join(basePath, "server.crt"), path.Join(basePath, "server.key"))
+				Ω(err).ShouldNot(HaveOccurred())
+
+				identityHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.Write([]byte(http_server.ClientCertificate(r).Subject.CommonName))
+				})
+				server = http_server.NewTLSServer(address, identityHandler, &tls.Config{Certificates: []tls.Certificate{serverCert}},
+					http_server.WithClientCAFile(caFile, 10*time.Millisecond),
+				)
+				process = ifrit.Invoke(server)
+
+				clientNamed = func(cert tls.Certificate) (string, error) {
+					resp, err := httpTLSGet("https://"+address, &tls.Config{
+						InsecureSkipVerify: true,
+						Certificates:       []tls.Certificate{cert},
+					})
+					if err != nil {
+						return "", err
+					}
+					defer resp.Body.Close()
+					body, err := ioutil.ReadAll(resp.Body)
+					return string(body), err
+				}
+			})
+
+			AfterEach(func() {
+				process.Signal(syscall.SIGINT)
+				Eventually(process.Wait()).Should(Receive())
+				os.RemoveAll(tmpdir)
+			})
+
+			It("serves clients with a trusted certificate, and reports their identity", func() {
+				Ω(clientNamed(first)).Should(Equal("first"))
+
+				_, err := clientNamed(second)
+				Ω(err).Should(HaveOccurred())
+				_, err = clientNamed(tls.Certificate{})
+				Ω(err).Should(HaveOccurred())
+			})
+
+			It("trusts the new CAs once the file changes", func() {
+				time.Sleep(20 * time.Millisecond)
+				Ω(ioutil.WriteFile(caFile, secondPEM, 0600)).Should(Succeed())
+
+				Eventually(func() (string, error) { return clientNamed(second) }).Should(Equal("second"))
+				_, err := clientNamed(first)
+				Ω(err).Should(HaveOccurred())
+			})
+		})
+
+		Context("when the server requires client certificates but has no certificate of its own", func() {
+			BeforeEach(func() {
+				server = http_server.New(address, handler, http_server.WithClientCAFile(path.Join("test_certs", "server-ca.crt"), 0))
+			})
+
+			It("returns an error", func() {
+				process = ifrit.Background(server)
+				Eventually(process.Wait()).Should(Receive(Equal(http_server.ErrClientCAWithoutCertificate)))
+			})
+		})
+
+		Context("when the server logs requests", func() {
+			var entries chan http_server.AccessLogEntry
+
+			BeforeEach(func() {
+				entries = make(chan http_server.AccessLogEntry, 10)
+				logger := http_server.AccessLoggerFunc(func(e http_server.AccessLogEntry) { entries <- e })
+				server = http_server.New(address, http.NotFoundHandler(), http_server.WithAccessLog(logger), http_server.WithProbes())
+				process = ifrit.Invoke(server)
+			})
+
+			AfterEach(func() {
+				process.Signal(syscall.SIGINT)
+				Eventually(process.Wait()).Should(Receive())
+			})
+
+			It("records requests to the handler, but not probes", func() {
+				resp, err := httpGet("http://" + address + "/healthz")
+				Ω(err).ShouldNot(HaveOccurred())
+				resp.Body.Close()
+
+				resp, err = httpGet("http://" + address + "/missing")
+				Ω(err).ShouldNot(HaveOccurred())
+				resp.Body.Close()
+
+				var entry http_server.AccessLogEntry
+				Eventually(entries).Should(Receive(&entry))
+				Ω(entry.Path).Should(Equal("/missing"))
+				Ω(entry.Status).Should(Equal(http.StatusNotFound))
+				Consistently(entries).ShouldNot(Receive())
+			})
+		})
+
 		Context("when the server fails to start", func() {
 			BeforeEach(func() {
 				address = fmt.Sprintf("127.0.0.1:80")
@@ -159,6 +870,36 @@ var _ = Describe("HttpServer", func() {
 			})
 		})
 
+		Context("when a named server fails", func() {
+			var listener net.Listener
+
+			BeforeEach(func() {
+				var err error
+				listener, err = net.Listen("tcp", address)
+				Ω(err).ShouldNot(HaveOccurred())
+			})
+
+			AfterEach(func() {
+				listener.Close()
+			})
+
+			It("wraps the error with the runner's name and address", func() {
+				process = ifrit.Invoke(http_server.New(address, handler, http_server.WithName("api")))
+
+				var err error
+				Eventually(process.Wait()).Should(Receive(&err))
+
+				var serverErr *http_server.ServerError
+				Ω(errors.As(err, &serverErr)).Should(BeTrue())
+				Ω(serverErr.Name).Should(Equal("api"))
+				Ω(serverErr.Address).Should(Equal(address))
+				Ω(err.Error()).Should(HavePrefix(fmt.Sprintf(`http server "api" (%s): listen tcp`, address)))
+
+				var opErr *net.OpError
+				Ω(errors.As(err, &opErr)).Should(BeTrue())
+			})
+		})
+
 		Context("when the TLS server is started with a different net Protocol.", func() {
 			var tlsConfig *tls.Config
 			var tmpdir string
@@ -293,6 +1034,40 @@ var _ = Describe("HttpServer", func() {
 				})
 			})
 		})
+
+		Context("and it starts a server with TLS and an HTTP/2 config", func() {
+			BeforeEach(func() {
+				basePath := path.Join(os.Getenv("GOPATH"), "src", "github.com", "tedsuo", "ifrit", "http_server", "test_certs")
+				tlsCert, err := tls.LoadX509KeyPair(path.Join(basePath, "server.crt"), path.Join(basePath, "server.key"))
+				Expect(err).NotTo(HaveOccurred())
+
+				protoHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.Write([]byte(r.Proto))
+				})
+				server = http_server.NewTLSServer(address, protoHandler, &tls.Config{Certificates: []tls.Certificate{tlsCert}},
+					http_server.WithHTTP2Config(http.HTTP2Config{MaxConcurrentStreams: 10}),
+				)
+				process = ifrit.Invoke(server)
+			})
+
+			AfterEach(func() {
+				process.Signal(syscall.SIGINT)
+				Eventually(process.Wait()).Should(Receive())
+			})
+
+			It("serves HTTP/2 requests over TLS", func() {
+				client := http.Client{Transport: &http.Transport{
+					TLSClientConfig:   &tls.Config{InsecureSkipVerify: true},
+					ForceAttemptHTTP2: true,
+				}}
+
+				resp, err := client.Get("https://" + address)
+				Ω(err).ShouldNot(HaveOccurred())
+				body, err := ioutil.ReadAll(resp.Body)
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(body)).Should(Equal("HTTP/2.0"))
+			})
+		})
 	})
 })
 
@@ -328,3 +1103,22 @@ func httpTLSGetUnix(url string, socketPath string, tlsConfig *tls.Config) (*http
 	}
 	return client.Get(url)
 }
+
+// selfSignedCertificate returns a self-signed certificate for commonName, usable as its own CA, and the certificate
+// in PEM format.
+func selfSignedCertificate(commonName string) (tls.Certificate, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Ω(err).ShouldNot(HaveOccurred())
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	Ω(err).ShouldNot(HaveOccurred())
+
+	cert := tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+	return cert, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
@@ -0,0 +1,147 @@
+package http_server
+
+import (
+	"net"
+	"sync"
+	"time"
+)
+
+// A ConnectionLimit bounds the number of connections open at once, across all of the runner's listeners.
+type ConnectionLimit struct {
+	Max int
+
+	// QueueTimeout is how long a connection accepted beyond Max waits for another to close before it is refused.
+	// While a connection waits, further connections wait in the listener's backlog.  If zero, connections beyond
+	// Max are refused as soon as they are accepted.
+	QueueTimeout time.Duration
+
+	// OnChange is optional, and is invoked with the limiter's counters whenever they change.  It must not block.
+	OnChange func(ConnectionLimitStats)
+}
+
+// ConnectionLimitStats counts the connections seen by a ConnectionLimit.
+type ConnectionLimitStats struct {
+	Open    int    // connections being served
+	Queued  int    // connections waiting for another to close
+	Refused uint64 // connections closed without being served, since the runner started
+}
+
+// connectionLimiter enforces a ConnectionLimit.  It is shared by all of the runner's listeners.
+type connectionLimiter struct {
+	limit ConnectionLimit
+	slots chan struct{}
+
+	mu    sync.Mutex
+	stats ConnectionLimitStats
+}
+
+func newConnectionLimiter(limit ConnectionLimit) *connectionLimiter {
+	return &connectionLimiter{
+		limit: limit,
+		slots: make(chan struct{}, limit.Max),
+	}
+}
+
+func (l *connectionLimiter) Wrap(lis net.Listener) net.Listener {
+	return &limitedListener{Listener: lis, limiter: l, closed: make(chan struct{})}
+}
+
+func (l *connectionLimiter) update(change func(*ConnectionLimitStats)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	change(&l.stats)
+	if l.limit.OnChange != nil {
+		l.limit.OnChange(l.stats)
+	}
+}
+
+func (l *connectionLimiter) acquired() {
+	l.update(func(stats *ConnectionLimitStats) { stats.Open++ })
+}
+
+func (l *connectionLimiter) release() {
+	<-l.slots
+	l.update(func(stats *ConnectionLimitStats) { stats.Open-- })
+}
+
+func (l *connectionLimiter) refuse(conn net.Conn) {
+	conn.Close()
+	l.update(func(stats *ConnectionLimitStats) { stats.Refused++ })
+}
+
+type limitedListener struct {
+	net.Listener
+	limiter *connectionLimiter
+
+	closeOnce sync.Once
+	closed    chan struct{}
+}
+
+func (l *limitedListener) Accept() (net.Conn, error) {
+	for {
+		conn, err := l.Listener.Accept()
+		if err != nil {
+			return nil, err
+		}
+
+		ok, err := l.acquire()
+		if err != nil {
+			conn.Close()
+			return nil, err
+		}
+		if !ok {
+			l.limiter.refuse(conn)
+			continue
+		}
+
+		l.limiter.acquired()
+		return &limitedConn{Conn: conn, release: l.limiter.release}, nil
+	}
+}
+
+// acquire takes a slot for a newly accepted connection, waiting up to the queue timeout for one to become free.  It
+// fails if the listener is closed while it waits.
+func (l *limitedListener) acquire() (bool, error) {
+	select {
+	case l.limiter.slots <- struct{}{}:
+		return true, nil
+	default:
+	}
+
+	timeout := l.limiter.limit.QueueTimeout
+	if timeout <= 0 {
+		return false, nil
+	}
+
+	l.limiter.update(func(stats *ConnectionLimitStats) { stats.Queued++ })
+	defer l.limiter.update(func(stats *ConnectionLimitStats) { stats.Queued-- })
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case l.limiter.slots <- struct{}{}:
+		return true, nil
+	case <-timer.C:
+		return false, nil
+	case <-l.closed:
+		return false, net.ErrClosed
+	}
+}
+
+func (l *limitedListener) Close() error {
+	l.closeOnce.Do(func() { close(l.closed) })
+	return l.Listener.Close()
+}
+
+type limitedConn struct {
+	net.Conn
+	release func()
+	once    sync.Once
+}
+
+func (c *limitedConn) Close() error {
+	c.once.Do(c.release)
+	return c.Conn.Close()
+}
@@ -0,0 +1,238 @@
+package http_server
+
+import (
+	"log"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/netip"
+	"os"
+	"time"
+)
+
+// An Option configures optional behavior of the runners returned by New, NewTLSServer, NewUnixServer and
+// NewUnixTLSServer.
+type Option func(*httpServer)
+
+// WithShutdownTimeout shuts the server down with http.Server.Shutdown when the runner is signaled.  The server stops
+// accepting connections, closes idle ones, and waits up to timeout for in-flight requests to complete.  Once the
+// timeout elapses the remaining connections are closed, and the runner exits with a ShutdownTimeoutError.
+//
+// By default the runner waits indefinitely for in-flight requests.
+func WithShutdownTimeout(timeout time.Duration) Option {
+	return func(s *httpServer) {
+		s.shutdownTimeout = timeout
+	}
+}
+
+// WithH2C serves HTTP/2 without TLS, known as h2c, alongside HTTP/1 to clients which have prior knowledge of it, as
+// gRPC-web proxies and service mesh sidecars do.  It has no effect on connections secured by TLS.
+func WithH2C() Option {
+	return func(s *httpServer) {
+		s.h2c = true
+	}
+}
+
+// WithHTTP2Config tunes the server's HTTP/2 settings, such as the maximum number of concurrent streams and the
+// maximum frame size.  It also enables HTTP/2 for TLS servers, by advertising "h2" through ALPN, unless the
+// runner's tlsConfig already sets NextProtos.
+func WithHTTP2Config(config http.HTTP2Config) Option {
+	return func(s *httpServer) {
+		s.http2Config = &config
+	}
+}
+
+// WithCertificateFiles serves the certificate in the certFile/keyFile pair, and reloads it without interrupting the
+// server whenever either file changes.  The files are checked for changes every pollInterval; if pollInterval is
+// zero they are only reloaded when the runner receives its reload signal, see WithReloadSignal.  Connections which are
+// already established keep using the certificate they were opened with.  If the pair cannot be loaded when the
+// runner is invoked, the runner exits with an error; if it cannot be loaded later, the previous certificate remains in
+// use.
+//
+// The server runs with TLS when this option is used, even if it was created by New or NewUnixServer.  Any
+// certificates in the tlsConfig are ignored.
+func WithCertificateFiles(certFile, keyFile string, pollInterval time.Duration) Option {
+	return func(s *httpServer) {
+		s.certFiles = &certificateFiles{
+			certFile:     certFile,
+			keyFile:      keyFile,
+			pollInterval: pollInterval,
+		}
+	}
+}
+
+// WithClientCAFile requires clients to present a certificate signed by one of the CAs in caFile, and reloads the file
+// without interrupting the server whenever it changes, as WithCertificateFiles does for the server's certificate.
+// The runner must serve TLS, through its tlsConfig or WithCertificateFiles; otherwise it returns
+// ErrClientCAWithoutCertificate.  Handlers may find the client's verified certificate with ClientCertificate.
+func WithClientCAFile(caFile string, pollInterval time.Duration) Option {
+	return func(s *httpServer) {
+		s.clientCAFile = &clientCAFile{
+			caFile:       caFile,
+			pollInterval: pollInterval,
+		}
+	}
+}
+
+// WithReloadSignal designates a signal, typically syscall.SIGHUP, which causes the runner to reload the certificate
+// files given by WithCertificateFiles and WithClientCAFile and continue serving, rather than shut down.
+func WithReloadSignal(signal os.Signal) Option {
+	return func(s *httpServer) {
+		s.reloadSignal = signal
+	}
+}
+
+// WithAddressCallback invokes callback with the address the server is bound to before the runner becomes ready.  This
+// allows the port chosen for a ":0" address to be discovered, by tests or for service registration.  The option may
+// be given more than once.
+func WithAddressCallback(callback func(net.Addr)) Option {
+	return func(s *httpServer) {
+		s.addressCallbacks = append(s.addressCallbacks, callback)
+	}
+}
+
+func withServerSetting(setting func(*http.Server)) Option {
+	return func(s *httpServer) {
+		s.serverSettings = append(s.serverSettings, setting)
+	}
+}
+
+// WithReadTimeout sets the http.Server's ReadTimeout, the maximum duration for reading an entire request.
+func WithReadTimeout(timeout time.Duration) Option {
+	return withServerSetting(func(server *http.Server) {
+		server.ReadTimeout = timeout
+	})
+}
+
+// WithReadHeaderTimeout sets the http.Server's ReadHeaderTimeout, the maximum duration for reading request headers.
+func WithReadHeaderTimeout(timeout time.Duration) Option {
+	return withServerSetting(func(server *http.Server) {
+		server.ReadHeaderTimeout = timeout
+	})
+}
+
+// WithWriteTimeout sets the http.Server's WriteTimeout, the maximum duration before timing out writes of a response.
+func WithWriteTimeout(timeout time.Duration) Option {
+	return withServerSetting(func(server *http.Server) {
+		server.WriteTimeout = timeout
+	})
+}
+
+// WithIdleTimeout sets the http.Server's IdleTimeout, the maximum duration to wait for the next request on a
+// keep-alive connection.
+func WithIdleTimeout(timeout time.Duration) Option {
+	return withServerSetting(func(server *http.Server) {
+		server.IdleTimeout = timeout
+	})
+}
+
+// WithMaxHeaderBytes sets the http.Server's MaxHeaderBytes, the maximum size of request headers.
+func WithMaxHeaderBytes(max int) Option {
+	return withServerSetting(func(server *http.Server) {
+		server.MaxHeaderBytes = max
+	})
+}
+
+// WithErrorLog logs errors accepting connections, unexpected behavior from handlers, and underlying file system
+// errors to logger, rather than to the standard logger.
+func WithErrorLog(logger *log.Logger) Option {
+	return withServerSetting(func(server *http.Server) {
+		server.ErrorLog = logger
+	})
+}
+
+// WithErrorLogHandler logs the same errors as WithErrorLog to handler, at slog.LevelError.
+func WithErrorLogHandler(handler slog.Handler) Option {
+	return WithErrorLog(slog.NewLogLogger(handler, slog.LevelError))
+}
+
+// WithConnectionStats invokes callback with the number of active and idle connections whenever a connection changes
+// state, and once more when the runner begins shutting down.  During shutdown it reports the progress of the drain.
+// callback is invoked from the goroutine of the connection which changed state, so it may be invoked concurrently, and
+// delays that connection while it runs.
+func WithConnectionStats(callback func(ConnectionStats)) Option {
+	return func(s *httpServer) {
+		s.connStatsCallback = callback
+	}
+}
+
+// WithAdditionalAddresses serves the same handler on each of addresses, in addition to the runner's own address.  An
+// address of the form "unix:///path/to/socket" listens on a Unix domain socket; all others listen on TCP.  Every
+// listener uses the runner's TLS configuration, and all of them are drained together when the runner shuts down.
+func WithAdditionalAddresses(addresses ...string) Option {
+	return func(s *httpServer) {
+		s.extraAddresses = append(s.extraAddresses, addresses...)
+	}
+}
+
+// WithProbes serves liveness and readiness probes, such as those used by Kubernetes, ahead of the runner's handler.
+// /healthz succeeds whenever the server is serving.  /readyz succeeds once the runner is ready, and fails with
+// 503 Service Unavailable once the runner begins shutting down.
+func WithProbes() Option {
+	return func(s *httpServer) {
+		s.probes = true
+	}
+}
+
+// WithProxyProtocol reads PROXY protocol headers, versions 1 and 2, from connections made by load balancers such as
+// HAProxy or an AWS Network Load Balancer, so that the client's own address is reported as the request's RemoteAddr.
+// Headers are only read from TCP connections whose source address is within one of trusted; connections from other
+// sources are served as is, so that they cannot spoof their address.  Trusted sources may omit the header.
+func WithProxyProtocol(trusted ...netip.Prefix) Option {
+	return func(s *httpServer) {
+		s.proxyProtocol = &proxyProtocol{trusted: trusted}
+	}
+}
+
+// WithConnectionLimit bounds the number of connections the runner serves at once, across all of its listeners, as
+// described by limit.  Connections count against the limit from when they are accepted, including during the TLS
+// handshake, until they are closed.
+func WithConnectionLimit(limit ConnectionLimit) Option {
+	return func(s *httpServer) {
+		s.connLimit = &limit
+	}
+}
+
+// WithCancelOnShutdown cancels the context of every request, including those in flight, as soon as the runner is
+// signaled to shut down, so that handlers doing long work may abandon it rather than hold up the drain.
+func WithCancelOnShutdown() Option {
+	return func(s *httpServer) {
+		s.cancelOnShutdown = true
+	}
+}
+
+// WithOnShutdown calls f, in a goroutine of its own, when the runner is signaled to shut down, like
+// http.Server.RegisterOnShutdown.  It is called whether or not the runner has a shutdown timeout, and may be used to
+// notify or close connections the server does not track, such as hijacked ones.
+func WithOnShutdown(f func()) Option {
+	return func(s *httpServer) {
+		s.onShutdown = append(s.onShutdown, f)
+	}
+}
+
+// WithConnectionRegistry shuts down the long-lived connections which handlers register with registry, such as
+// WebSockets, alongside the connections the server tracks itself.  When the runner is signaled, registered
+// connections are notified, and those still registered once gracePeriod elapses are closed.  The runner exits once
+// both the server and the registry have drained.
+func WithConnectionRegistry(registry *ConnectionRegistry, gracePeriod time.Duration) Option {
+	return func(s *httpServer) {
+		s.registry = registry
+		s.registryGrace = gracePeriod
+	}
+}
+
+// WithName names the runner, so that it can be told apart from others, for example in a grouper.  Every error the
+// runner returns is wrapped in a ServerError which carries the name and the runner's address.
+func WithName(name string) Option {
+	return func(s *httpServer) {
+		s.name = name
+	}
+}
+
+// WithAccessLog records an entry with logger for every request served by the runner's handler, as AccessLogHandler
+// does.  Probe requests served by WithProbes are not recorded.
+func WithAccessLog(logger AccessLogger) Option {
+	return func(s *httpServer) {
+		s.accessLogger = logger
+	}
+}
@@ -0,0 +1,36 @@
+package http_server
+
+import (
+	"net/http"
+	"sync/atomic"
+)
+
+const (
+	healthzPath = "/healthz"
+	readyzPath  = "/readyz"
+)
+
+// probeHandler serves liveness and readiness probes ahead of the runner's handler.
+type probeHandler struct {
+	handler http.Handler
+	ready   atomic.Bool
+}
+
+func (h *probeHandler) SetReady(ready bool) {
+	h.ready.Store(ready)
+}
+
+func (h *probeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case healthzPath:
+		w.Write([]byte("ok"))
+	case readyzPath:
+		if !h.ready.Load() {
+			http.Error(w, "not ready", http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("ok"))
+	default:
+		h.handler.ServeHTTP(w, r)
+	}
+}
@@ -0,0 +1,227 @@
+package http_server
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/netip"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// proxyHeaderTimeout bounds how long a trusted client may take to send its PROXY protocol header.
+const proxyHeaderTimeout = 10 * time.Second
+
+var proxyV2Signature = []byte("\r\n\r\n\x00\r\nQUIT\n")
+
+// ErrInvalidProxyHeader is returned when reading from a connection whose PROXY protocol header is malformed.  The
+// connection is closed.
+var ErrInvalidProxyHeader = errors.New("http_server: invalid PROXY protocol header")
+
+// proxyProtocol reads PROXY protocol headers from connections made by trusted sources.
+type proxyProtocol struct {
+	trusted []netip.Prefix
+}
+
+func (p *proxyProtocol) Wrap(lis net.Listener) net.Listener {
+	return &proxyProtocolListener{Listener: lis, proxyProtocol: p}
+}
+
+func (p *proxyProtocol) trusts(addr net.Addr) bool {
+	tcpAddr, ok := addr.(*net.TCPAddr)
+	if !ok {
+		return false
+	}
+
+	ip, ok := netip.AddrFromSlice(tcpAddr.IP)
+	if !ok {
+		return false
+	}
+	ip = ip.Unmap()
+	for _, prefix := range p.trusted {
+		if prefix.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+type proxyProtocolListener struct {
+	net.Listener
+	*proxyProtocol
+}
+
+// Accept does not read the header itself, so that a slow client does not hold up other connections.  The header is
+// read by the connection's first call to Read or RemoteAddr.
+func (l *proxyProtocolListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	if !l.trusts(conn.RemoteAddr()) {
+		return conn, nil
+	}
+	return &proxyProtocolConn{Conn: conn, reader: bufio.NewReader(conn)}, nil
+}
+
+type proxyProtocolConn struct {
+	net.Conn
+	reader *bufio.Reader
+
+	once       sync.Once
+	remoteAddr net.Addr
+	err        error
+}
+
+func (c *proxyProtocolConn) readHeader() {
+	c.once.Do(func() {
+		c.Conn.SetReadDeadline(time.Now().Add(proxyHeaderTimeout))
+		c.remoteAddr, c.err = readProxyHeader(c.reader)
+		if c.err != nil {
+			c.Conn.Close()
+			return
+		}
+		c.Conn.SetReadDeadline(time.Time{})
+	})
+}
+
+func (c *proxyProtocolConn) Read(b []byte) (int, error) {
+	c.readHeader()
+	if c.err != nil {
+		return 0, c.err
+	}
+	return c.reader.Read(b)
+}
+
+// RemoteAddr returns the client's address as given by the PROXY protocol header, if there was one.
+func (c *proxyProtocolConn) RemoteAddr() net.Addr {
+	c.readHeader()
+	if c.remoteAddr != nil {
+		return c.remoteAddr
+	}
+	return c.Conn.RemoteAddr()
+}
+
+// readProxyHeader consumes a version 1 or version 2 PROXY protocol header, if one is present, and returns the source
+// address it describes.  The address is nil if there was no header, or if the header does not describe a TCP
+// connection.
+func readProxyHeader(r *bufio.Reader) (net.Addr, error) {
+	first, err := r.Peek(1)
+	if err != nil {
+		return nil, nil
+	}
+
+	switch first[0] {
+	case 'P':
+		prefix, err := r.Peek(6)
+		if err != nil || string(prefix) != "PROXY " {
+			return nil, nil
+		}
+		return readProxyV1(r)
+	case '\r':
+		signature, err := r.Peek(len(proxyV2Signature))
+		if err != nil || !bytes.Equal(signature, proxyV2Signature) {
+			return nil, nil
+		}
+		return readProxyV2(r)
+	default:
+		return nil, nil
+	}
+}
+
+// readProxyV1 reads a header of the form "PROXY TCP4 192.0.2.1 192.0.2.2 56324 443\r\n".
+func readProxyV1(r *bufio.Reader) (net.Addr, error) {
+	const maxLength = 107
+
+	var line []byte
+	for len(line) < maxLength {
+		b, err := r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		line = append(line, b)
+		if b == '\n' {
+			break
+		}
+	}
+	if !bytes.HasSuffix(line, []byte("\r\n")) {
+		return nil, ErrInvalidProxyHeader
+	}
+
+	fields := strings.Fields(string(line[:len(line)-2]))
+	if len(fields) >= 2 && fields[1] == "UNKNOWN" {
+		return nil, nil
+	}
+	if len(fields) != 6 || (fields[1] != "TCP4" && fields[1] != "TCP6") {
+		return nil, ErrInvalidProxyHeader
+	}
+
+	ip, err := netip.ParseAddr(fields[2])
+	if err != nil {
+		return nil, ErrInvalidProxyHeader
+	}
+	port, err := strconv.ParseUint(fields[4], 10, 16)
+	if err != nil {
+		return nil, ErrInvalidProxyHeader
+	}
+	return net.TCPAddrFromAddrPort(netip.AddrPortFrom(ip, uint16(port))), nil
+}
+
+// readProxyV2 reads a binary header, as described in section 2.2 of the PROXY protocol specification.
+func readProxyV2(r *bufio.Reader) (net.Addr, error) {
+	header := make([]byte, 16)
+	_, err := io.ReadFull(r, header)
+	if err != nil {
+		return nil, err
+	}
+
+	versionCommand, family := header[12], header[13]
+	length := binary.BigEndian.Uint16(header[14:16])
+	if versionCommand>>4 != 2 {
+		return nil, fmt.Errorf("%w: unsupported version %d", ErrInvalidProxyHeader, versionCommand>>4)
+	}
+
+	body := make([]byte, length)
+	_, err = io.ReadFull(r, body)
+	if err != nil {
+		return nil, err
+	}
+
+	const (
+		commandLocal = 0x0
+		commandProxy = 0x1
+		tcpOverIPv4  = 0x11
+		tcpOverIPv6  = 0x21
+	)
+
+	switch versionCommand & 0xf {
+	case commandLocal:
+		return nil, nil
+	case commandProxy:
+	default:
+		return nil, fmt.Errorf("%w: unsupported command %d", ErrInvalidProxyHeader, versionCommand&0xf)
+	}
+
+	var ipLength int
+	switch family {
+	case tcpOverIPv4:
+		ipLength = 4
+	case tcpOverIPv6:
+		ipLength = 16
+	default:
+		return nil, nil
+	}
+
+	if len(body) < 2*ipLength+4 {
+		return nil, ErrInvalidProxyHeader
+	}
+	ip, _ := netip.AddrFromSlice(body[:ipLength])
+	port := binary.BigEndian.Uint16(body[2*ipLength:])
+	return net.TCPAddrFromAddrPort(netip.AddrPortFrom(ip, port)), nil
+}
@@ -0,0 +1,74 @@
+package http_server
+
+import (
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tedsuo/ifrit"
+)
+
+// An HSTSPolicy describes a Strict-Transport-Security header.
+type HSTSPolicy struct {
+	MaxAge            time.Duration
+	IncludeSubdomains bool
+	Preload           bool
+}
+
+func (p HSTSPolicy) header() string {
+	header := "max-age=" + strconv.Itoa(int(p.MaxAge/time.Second))
+	if p.IncludeSubdomains {
+		header += "; includeSubDomains"
+	}
+	if p.Preload {
+		header += "; preload"
+	}
+	return header
+}
+
+// HSTSHandler adds the policy's Strict-Transport-Security header to every response served by handler.  Browsers
+// only honor the header when it is served over TLS, so handler is usually the one given to NewTLSServer.
+func HSTSHandler(policy HSTSPolicy, handler http.Handler) http.Handler {
+	header := policy.header()
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Strict-Transport-Security", header)
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// RedirectConfig describes the TLS endpoint which a redirect server sends clients to.
+type RedirectConfig struct {
+	TLSPort int         // optional; if zero, clients are sent to the default https port
+	HSTS    *HSTSPolicy // optional; added to every redirect
+}
+
+// RedirectHandler permanently redirects every request to the same host and path over https.
+func RedirectHandler(config RedirectConfig) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		if strings.Contains(host, ":") {
+			host = "[" + host + "]"
+		}
+		if config.TLSPort != 0 && config.TLSPort != 443 {
+			host += ":" + strconv.Itoa(config.TLSPort)
+		}
+
+		if config.HSTS != nil {
+			w.Header().Set("Strict-Transport-Security", config.HSTS.header())
+		}
+
+		target := "https://" + host + r.URL.RequestURI()
+		http.Redirect(w, r, target, http.StatusMovedPermanently)
+	})
+}
+
+// NewRedirectServer returns a runner which listens on address without TLS, and redirects every request to the TLS
+// endpoint described by config.  It is typically grouped with the runner returned by NewTLSServer.
+func NewRedirectServer(address string, config RedirectConfig, opts ...Option) ifrit.Runner {
+	return New(address, RedirectHandler(config), opts...)
+}
@@ -0,0 +1,83 @@
+package http_server_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"syscall"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/tedsuo/ifrit"
+	"github.com/tedsuo/ifrit/http_server"
+)
+
+var _ = Describe("Redirects", func() {
+	redirect := func(config http_server.RedirectConfig, target string) *http.Response {
+		recorder := httptest.NewRecorder()
+		http_server.RedirectHandler(config).ServeHTTP(recorder, httptest.NewRequest("GET", target, nil))
+		return recorder.Result()
+	}
+
+	Describe("RedirectHandler", func() {
+		It("permanently redirects to the same host and path over https", func() {
+			resp := redirect(http_server.RedirectConfig{}, "http://example.com:8080/some/path?q=1")
+			Ω(resp.StatusCode).Should(Equal(http.StatusMovedPermanently))
+			Ω(resp.Header.Get("Location")).Should(Equal("https://example.com/some/path?q=1"))
+			Ω(resp.Header.Get("Strict-Transport-Security")).Should(BeEmpty())
+		})
+
+		It("redirects to the TLS port when it is not the default", func() {
+			resp := redirect(http_server.RedirectConfig{TLSPort: 8443}, "http://[::1]:8080/")
+			Ω(resp.Header.Get("Location")).Should(Equal("https://[::1]:8443/"))
+		})
+
+		It("adds the HSTS header when configured", func() {
+			config := http_server.RedirectConfig{
+				HSTS: &http_server.HSTSPolicy{MaxAge: 365 * 24 * time.Hour, IncludeSubdomains: true, Preload: true},
+			}
+			resp := redirect(config, "http://example.com/")
+			Ω(resp.Header.Get("Strict-Transport-Security")).Should(Equal("max-age=31536000; includeSubDomains; preload"))
+		})
+	})
+
+	Describe("HSTSHandler", func() {
+		It("adds the header to every response", func() {
+			handler := http_server.HSTSHandler(http_server.HSTSPolicy{MaxAge: time.Hour}, http.NotFoundHandler())
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, httptest.NewRequest("GET", "https://example.com/", nil))
+			Ω(recorder.Code).Should(Equal(http.StatusNotFound))
+			Ω(recorder.Header().Get("Strict-Transport-Security")).Should(Equal("max-age=3600"))
+		})
+	})
+
+	Describe("NewRedirectServer", func() {
+		var (
+			address string
+			process ifrit.Process
+		)
+
+		BeforeEach(func() {
+			address = fmt.Sprintf("127.0.0.1:%d", 8200+GinkgoParallelNode())
+			process = ifrit.Invoke(http_server.NewRedirectServer(address, http_server.RedirectConfig{TLSPort: 8443}))
+		})
+
+		AfterEach(func() {
+			process.Signal(syscall.SIGINT)
+			Eventually(process.Wait()).Should(Receive())
+		})
+
+		It("serves redirects", func() {
+			client := http.Client{
+				CheckRedirect: func(*http.Request, []*http.Request) error {
+					return http.ErrUseLastResponse
+				},
+			}
+			resp, err := client.Get("http://" + address + "/path")
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(resp.StatusCode).Should(Equal(http.StatusMovedPermanently))
+			Ω(resp.Header.Get("Location")).Should(Equal("https://127.0.0.1:8443/path"))
+		})
+	})
+})
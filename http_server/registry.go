@@ -0,0 +1,126 @@
+package http_server
+
+import (
+	"io"
+	"sync"
+	"time"
+)
+
+// A ConnectionRegistry tracks long-lived connections which the server no longer tracks itself, such as WebSockets
+// and other hijacked connections, so that the runner can shut them down.  Handlers register each connection for as
+// long as they serve it; see WithConnectionRegistry.
+//
+// A registry drains each time its runner stops, and should be given to a single runner.
+type ConnectionRegistry struct {
+	mu       sync.Mutex
+	conns    map[*registeredConn]struct{}
+	draining chan struct{}
+	changed  chan struct{}
+}
+
+type registeredConn struct {
+	conn   io.Closer
+	notify func()
+}
+
+func NewConnectionRegistry() *ConnectionRegistry {
+	return &ConnectionRegistry{
+		conns:    map[*registeredConn]struct{}{},
+		draining: make(chan struct{}),
+		changed:  make(chan struct{}, 1),
+	}
+}
+
+// Register tracks conn until the returned function is called, which the handler must do once it stops serving conn.
+// When the runner begins shutting down, notify, if non-nil, is called in a goroutine of its own so that the handler
+// can ask its client to go away, for example with a WebSocket close frame.  conn is closed if it is still registered
+// once the runner's grace period elapses.  A connection registered while the runner is shutting down is notified
+// immediately.
+func (r *ConnectionRegistry) Register(conn io.Closer, notify func()) (deregister func()) {
+	c := &registeredConn{conn: conn, notify: notify}
+
+	r.mu.Lock()
+	r.conns[c] = struct{}{}
+	draining := r.isDraining()
+	r.mu.Unlock()
+
+	if draining && notify != nil {
+		go notify()
+	}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			r.mu.Lock()
+			delete(r.conns, c)
+			r.mu.Unlock()
+
+			select {
+			case r.changed <- struct{}{}:
+			default:
+			}
+		})
+	}
+}
+
+// Draining is closed when the runner begins shutting down.  Handlers which stream responses, such as server-sent
+// events, may select on it to end their streams.  A runner which is run again starts with a new channel.
+func (r *ConnectionRegistry) Draining() <-chan struct{} {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.draining
+}
+
+// Len returns the number of registered connections.
+func (r *ConnectionRegistry) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.conns)
+}
+
+func (r *ConnectionRegistry) isDraining() bool {
+	select {
+	case <-r.draining:
+		return true
+	default:
+		return false
+	}
+}
+
+// reset readies a registry which has drained to be drained again, when its runner is run again.
+func (r *ConnectionRegistry) reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.isDraining() {
+		r.draining = make(chan struct{})
+	}
+}
+
+// drain notifies every registered connection, and waits up to gracePeriod for them to be deregistered before
+// closing the rest.
+func (r *ConnectionRegistry) drain(gracePeriod time.Duration) {
+	r.mu.Lock()
+	close(r.draining)
+	for c := range r.conns {
+		if c.notify != nil {
+			go c.notify()
+		}
+	}
+	r.mu.Unlock()
+
+	timer := time.NewTimer(gracePeriod)
+	defer timer.Stop()
+
+	for r.Len() > 0 {
+		select {
+		case <-r.changed:
+		case <-timer.C:
+			r.mu.Lock()
+			for c := range r.conns {
+				c.conn.Close()
+			}
+			r.mu.Unlock()
+			return
+		}
+	}
+}
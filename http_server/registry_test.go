@@ -0,0 +1,127 @@
+package http_server_test
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/tedsuo/ifrit"
+	"github.com/tedsuo/ifrit/http_server"
+)
+
+var _ = Describe("ConnectionRegistry", func() {
+	var (
+		address  string
+		registry *http_server.ConnectionRegistry
+		runner   ifrit.Runner
+		process  ifrit.Process
+	)
+
+	// the handler hijacks each connection, and asks the client to go away when the runner shuts down
+	handler := func(registry *http_server.ConnectionRegistry) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+
+			deregister := registry.Register(conn, func() {
+				conn.Write([]byte("goodbye\n"))
+			})
+			defer deregister()
+
+			conn.Write([]byte("hello\n"))
+			io.Copy(io.Discard, conn)
+		})
+	}
+
+	connect := func() (net.Conn, *bufio.Reader) {
+		conn, err := net.Dial("tcp", address)
+		Ω(err).ShouldNot(HaveOccurred())
+		_, err = conn.Write([]byte("GET / HTTP/1.1\r\nHost: example.com\r\n\r\n"))
+		Ω(err).ShouldNot(HaveOccurred())
+
+		reader := bufio.NewReader(conn)
+		line, err := reader.ReadString('\n')
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(line).Should(Equal("hello\n"))
+		return conn, reader
+	}
+
+	BeforeEach(func() {
+		address = fmt.Sprintf("127.0.0.1:%d", 8600+GinkgoParallelNode())
+		registry = http_server.NewConnectionRegistry()
+		runner = http_server.New(address, handler(registry), http_server.WithConnectionRegistry(registry, 200*time.Millisecond))
+		process = ifrit.Invoke(runner)
+	})
+
+	AfterEach(func() {
+		process.Signal(syscall.SIGKILL)
+		Eventually(process.Wait()).Should(Receive())
+	})
+
+	It("tracks registered connections", func() {
+		conn, _ := connect()
+		Eventually(registry.Len).Should(Equal(1))
+
+		conn.Close()
+		Eventually(registry.Len).Should(Equal(0))
+	})
+
+	It("notifies registered connections when the runner shuts down, and waits for them", func() {
+		conn, reader := connect()
+		defer conn.Close()
+		Eventually(registry.Len).Should(Equal(1))
+
+		process.Signal(syscall.SIGINT)
+		Eventually(registry.Draining()).Should(BeClosed())
+
+		line, err := reader.ReadString('\n')
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(line).Should(Equal("goodbye\n"))
+		Consistently(process.Wait(), 100*time.Millisecond).ShouldNot(Receive())
+
+		conn.Close()
+		Eventually(process.Wait()).Should(Receive(BeNil()))
+	})
+
+	It("closes connections which are still registered once the grace period elapses", func() {
+		conn, reader := connect()
+		defer conn.Close()
+		Eventually(registry.Len).Should(Equal(1))
+
+		process.Signal(syscall.SIGINT)
+		_, err := reader.ReadString('\n')
+		Ω(err).ShouldNot(HaveOccurred())
+
+		_, err = reader.ReadString('\n')
+		Ω(err).Should(Equal(io.EOF))
+		Eventually(process.Wait()).Should(Receive(BeNil()))
+	})
+
+	It("drains again when the runner is run again", func() {
+		process.Signal(syscall.SIGINT)
+		Eventually(process.Wait()).Should(Receive(BeNil()))
+
+		process = ifrit.Invoke(runner)
+		conn, reader := connect()
+		defer conn.Close()
+		Eventually(registry.Len).Should(Equal(1))
+
+		process.Signal(syscall.SIGINT)
+		line, err := reader.ReadString('\n')
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(line).Should(Equal("goodbye\n"))
+
+		_, err = reader.ReadString('\n')
+		Ω(err).Should(Equal(io.EOF))
+		Eventually(process.Wait()).Should(Receive(BeNil()))
+	})
+})
@@ -0,0 +1,68 @@
+/*
+Package signalqueue forwards signals to a Runner in the order they were sent,
+without blocking the sender on a Runner which is slow to receive them.
+*/
+package signalqueue
+
+import (
+	"os"
+	"sync"
+)
+
+/*
+A Queue holds the signals sent to it until they can be delivered.  A single
+goroutine delivers them, in order, until done is closed.
+*/
+type Queue struct {
+	lock    sync.Mutex
+	pending []os.Signal
+	wake    chan struct{}
+}
+
+/*
+Forward returns a Queue which delivers the signals sent to it on out, until done
+is closed.
+*/
+func Forward(out chan<- os.Signal, done <-chan struct{}) *Queue {
+	q := &Queue{wake: make(chan struct{}, 1)}
+	go q.deliver(out, done)
+	return q
+}
+
+/*
+Send queues signal for delivery, and returns immediately.
+*/
+func (q *Queue) Send(signal os.Signal) {
+	q.lock.Lock()
+	q.pending = append(q.pending, signal)
+	q.lock.Unlock()
+
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (q *Queue) deliver(out chan<- os.Signal, done <-chan struct{}) {
+	for {
+		q.lock.Lock()
+		if len(q.pending) == 0 {
+			q.lock.Unlock()
+			select {
+			case <-q.wake:
+				continue
+			case <-done:
+				return
+			}
+		}
+		signal := q.pending[0]
+		q.pending = q.pending[1:]
+		q.lock.Unlock()
+
+		select {
+		case out <- signal:
+		case <-done:
+			return
+		}
+	}
+}
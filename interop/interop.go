@@ -0,0 +1,97 @@
+/*
+The interop package adapts Runners to and from the concurrency primitives of
+golang.org/x/sync/errgroup and github.com/oklog/run, so that code using them can
+be mixed with ifrit, and migrated incrementally.  Neither library is imported;
+the adapters are expressed in terms of the function types they accept.
+
+	g, ctx := errgroup.WithContext(ctx)
+	g.Go(interop.Task(ctx, runner, os.Interrupt, nil))
+
+	var group run.Group
+	group.Add(interop.Actor(runner, os.Interrupt, nil))
+*/
+package interop
+
+import (
+	"context"
+	"os"
+
+	"github.com/tedsuo/ifrit"
+)
+
+/*
+Task returns a function for errgroup.Group.Go which runs r until ctx is done,
+then sends it signal and waits for it to exit.  If ready is non-nil, it is
+closed once r is ready.
+*/
+func Task(ctx context.Context, r ifrit.Runner, signal os.Signal, ready chan<- struct{}) func() error {
+	if ready == nil {
+		ready = make(chan struct{})
+	}
+	return func() error {
+		return ifrit.ToRunnerContext(r, signal).Run(ctx, ready)
+	}
+}
+
+/*
+FromTask returns a Runner which runs task, such as one given to errgroup.Group.Go,
+with a context which is cancelled when the Runner is signaled.  The Runner is
+ready as soon as task starts.
+*/
+func FromTask(task func(ctx context.Context) error) ifrit.Runner {
+	return ifrit.FromRunnerContext(ifrit.RunContextFunc(func(ctx context.Context, ready chan<- struct{}) error {
+		close(ready)
+		return task(ctx)
+	}))
+}
+
+/*
+Actor returns an execute and interrupt function pair for run.Group.Add which runs
+r, and sends it signal when the actor is interrupted.  If ready is non-nil, it is
+closed once r is ready.
+*/
+func Actor(r ifrit.Runner, signal os.Signal, ready chan<- struct{}) (execute func() error, interrupt func(error)) {
+	if ready == nil {
+		ready = make(chan struct{})
+	}
+	signals := make(chan os.Signal, 1)
+
+	execute = func() error {
+		return r.Run(signals, ready)
+	}
+	interrupt = func(error) {
+		select {
+		case signals <- signal:
+		default:
+		}
+	}
+	return execute, interrupt
+}
+
+/*
+FromActor returns a Runner which runs an actor, such as one given to
+run.Group.Add.  The Runner is ready as soon as execute starts.  When the Runner is
+signaled, interrupt is called with an ifrit.SignalCause.
+*/
+func FromActor(execute func() error, interrupt func(error)) ifrit.Runner {
+	return ifrit.RunFunc(func(signals <-chan os.Signal, ready chan<- struct{}) error {
+		errs := make(chan error, 1)
+		go func() {
+			errs <- execute()
+		}()
+		close(ready)
+
+		interrupted := false
+		for {
+			select {
+			case err := <-errs:
+				return err
+			case signal := <-signals:
+				if !interrupted {
+					interrupted = true
+					interrupt(ifrit.SignalCause{Signal: signal})
+				}
+			}
+		}
+	})
+}
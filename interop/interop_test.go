@@ -0,0 +1,93 @@
+package interop_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"syscall"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/tedsuo/ifrit"
+	"github.com/tedsuo/ifrit/interop"
+	"github.com/tedsuo/ifrit/test_helpers"
+	"golang.org/x/sync/errgroup"
+)
+
+var _ = Describe("Interop", func() {
+	Describe("Task", func() {
+		It("runs the runner in an errgroup until the group's context is done", func() {
+			recorder := test_helpers.NewSignalRecorder()
+			ready := make(chan struct{})
+			failure := errors.New("failed")
+
+			g, ctx := errgroup.WithContext(context.Background())
+			g.Go(interop.Task(ctx, recorder, os.Interrupt, ready))
+			Eventually(ready).Should(BeClosed())
+
+			g.Go(func() error { return failure })
+			Ω(g.Wait()).Should(Equal(failure))
+			Ω(recorder.ReceivedSignals()).Should(Equal([]os.Signal{os.Interrupt}))
+		})
+	})
+
+	Describe("FromTask", func() {
+		It("cancels the task's context when signaled", func() {
+			process := ifrit.Invoke(interop.FromTask(func(ctx context.Context) error {
+				<-ctx.Done()
+				return context.Cause(ctx)
+			}))
+			Ω(process.Ready()).Should(BeClosed())
+
+			process.Signal(syscall.SIGTERM)
+			Eventually(process.Wait()).Should(Receive(Equal(ifrit.SignalCause{Signal: syscall.SIGTERM})))
+		})
+	})
+
+	Describe("Actor", func() {
+		It("runs the runner until the actor is interrupted", func() {
+			recorder := test_helpers.NewSignalRecorder()
+			ready := make(chan struct{})
+			execute, interrupt := interop.Actor(recorder, os.Interrupt, ready)
+
+			errs := make(chan error, 1)
+			go func() {
+				errs <- execute()
+			}()
+			Eventually(ready).Should(BeClosed())
+			Consistently(errs).ShouldNot(Receive())
+
+			interrupt(errors.New("another actor exited"))
+			Eventually(errs).Should(Receive(BeNil()))
+			Ω(recorder.ReceivedSignals()).Should(Equal([]os.Signal{os.Interrupt}))
+		})
+
+		It("stops a runner interrupted before it executes", func() {
+			recorder := test_helpers.NewSignalRecorder()
+			execute, interrupt := interop.Actor(recorder, os.Interrupt, nil)
+
+			interrupt(nil)
+			Ω(execute()).Should(Succeed())
+		})
+	})
+
+	Describe("FromActor", func() {
+		It("interrupts the actor when signaled", func() {
+			interrupts := make(chan error, 1)
+			stop := make(chan struct{})
+			runner := interop.FromActor(func() error {
+				<-stop
+				return nil
+			}, func(err error) {
+				interrupts <- err
+				close(stop)
+			})
+
+			process := ifrit.Invoke(runner)
+			process.Signal(os.Interrupt)
+			process.Signal(os.Interrupt)
+			Eventually(process.Wait()).Should(Receive(BeNil()))
+			Ω(interrupts).Should(Receive(Equal(ifrit.SignalCause{Signal: os.Interrupt})))
+		})
+	})
+})
@@ -0,0 +1,45 @@
+package ifrit
+
+import (
+	"log/slog"
+	"os"
+	"time"
+)
+
+/*
+WithLogger returns a Runner which runs r, logging when it starts, becomes
+ready, is signaled, and exits, with the error it exited with and how long it
+ran for.  Exits with an error are logged at slog.LevelError, and everything
+else at slog.LevelInfo.  If r was returned by Named, every record has a
+"runner" attribute with its name.
+*/
+func WithLogger(r Runner, logger *slog.Logger) Runner {
+	if named, ok := r.(interface{ Name() string }); ok {
+		logger = logger.With(slog.String("runner", named.Name()))
+	}
+
+	return RunFunc(func(signals <-chan os.Signal, ready chan<- struct{}) error {
+		var startedAt time.Time
+
+		return WithHooks(r, Hooks{
+			BeforeRun: func() {
+				startedAt = time.Now()
+				logger.Info("runner starting")
+			},
+			OnReady: func() {
+				logger.Info("runner ready", slog.Duration("startup", time.Since(startedAt)))
+			},
+			OnSignal: func(signal os.Signal) {
+				logger.Info("runner signaled", slog.String("signal", signal.String()))
+			},
+			OnExit: func(err error) {
+				duration := slog.Duration("duration", time.Since(startedAt))
+				if err != nil {
+					logger.Error("runner exited", duration, slog.String("error", err.Error()))
+				} else {
+					logger.Info("runner exited", duration)
+				}
+			},
+		}).Run(signals, ready)
+	})
+}
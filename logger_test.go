@@ -0,0 +1,96 @@
+package ifrit_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"sync"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/tedsuo/ifrit"
+	"github.com/tedsuo/ifrit/test_helpers"
+)
+
+type lockedBuffer struct {
+	lock   sync.Mutex
+	buffer bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.buffer.Write(p)
+}
+
+func (b *lockedBuffer) Records() []map[string]interface{} {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	var records []map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(b.buffer.Bytes()))
+	for decoder.More() {
+		var record map[string]interface{}
+		Ω(decoder.Decode(&record)).Should(Succeed())
+		records = append(records, record)
+	}
+	return records
+}
+
+var _ = Describe("WithLogger", func() {
+	var output *lockedBuffer
+	var logger *slog.Logger
+
+	BeforeEach(func() {
+		output = &lockedBuffer{}
+		logger = slog.New(slog.NewJSONHandler(output, nil))
+	})
+
+	messages := func() []string {
+		var messages []string
+		for _, record := range output.Records() {
+			messages = append(messages, record["level"].(string)+" "+record["msg"].(string))
+		}
+		return messages
+	}
+
+	It("logs each stage of the lifecycle", func() {
+		process := ifrit.Invoke(ifrit.WithLogger(make(test_helpers.PingChan), logger))
+		process.Signal(os.Kill)
+		Eventually(process.Wait()).Should(Receive())
+
+		Ω(messages()).Should(Equal([]string{
+			"INFO runner starting",
+			"INFO runner ready",
+			"INFO runner signaled",
+			"ERROR runner exited",
+		}))
+
+		records := output.Records()
+		Ω(records[0]).ShouldNot(HaveKey("runner"))
+		Ω(records[1]).Should(HaveKey("startup"))
+		Ω(records[2]).Should(HaveKeyWithValue("signal", "killed"))
+		Ω(records[3]).Should(HaveKey("duration"))
+		Ω(records[3]).Should(HaveKeyWithValue("error", test_helpers.PingerExitedFromSignal.Error()))
+	})
+
+	It("includes the name of a named runner", func() {
+		process := ifrit.Invoke(ifrit.WithLogger(ifrit.Named("web", test_helpers.NoReadyRunner), logger))
+		Eventually(process.Wait()).Should(Receive())
+
+		Ω(messages()).Should(Equal([]string{"INFO runner starting", "ERROR runner exited"}))
+		for _, record := range output.Records() {
+			Ω(record).Should(HaveKeyWithValue("runner", "web"))
+		}
+	})
+
+	It("logs a clean exit at info", func() {
+		process := ifrit.Invoke(ifrit.WithLogger(test_helpers.NewSignalRecorder(), logger))
+		process.Signal(os.Interrupt)
+		Eventually(process.Wait()).Should(Receive(BeNil()))
+
+		Ω(messages()).Should(ContainElement("INFO runner exited"))
+		Ω(output.Records()[len(output.Records())-1]).ShouldNot(HaveKey("error"))
+	})
+})
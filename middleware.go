@@ -0,0 +1,28 @@
+package ifrit
+
+/*
+A RunnerMiddleware wraps a Runner with additional behaviour, such as
+RecoverPanics.
+*/
+type RunnerMiddleware func(Runner) Runner
+
+/*
+Chain returns a RunnerMiddleware which applies middlewares in the order given:
+the first middleware is the outermost, and sees signals first and the exit
+error last.  An empty Chain returns the Runner unchanged.
+*/
+func Chain(middlewares ...RunnerMiddleware) RunnerMiddleware {
+	return func(r Runner) Runner {
+		for i := len(middlewares) - 1; i >= 0; i-- {
+			r = middlewares[i](r)
+		}
+		return r
+	}
+}
+
+/*
+Then returns r wrapped in m.
+*/
+func (m RunnerMiddleware) Then(r Runner) Runner {
+	return m(r)
+}
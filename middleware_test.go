@@ -0,0 +1,64 @@
+package ifrit_test
+
+import (
+	"errors"
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/tedsuo/ifrit"
+)
+
+var _ = Describe("Chain", func() {
+	var calls []string
+
+	record := func(name string) ifrit.RunnerMiddleware {
+		return func(r ifrit.Runner) ifrit.Runner {
+			return ifrit.RunFunc(func(signals <-chan os.Signal, ready chan<- struct{}) error {
+				calls = append(calls, "enter "+name)
+				err := r.Run(signals, ready)
+				calls = append(calls, "exit "+name)
+				return err
+			})
+		}
+	}
+
+	runner := ifrit.RunFunc(func(signals <-chan os.Signal, ready chan<- struct{}) error {
+		calls = append(calls, "run")
+		close(ready)
+		return errors.New("done")
+	})
+
+	BeforeEach(func() {
+		calls = nil
+	})
+
+	It("applies the middlewares with the first outermost", func() {
+		chained := ifrit.Chain(record("a"), record("b"), record("c")).Then(runner)
+
+		Ω(<-ifrit.Invoke(chained).Wait()).Should(MatchError("done"))
+		Ω(calls).Should(Equal([]string{"enter a", "enter b", "enter c", "run", "exit c", "exit b", "exit a"}))
+	})
+
+	It("returns the runner unchanged when empty", func() {
+		Ω(<-ifrit.Invoke(ifrit.Chain().Then(runner)).Wait()).Should(MatchError("done"))
+		Ω(calls).Should(Equal([]string{"run"}))
+	})
+
+	It("composes with the built-in wrappers", func() {
+		panicky := ifrit.RunFunc(func(signals <-chan os.Signal, ready chan<- struct{}) error {
+			panic("boom")
+		})
+		chained := ifrit.Chain(
+			func(r ifrit.Runner) ifrit.Runner { return ifrit.Named("worker", r) },
+			ifrit.RecoverPanics,
+		).Then(panicky)
+
+		err := <-ifrit.Background(chained).Wait()
+		Ω(errors.Is(err, &ifrit.ProcessError{Name: "worker"})).Should(BeTrue())
+
+		var panicErr ifrit.PanicError
+		Ω(errors.As(err, &panicErr)).Should(BeTrue())
+		Ω(panicErr.Value).Should(Equal("boom"))
+	})
+})
@@ -0,0 +1,60 @@
+package ifrit
+
+import (
+	"fmt"
+	"os"
+)
+
+/*
+A ProcessError is returned by a Runner returned by Named when the Runner it
+wraps exits with an error.  Name identifies the Runner, and Err is the error it
+returned.
+
+errors.Is reports whether an error is a ProcessError for a given name when the
+target is a ProcessError with that Name and no Err, e.g.
+
+	errors.Is(err, &ifrit.ProcessError{Name: "database"})
+*/
+type ProcessError struct {
+	Name string
+	Err  error
+}
+
+func (e *ProcessError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Name, e.Err)
+}
+
+func (e *ProcessError) Unwrap() error {
+	return e.Err
+}
+
+func (e *ProcessError) Is(target error) bool {
+	t, ok := target.(*ProcessError)
+	return ok && t.Err == nil && t.Name == e.Name
+}
+
+/*
+Named returns a Runner which runs r, and wraps any error it exits with in a
+ProcessError carrying name.  A nil error is returned as-is.  The name is also
+used by wrappers such as WithLogger.
+*/
+func Named(name string, r Runner) Runner {
+	return namedRunner{name: name, runner: r}
+}
+
+type namedRunner struct {
+	name   string
+	runner Runner
+}
+
+func (r namedRunner) Name() string {
+	return r.name
+}
+
+func (r namedRunner) Run(signals <-chan os.Signal, ready chan<- struct{}) error {
+	err := r.runner.Run(signals, ready)
+	if err != nil {
+		return &ProcessError{Name: r.name, Err: err}
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package ifrit_test
+
+import (
+	"errors"
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/tedsuo/ifrit"
+)
+
+var _ = Describe("Named", func() {
+	var exitErr error
+
+	runner := func() ifrit.Runner {
+		return ifrit.RunFunc(func(signals <-chan os.Signal, ready chan<- struct{}) error {
+			close(ready)
+			<-signals
+			return exitErr
+		})
+	}
+
+	BeforeEach(func() {
+		exitErr = errors.New("connection refused")
+	})
+
+	It("wraps the runner's error in a ProcessError", func() {
+		process := ifrit.Invoke(ifrit.Named("database", runner()))
+		process.Signal(os.Interrupt)
+
+		var err error
+		Eventually(process.Wait()).Should(Receive(&err))
+		Ω(err).Should(MatchError("database: connection refused"))
+
+		var processErr *ifrit.ProcessError
+		Ω(errors.As(err, &processErr)).Should(BeTrue())
+		Ω(processErr.Name).Should(Equal("database"))
+		Ω(errors.Is(err, exitErr)).Should(BeTrue())
+	})
+
+	It("matches a ProcessError with the same name and no error", func() {
+		process := ifrit.Invoke(ifrit.Named("database", runner()))
+		process.Signal(os.Interrupt)
+
+		var err error
+		Eventually(process.Wait()).Should(Receive(&err))
+		Ω(errors.Is(err, &ifrit.ProcessError{Name: "database"})).Should(BeTrue())
+		Ω(errors.Is(err, &ifrit.ProcessError{Name: "cache"})).Should(BeFalse())
+	})
+
+	It("returns nil when the runner exits cleanly", func() {
+		exitErr = nil
+		process := ifrit.Invoke(ifrit.Named("database", runner()))
+		process.Signal(os.Interrupt)
+
+		Eventually(process.Wait()).Should(Receive(BeNil()))
+	})
+})
@@ -1,6 +1,14 @@
 package ifrit
 
-import "os"
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
 
 /*
 A Process represents a Runner that has been started.  It is safe to call any
@@ -13,10 +21,94 @@ type Process interface {
 	// Wait returns a channel that will emit a single error once the Process exits.
 	Wait() <-chan error
 
-	// Signal sends a shutdown signal to the Process.  It does not block.
+	// Signal sends a shutdown signal to the Process.  It does not block.  A
+	// signal which is already waiting to be received by the Runner is not sent
+	// again, so signaling a Process repeatedly before it responds has the same
+	// effect as signaling it once.  Signals sent to an exited Process are
+	// dropped.
 	Signal(os.Signal)
 }
 
+/*
+A ProcessStatus is a Process which reports on its lifetime.  The Processes
+returned by Invoke and Background are ProcessStatuses.
+*/
+type ProcessStatus interface {
+	Process
+
+	// StartedAt returns the time the Runner started running.
+	StartedAt() time.Time
+
+	// Uptime returns how long the Runner has been running, or, once the Process
+	// has exited, how long it ran for.
+	Uptime() time.Duration
+
+	// ExitError returns the error the Runner exited with, and whether it has
+	// exited.
+	ExitError() (err error, exited bool)
+
+	// State returns the state of the Process, and once it has exited, the error
+	// the Runner exited with.
+	State() (ProcessState, error)
+
+	// Done returns a channel which will close once the Runner has exited.  Once
+	// it is closed, ExitError reports the error the Runner exited with.
+	Done() <-chan struct{}
+}
+
+/*
+A ProcessState describes how far through its lifecycle a Process is.
+*/
+type ProcessState int
+
+const (
+	// StatePending means the Runner has not started running yet.
+	StatePending ProcessState = iota
+	// StateRunning means the Runner is running, but is not ready yet.
+	StateRunning
+	// StateReady means the Runner is ready, and has not been asked to stop.
+	StateReady
+	// StateStopping means the Process has been sent one of StopSignals, or
+	// stopped by a Stopper, and has not exited yet.
+	StateStopping
+	// StateExited means the Runner has returned.
+	StateExited
+)
+
+/*
+StopSignals are the signals which ask a Process to stop.  A Process reports
+StateStopping once it has been sent one of them; other signals, such as a
+signal to reload configuration, leave its state as it was.  A Stopper stops a
+Process whichever signal it sends.
+*/
+var StopSignals = []os.Signal{os.Interrupt, os.Kill, syscall.SIGTERM, syscall.SIGQUIT}
+
+func isStopSignal(signal os.Signal) bool {
+	for _, stopSignal := range StopSignals {
+		if signal == stopSignal {
+			return true
+		}
+	}
+	return false
+}
+
+func (s ProcessState) String() string {
+	switch s {
+	case StatePending:
+		return "pending"
+	case StateRunning:
+		return "running"
+	case StateReady:
+		return "ready"
+	case StateStopping:
+		return "stopping"
+	case StateExited:
+		return "exited"
+	default:
+		return fmt.Sprintf("ProcessState(%d)", int(s))
+	}
+}
+
 /*
 Invoke executes a Runner and returns a Process once the Runner is ready.  Waiting
 for ready allows program initializtion to be scripted in a procedural manner.
@@ -34,6 +126,67 @@ func Invoke(r Runner) Process {
 	return p
 }
 
+/*
+A ReadyTimeoutError is returned by InvokeWithTimeout when a Runner does not
+become ready in time.  Err is the error the Runner exited with once it was
+killed, or nil if it did not exit in time either.
+*/
+type ReadyTimeoutError struct {
+	Timeout time.Duration
+	Err     error
+}
+
+func (e ReadyTimeoutError) Error() string {
+	return fmt.Sprintf("runner was not ready within %s", e.Timeout)
+}
+
+func (e ReadyTimeoutError) Unwrap() error {
+	return e.Err
+}
+
+/*
+InvokeWithTimeout is like Invoke, but gives up on a Runner which does not become
+ready within timeout.  The Runner is sent os.Kill, and the Process is returned
+with a ReadyTimeoutError once it has exited, or after waiting as long again for
+it to do so, in which case the Process is still running.
+*/
+func InvokeWithTimeout(r Runner, timeout time.Duration) (Process, error) {
+	p := Background(r)
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-p.Ready():
+		return p, nil
+	case <-p.Wait():
+		return p, nil
+	case <-timer.C:
+	}
+
+	p.Signal(os.Kill)
+	timer.Reset(timeout)
+	select {
+	case err := <-p.Wait():
+		return p, ReadyTimeoutError{Timeout: timeout, Err: err}
+	case <-timer.C:
+		return p, ReadyTimeoutError{Timeout: timeout}
+	}
+}
+
+/*
+WaitContext waits for p to exit, and returns the error it exited with.  If ctx
+is done first, WaitContext returns the context's error, and p is left running.
+*/
+func WaitContext(ctx context.Context, p Process) error {
+	select {
+	case err := <-p.Wait():
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 /*
 Envoke is deprecated in favor of Invoke, on account of it not being a real word.
 */
@@ -46,16 +199,39 @@ Background executes a Runner and returns a Process immediately, without waiting.
 */
 func Background(r Runner) Process {
 	p := newProcess(r)
+	p.startedAt = time.Now()
 	go p.run()
 	return p
 }
 
+/*
+BackgroundWithCallback is like Background, but calls callback with the error the
+Runner exits with, so that failures of processes nobody waits on can still be
+reported.  callback is called on its own goroutine.
+*/
+func BackgroundWithCallback(r Runner, callback func(err error)) Process {
+	p := newProcess(r)
+	p.startedAt = time.Now()
+	go func() {
+		p.run()
+		callback(p.exitStatus)
+	}()
+	return p
+}
+
 type process struct {
 	runner     Runner
 	signals    chan os.Signal
 	ready      chan struct{}
 	exited     chan struct{}
 	exitStatus error
+	startedAt  time.Time
+	exitedAt   time.Time
+	running    atomic.Bool
+	stopping   atomic.Bool
+
+	pendingLock sync.Mutex
+	pending     map[os.Signal]bool
 }
 
 func newProcess(runner Runner) *process {
@@ -64,11 +240,14 @@ func newProcess(runner Runner) *process {
 		signals: make(chan os.Signal),
 		ready:   make(chan struct{}),
 		exited:  make(chan struct{}),
+		pending: map[os.Signal]bool{},
 	}
 }
 
 func (p *process) run() {
+	p.running.Store(true)
 	p.exitStatus = p.runner.Run(p.signals, p.ready)
+	p.exitedAt = time.Now()
 	close(p.exited)
 }
 
@@ -88,10 +267,86 @@ func (p *process) Wait() <-chan error {
 }
 
 func (p *process) Signal(signal os.Signal) {
+	if isStopSignal(signal) {
+		p.stopping.Store(true)
+	}
+	p.send(signal)
+}
+
+/*
+stop sends signal to the Process, and reports it as stopping, whether or not
+signal is one of StopSignals.
+*/
+func (p *process) stop(signal os.Signal) {
+	p.stopping.Store(true)
+	p.send(signal)
+}
+
+func (p *process) send(signal os.Signal) {
+	p.pendingLock.Lock()
+	defer p.pendingLock.Unlock()
+	if p.pending[signal] {
+		return
+	}
+	p.pending[signal] = true
+
 	go func() {
 		select {
 		case p.signals <- signal:
 		case <-p.exited:
 		}
+
+		p.pendingLock.Lock()
+		delete(p.pending, signal)
+		p.pendingLock.Unlock()
 	}()
 }
+
+func (p *process) StartedAt() time.Time {
+	return p.startedAt
+}
+
+func (p *process) Uptime() time.Duration {
+	select {
+	case <-p.exited:
+		return p.exitedAt.Sub(p.startedAt)
+	default:
+		return time.Since(p.startedAt)
+	}
+}
+
+func (p *process) Done() <-chan struct{} {
+	return p.exited
+}
+
+func (p *process) ExitError() (error, bool) {
+	select {
+	case <-p.exited:
+		return p.exitStatus, true
+	default:
+		return nil, false
+	}
+}
+
+func (p *process) State() (ProcessState, error) {
+	select {
+	case <-p.exited:
+		return StateExited, p.exitStatus
+	default:
+	}
+
+	if p.stopping.Load() {
+		return StateStopping, nil
+	}
+
+	select {
+	case <-p.ready:
+		return StateReady, nil
+	default:
+	}
+
+	if p.running.Load() {
+		return StateRunning, nil
+	}
+	return StatePending, nil
+}
@@ -1,7 +1,11 @@
 package ifrit_test
 
 import (
+	"context"
+	"errors"
 	"os"
+	"syscall"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -71,4 +75,253 @@ var _ = Describe("Process", func() {
 			Ω(<-proc.Wait()).Should(Equal(test_helpers.NoReadyExitedNormally))
 		})
 	})
+
+	Context("when a process reports its status", func() {
+		var pinger test_helpers.PingChan
+		var status ifrit.ProcessStatus
+		var before time.Time
+
+		BeforeEach(func() {
+			pinger = make(test_helpers.PingChan)
+			before = time.Now()
+			status = ifrit.Invoke(pinger).(ifrit.ProcessStatus)
+		})
+
+		It("reports when it started, and that it is still running", func() {
+			Ω(status.StartedAt()).Should(BeTemporally(">=", before))
+			Ω(status.StartedAt()).Should(BeTemporally("<=", time.Now()))
+
+			_, exited := status.ExitError()
+			Ω(exited).Should(BeFalse())
+			Eventually(status.Uptime).Should(BeNumerically(">", 10*time.Millisecond))
+
+			status.Signal(os.Kill)
+			Eventually(status.Wait()).Should(Receive())
+		})
+
+		It("reports its exit error and fixed uptime once it has exited", func() {
+			<-pinger
+			Eventually(status.Wait()).Should(Receive())
+
+			err, exited := status.ExitError()
+			Ω(exited).Should(BeTrue())
+			Ω(err).Should(Equal(test_helpers.PingerExitedFromPing))
+
+			uptime := status.Uptime()
+			Consistently(status.Uptime).Should(Equal(uptime))
+		})
+	})
+
+	Describe("InvokeWithTimeout", func() {
+		It("returns the process once it is ready", func() {
+			pinger := make(test_helpers.PingChan)
+			proc, err := ifrit.InvokeWithTimeout(pinger, time.Second)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(proc.Ready()).Should(BeClosed())
+
+			proc.Signal(os.Kill)
+			Eventually(proc.Wait()).Should(Receive())
+		})
+
+		It("returns the process when it exits without becoming ready", func() {
+			proc, err := ifrit.InvokeWithTimeout(test_helpers.NoReadyRunner, time.Second)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(<-proc.Wait()).Should(Equal(test_helpers.NoReadyExitedNormally))
+		})
+
+		It("kills the runner if it is not ready in time", func() {
+			neverReady := ifrit.RunFunc(func(signals <-chan os.Signal, ready chan<- struct{}) error {
+				<-signals
+				return test_helpers.PingerExitedFromSignal
+			})
+
+			proc, err := ifrit.InvokeWithTimeout(neverReady, 50*time.Millisecond)
+			Ω(err).Should(Equal(ifrit.ReadyTimeoutError{Timeout: 50 * time.Millisecond, Err: test_helpers.PingerExitedFromSignal}))
+			Ω(errors.Is(err, test_helpers.PingerExitedFromSignal)).Should(BeTrue())
+			Eventually(proc.Wait()).Should(Receive())
+		})
+
+		It("returns the running process if the runner ignores being killed", func() {
+			release := make(chan struct{})
+			stubborn := ifrit.RunFunc(func(signals <-chan os.Signal, ready chan<- struct{}) error {
+				<-release
+				return nil
+			})
+
+			proc, err := ifrit.InvokeWithTimeout(stubborn, 50*time.Millisecond)
+			Ω(err).Should(Equal(ifrit.ReadyTimeoutError{Timeout: 50 * time.Millisecond}))
+			Consistently(proc.Wait()).ShouldNot(Receive())
+
+			close(release)
+			Eventually(proc.Wait()).Should(Receive(BeNil()))
+		})
+	})
+
+	Describe("signaling repeatedly", func() {
+		It("does not send a signal again while it is waiting to be received", func() {
+			release := make(chan struct{})
+			received := make(chan os.Signal, 10)
+			runner := ifrit.RunFunc(func(signals <-chan os.Signal, ready chan<- struct{}) error {
+				close(ready)
+				<-release
+				for signal := range signals {
+					received <- signal
+					if signal == os.Kill {
+						return nil
+					}
+				}
+				return nil
+			})
+
+			proc := ifrit.Invoke(runner)
+			proc.Signal(syscall.SIGHUP)
+			proc.Signal(syscall.SIGHUP)
+			proc.Signal(syscall.SIGHUP)
+			close(release)
+
+			Eventually(received).Should(Receive(Equal(syscall.SIGHUP)))
+			Consistently(received).ShouldNot(Receive())
+
+			proc.Signal(syscall.SIGHUP)
+			Eventually(received).Should(Receive(Equal(syscall.SIGHUP)))
+
+			proc.Signal(os.Kill)
+			Eventually(proc.Wait()).Should(Receive(BeNil()))
+		})
+	})
+
+	Describe("waiting with a deadline", func() {
+		var pinger test_helpers.PingChan
+		var proc ifrit.Process
+
+		BeforeEach(func() {
+			pinger = make(test_helpers.PingChan)
+			proc = ifrit.Invoke(pinger)
+		})
+
+		AfterEach(func() {
+			proc.Signal(os.Kill)
+			Eventually(proc.Wait()).Should(Receive())
+		})
+
+		It("closes Done once the process exits", func() {
+			done := proc.(ifrit.ProcessStatus).Done()
+			Consistently(done).ShouldNot(BeClosed())
+
+			<-pinger
+			Eventually(done).Should(BeClosed())
+			err, exited := proc.(ifrit.ProcessStatus).ExitError()
+			Ω(exited).Should(BeTrue())
+			Ω(err).Should(Equal(test_helpers.PingerExitedFromPing))
+		})
+
+		It("returns the exit error from WaitContext", func() {
+			<-pinger
+			Ω(ifrit.WaitContext(context.Background(), proc)).Should(Equal(test_helpers.PingerExitedFromPing))
+		})
+
+		It("returns the context's error from WaitContext if it is done first", func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+
+			Ω(ifrit.WaitContext(ctx, proc)).Should(Equal(context.DeadlineExceeded))
+			_, exited := proc.(ifrit.ProcessStatus).ExitError()
+			Ω(exited).Should(BeFalse())
+		})
+	})
+
+	Describe("BackgroundWithCallback", func() {
+		It("calls the callback with the exit error", func() {
+			exitErrs := make(chan error, 1)
+			pinger := make(test_helpers.PingChan)
+			proc := ifrit.BackgroundWithCallback(pinger, func(err error) {
+				exitErrs <- err
+			})
+
+			Eventually(proc.Ready()).Should(BeClosed())
+			Consistently(exitErrs).ShouldNot(Receive())
+
+			proc.Signal(os.Kill)
+			Eventually(exitErrs).Should(Receive(Equal(test_helpers.PingerExitedFromSignal)))
+			Ω(<-proc.Wait()).Should(Equal(test_helpers.PingerExitedFromSignal))
+		})
+	})
+
+	Describe("State", func() {
+		It("reports each stage of the lifecycle", func() {
+			readyChan := make(chan struct{})
+			exitChan := make(chan error)
+			runner := ifrit.RunFunc(func(signals <-chan os.Signal, ready chan<- struct{}) error {
+				<-readyChan
+				close(ready)
+				<-signals
+				return <-exitChan
+			})
+
+			status := ifrit.Background(runner).(ifrit.ProcessStatus)
+			state := func() ifrit.ProcessState {
+				s, _ := status.State()
+				return s
+			}
+			Eventually(state).Should(Equal(ifrit.StateRunning))
+
+			close(readyChan)
+			Eventually(state).Should(Equal(ifrit.StateReady))
+
+			status.Signal(os.Interrupt)
+			Ω(state()).Should(Equal(ifrit.StateStopping))
+
+			exitChan <- test_helpers.PingerExitedFromSignal
+			Eventually(state).Should(Equal(ifrit.StateExited))
+			_, err := status.State()
+			Ω(err).Should(Equal(test_helpers.PingerExitedFromSignal))
+		})
+
+		It("stays ready when it is sent a signal which is not a stop signal", func() {
+			runner := ifrit.RunFunc(func(signals <-chan os.Signal, ready chan<- struct{}) error {
+				close(ready)
+				for signal := range signals {
+					if signal != syscall.SIGHUP {
+						return nil
+					}
+				}
+				return nil
+			})
+
+			status := ifrit.Invoke(runner).(ifrit.ProcessStatus)
+			state := func() ifrit.ProcessState {
+				s, _ := status.State()
+				return s
+			}
+
+			status.Signal(syscall.SIGHUP)
+			Consistently(state).Should(Equal(ifrit.StateReady))
+
+			status.Signal(os.Interrupt)
+			Eventually(status.Wait()).Should(Receive())
+		})
+
+		It("is stopping once a Stopper has stopped it, whichever signal it sends", func() {
+			signalsReceived := make(chan os.Signal, 1)
+			exitChan := make(chan error)
+			runner := ifrit.RunFunc(func(signals <-chan os.Signal, ready chan<- struct{}) error {
+				close(ready)
+				signalsReceived <- <-signals
+				return <-exitChan
+			})
+
+			status := ifrit.Invoke(runner).(ifrit.ProcessStatus)
+			ifrit.Stopper{StopSignal: syscall.SIGUSR1, KillSignal: os.Kill}.Stop(status)
+
+			state, _ := status.State()
+			Ω(state).Should(Equal(ifrit.StateStopping))
+			Eventually(signalsReceived).Should(Receive(Equal(syscall.SIGUSR1)))
+			exitChan <- nil
+		})
+
+		It("names the states", func() {
+			Ω(ifrit.StatePending.String()).Should(Equal("pending"))
+			Ω(ifrit.StateExited.String()).Should(Equal("exited"))
+		})
+	})
 })
@@ -0,0 +1,47 @@
+package ifrit
+
+import (
+	"fmt"
+	"os"
+	"runtime/debug"
+)
+
+/*
+A PanicError is returned by a Runner returned by RecoverPanics when the Runner it
+wraps panics.  Value is the value passed to panic, and Stack is the stack of the
+panicking goroutine.
+*/
+type PanicError struct {
+	Value interface{}
+	Stack []byte
+}
+
+func (e PanicError) Error() string {
+	return fmt.Sprintf("runner panicked: %v", e.Value)
+}
+
+/*
+Unwrap returns the value passed to panic, if it was an error.
+*/
+func (e PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+/*
+RecoverPanics returns a Runner which runs r, and returns a PanicError if r
+panics, rather than crashing the program.  Only panics in the goroutine calling
+Run are recovered; a panic in a goroutine started by r still crashes the
+program.
+*/
+func RecoverPanics(r Runner) Runner {
+	return RunFunc(func(signals <-chan os.Signal, ready chan<- struct{}) (err error) {
+		defer func() {
+			if value := recover(); value != nil {
+				err = PanicError{Value: value, Stack: debug.Stack()}
+			}
+		}()
+
+		return r.Run(signals, ready)
+	})
+}
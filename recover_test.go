@@ -0,0 +1,50 @@
+package ifrit_test
+
+import (
+	"errors"
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/tedsuo/ifrit"
+	"github.com/tedsuo/ifrit/test_helpers"
+)
+
+var _ = Describe("RecoverPanics", func() {
+	panicking := func(value interface{}) ifrit.Runner {
+		return ifrit.RunFunc(func(signals <-chan os.Signal, ready chan<- struct{}) error {
+			close(ready)
+			<-signals
+			panic(value)
+		})
+	}
+
+	It("returns a PanicError carrying the value and the stack", func() {
+		process := ifrit.Invoke(ifrit.RecoverPanics(panicking("boom")))
+		process.Signal(os.Interrupt)
+
+		var err error
+		Eventually(process.Wait()).Should(Receive(&err))
+
+		var panicErr ifrit.PanicError
+		Ω(errors.As(err, &panicErr)).Should(BeTrue())
+		Ω(panicErr.Value).Should(Equal("boom"))
+		Ω(string(panicErr.Stack)).Should(ContainSubstring("recover_test.go"))
+		Ω(err.Error()).Should(Equal("runner panicked: boom"))
+	})
+
+	It("unwraps panics with an error value", func() {
+		panicValue := errors.New("bad state")
+		process := ifrit.Invoke(ifrit.RecoverPanics(panicking(panicValue)))
+		process.Signal(os.Interrupt)
+
+		var err error
+		Eventually(process.Wait()).Should(Receive(&err))
+		Ω(errors.Is(err, panicValue)).Should(BeTrue())
+	})
+
+	It("returns the runner's error when it does not panic", func() {
+		process := ifrit.Invoke(ifrit.RecoverPanics(test_helpers.NoReadyRunner))
+		Eventually(process.Wait()).Should(Receive(Equal(test_helpers.NoReadyExitedNormally)))
+	})
+})
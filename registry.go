@@ -0,0 +1,152 @@
+package ifrit
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+)
+
+/*
+A DuplicateProcessError is returned by a Registry when a process is registered
+under a name which is already in use.
+*/
+type DuplicateProcessError struct {
+	Name string
+}
+
+func (e DuplicateProcessError) Error() string {
+	return fmt.Sprintf("a process named %q is already registered", e.Name)
+}
+
+/*
+A RegisteredProcess is a Process and the name it was registered under.
+*/
+type RegisteredProcess struct {
+	Name    string
+	Process Process
+}
+
+/*
+A Registry tracks running processes by name, so that operational tooling can
+enumerate and signal them.  Processes are removed from the Registry once they
+exit.  The zero value is not usable; use NewRegistry, or DefaultRegistry.
+*/
+type Registry struct {
+	lock      sync.Mutex
+	processes map[string]Process
+}
+
+/*
+DefaultRegistry is a Registry for programs which only need one.
+*/
+var DefaultRegistry = NewRegistry()
+
+/*
+NewRegistry returns an empty Registry.
+*/
+func NewRegistry() *Registry {
+	return &Registry{processes: map[string]Process{}}
+}
+
+/*
+Invoke is like the package level Invoke, but registers the Process under name
+as soon as the Runner has started.  The Runner is not started if name is
+already in use.
+*/
+func (r *Registry) Invoke(name string, runner Runner) (Process, error) {
+	p, err := r.Background(name, runner)
+	if err != nil {
+		return nil, err
+	}
+
+	select {
+	case <-p.Ready():
+	case <-p.Wait():
+	}
+
+	return p, nil
+}
+
+/*
+Background is like the package level Background, but registers the Process
+under name.  The Runner is not started if name is already in use.
+*/
+func (r *Registry) Background(name string, runner Runner) (Process, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if _, found := r.processes[name]; found {
+		return nil, DuplicateProcessError{Name: name}
+	}
+
+	p := Background(runner)
+	r.add(name, p)
+	return p, nil
+}
+
+/*
+Register adds a Process which has already been started to the Registry.
+*/
+func (r *Registry) Register(name string, p Process) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if _, found := r.processes[name]; found {
+		return DuplicateProcessError{Name: name}
+	}
+
+	r.add(name, p)
+	return nil
+}
+
+func (r *Registry) add(name string, p Process) {
+	r.processes[name] = p
+
+	go func() {
+		<-p.Wait()
+
+		r.lock.Lock()
+		defer r.lock.Unlock()
+		if r.processes[name] == p {
+			delete(r.processes, name)
+		}
+	}()
+}
+
+/*
+Get returns the Process registered under name, if there is one.
+*/
+func (r *Registry) Get(name string) (Process, bool) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	p, found := r.processes[name]
+	return p, found
+}
+
+/*
+List returns the registered processes, sorted by name.
+*/
+func (r *Registry) List() []RegisteredProcess {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	list := make([]RegisteredProcess, 0, len(r.processes))
+	for name, p := range r.processes {
+		list = append(list, RegisteredProcess{Name: name, Process: p})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Name < list[j].Name
+	})
+	return list
+}
+
+/*
+SignalAll sends signal to every registered process.
+*/
+func (r *Registry) SignalAll(signal os.Signal) {
+	for _, registered := range r.List() {
+		registered.Process.Signal(signal)
+	}
+}
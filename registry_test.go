@@ -0,0 +1,87 @@
+package ifrit_test
+
+import (
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/tedsuo/ifrit"
+	"github.com/tedsuo/ifrit/test_helpers"
+)
+
+var _ = Describe("Registry", func() {
+	var registry *ifrit.Registry
+
+	BeforeEach(func() {
+		registry = ifrit.NewRegistry()
+	})
+
+	AfterEach(func() {
+		registry.SignalAll(os.Kill)
+		Eventually(registry.List).Should(BeEmpty())
+	})
+
+	It("looks up and lists registered processes", func() {
+		web, err := registry.Invoke("web", make(test_helpers.PingChan))
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(web.Ready()).Should(BeClosed())
+
+		worker, err := registry.Background("worker", make(test_helpers.PingChan))
+		Ω(err).ShouldNot(HaveOccurred())
+
+		p, found := registry.Get("web")
+		Ω(found).Should(BeTrue())
+		Ω(p).Should(Equal(web))
+
+		_, found = registry.Get("database")
+		Ω(found).Should(BeFalse())
+
+		Ω(registry.List()).Should(Equal([]ifrit.RegisteredProcess{
+			{Name: "web", Process: web},
+			{Name: "worker", Process: worker},
+		}))
+	})
+
+	It("refuses duplicate names without starting the runner", func() {
+		_, err := registry.Invoke("web", make(test_helpers.PingChan))
+		Ω(err).ShouldNot(HaveOccurred())
+
+		started := false
+		runner := ifrit.RunFunc(func(signals <-chan os.Signal, ready chan<- struct{}) error {
+			started = true
+			return nil
+		})
+		_, err = registry.Invoke("web", runner)
+		Ω(err).Should(Equal(ifrit.DuplicateProcessError{Name: "web"}))
+		Ω(started).Should(BeFalse())
+
+		err = registry.Register("web", ifrit.Background(runner))
+		Ω(err).Should(MatchError(`a process named "web" is already registered`))
+	})
+
+	It("removes processes once they exit", func() {
+		p, err := registry.Invoke("web", make(test_helpers.PingChan))
+		Ω(err).ShouldNot(HaveOccurred())
+
+		p.Signal(os.Kill)
+		Eventually(p.Wait()).Should(Receive())
+		Eventually(func() bool {
+			_, found := registry.Get("web")
+			return found
+		}).Should(BeFalse())
+
+		_, err = registry.Invoke("web", make(test_helpers.PingChan))
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	It("signals every registered process", func() {
+		web := ifrit.Invoke(make(test_helpers.PingChan))
+		Ω(registry.Register("web", web)).Should(Succeed())
+		worker, err := registry.Invoke("worker", make(test_helpers.PingChan))
+		Ω(err).ShouldNot(HaveOccurred())
+
+		registry.SignalAll(os.Kill)
+		Eventually(web.Wait()).Should(Receive(Equal(test_helpers.PingerExitedFromSignal)))
+		Eventually(worker.Wait()).Should(Receive(Equal(test_helpers.PingerExitedFromSignal)))
+	})
+})
@@ -0,0 +1,144 @@
+package ifrit
+
+import (
+	"errors"
+	"os"
+	"time"
+)
+
+/*
+ErrProcessStopped is returned by RestartableProcess.Restart once the process
+has been signaled, or has exited.
+*/
+var ErrProcessStopped = errors.New("process has been stopped")
+
+/*
+ErrRestartInProgress is returned by RestartableProcess.Restart while another
+restart has not finished.
+*/
+var ErrRestartInProgress = errors.New("restart already in progress")
+
+/*
+A RestartableProcess is a Process which can tear down its Runner and run it
+again, without its holders needing a new Process.
+
+Ready closes the first time the Runner becomes ready, and Wait emits once a run
+exits for any reason other than a Restart.  Signals are delivered to the
+current run.
+*/
+type RestartableProcess interface {
+	ProcessStatus
+
+	// Restart sends the stop signal to the current run, waits for it to exit,
+	// and runs the Runner again.  It returns once the new run is ready, or with
+	// the error the new run exited with if it exits first.
+	Restart() error
+}
+
+/*
+InvokeRestartable is like Invoke, but returns a RestartableProcess which stops
+its current run with stopSignal when restarted.
+*/
+func InvokeRestartable(r Runner, stopSignal os.Signal) RestartableProcess {
+	p := BackgroundRestartable(r, stopSignal)
+
+	select {
+	case <-p.Ready():
+	case <-p.Wait():
+	}
+
+	return p
+}
+
+/*
+BackgroundRestartable is like Background, but returns a RestartableProcess
+which stops its current run with stopSignal when restarted.
+*/
+func BackgroundRestartable(r Runner, stopSignal os.Signal) RestartableProcess {
+	restarts := make(chan chan error)
+	p := &restartableProcess{
+		process:  newProcess(restartLoop(r, stopSignal, restarts)),
+		restarts: restarts,
+	}
+	p.startedAt = time.Now()
+	go p.run()
+	return p
+}
+
+type restartableProcess struct {
+	*process
+	restarts chan chan error
+}
+
+func (p *restartableProcess) Restart() error {
+	reply := make(chan error, 1)
+
+	select {
+	case p.restarts <- reply:
+	case <-p.exited:
+		return ErrProcessStopped
+	}
+
+	return <-reply
+}
+
+func restartLoop(r Runner, stopSignal os.Signal, restarts <-chan chan error) Runner {
+	return RunFunc(func(signals <-chan os.Signal, ready chan<- struct{}) error {
+		process := Background(r)
+		processReady := process.Ready()
+		exit := process.Wait()
+
+		var restarting chan error
+		stopping := false
+		signaled := false
+
+		for {
+			select {
+			case signal := <-signals:
+				process.Signal(signal)
+				signaled = true
+
+			case <-processReady:
+				processReady = nil
+				if ready != nil {
+					close(ready)
+					ready = nil
+				}
+				if restarting != nil {
+					restarting <- nil
+					restarting = nil
+				}
+
+			case reply := <-restarts:
+				switch {
+				case signaled:
+					reply <- ErrProcessStopped
+				case restarting != nil:
+					reply <- ErrRestartInProgress
+				default:
+					restarting = reply
+					stopping = true
+					process.Signal(stopSignal)
+				}
+
+			case err := <-exit:
+				if stopping && !signaled {
+					stopping = false
+					process = Background(r)
+					processReady = process.Ready()
+					exit = process.Wait()
+					continue
+				}
+
+				if restarting != nil {
+					if err == nil || signaled {
+						restarting <- ErrProcessStopped
+					} else {
+						restarting <- err
+					}
+				}
+				return err
+			}
+		}
+	})
+}
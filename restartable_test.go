@@ -0,0 +1,97 @@
+package ifrit_test
+
+import (
+	"errors"
+	"os"
+	"sync/atomic"
+	"syscall"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/tedsuo/ifrit"
+	"github.com/tedsuo/ifrit/test_helpers"
+)
+
+var _ = Describe("RestartableProcess", func() {
+	var runs int32
+	var readyRuns chan int32
+	var runner ifrit.Runner
+
+	BeforeEach(func() {
+		runs = 0
+		readyRuns = make(chan int32, 10)
+		runner = ifrit.RunFunc(func(signals <-chan os.Signal, ready chan<- struct{}) error {
+			run := atomic.AddInt32(&runs, 1)
+			close(ready)
+			readyRuns <- run
+			if (<-signals) == syscall.SIGTERM {
+				return nil
+			}
+			return test_helpers.PingerExitedFromSignal
+		})
+	})
+
+	It("runs the runner again on Restart, keeping the same handle", func() {
+		p := ifrit.InvokeRestartable(runner, syscall.SIGTERM)
+		Ω(p.Ready()).Should(BeClosed())
+		Ω(<-readyRuns).Should(BeEquivalentTo(1))
+
+		Ω(p.Restart()).Should(Succeed())
+		Ω(readyRuns).Should(Receive(BeEquivalentTo(2)))
+		Consistently(p.Wait()).ShouldNot(Receive())
+
+		p.Signal(os.Kill)
+		Eventually(p.Wait()).Should(Receive(Equal(test_helpers.PingerExitedFromSignal)))
+		Ω(p.Restart()).Should(Equal(ifrit.ErrProcessStopped))
+	})
+
+	It("exits when a run exits without being restarted", func() {
+		p := ifrit.InvokeRestartable(runner, syscall.SIGTERM)
+		p.Signal(syscall.SIGTERM)
+		Eventually(p.Wait()).Should(Receive(BeNil()))
+		Eventually(p.Done()).Should(BeClosed())
+	})
+
+	It("returns the error of a replacement which fails to start", func() {
+		startErr := errors.New("failed to start")
+		var attempts int32
+		flaky := ifrit.RunFunc(func(signals <-chan os.Signal, ready chan<- struct{}) error {
+			if atomic.AddInt32(&attempts, 1) > 1 {
+				return startErr
+			}
+			close(ready)
+			<-signals
+			return nil
+		})
+
+		p := ifrit.InvokeRestartable(flaky, syscall.SIGTERM)
+		Ω(p.Restart()).Should(Equal(startErr))
+		Eventually(p.Wait()).Should(Receive(Equal(startErr)))
+	})
+
+	It("refuses a restart while another is in progress", func() {
+		stopping := make(chan struct{}, 1)
+		release := make(chan struct{})
+		slow := ifrit.RunFunc(func(signals <-chan os.Signal, ready chan<- struct{}) error {
+			close(ready)
+			<-signals
+			stopping <- struct{}{}
+			<-release
+			return nil
+		})
+
+		p := ifrit.InvokeRestartable(slow, syscall.SIGTERM)
+		first := make(chan error, 1)
+		go func() {
+			first <- p.Restart()
+		}()
+
+		Eventually(stopping).Should(Receive())
+		Ω(p.Restart()).Should(Equal(ifrit.ErrRestartInProgress))
+		close(release)
+		Eventually(first).Should(Receive(BeNil()))
+
+		p.Signal(syscall.SIGTERM)
+		Eventually(p.Wait()).Should(Receive())
+	})
+})
@@ -0,0 +1,25 @@
+package ifrit_test
+
+import (
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/tedsuo/ifrit"
+)
+
+var _ = Describe("RunFunc", func() {
+	It("runs the function as a Runner", func() {
+		var runner ifrit.Runner = ifrit.RunFunc(func(signals <-chan os.Signal, ready chan<- struct{}) error {
+			close(ready)
+			<-signals
+			return nil
+		})
+
+		process := ifrit.Invoke(runner)
+		Ω(process.Ready()).Should(BeClosed())
+
+		process.Signal(os.Interrupt)
+		Eventually(process.Wait()).Should(Receive(BeNil()))
+	})
+})
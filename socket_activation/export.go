@@ -0,0 +1,91 @@
+package socket_activation
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+type fileListener interface {
+	File() (*os.File, error)
+}
+
+/*
+Export passes listeners to the child process cmd using the same protocol as
+systemd socket activation, so that the child can retrieve them with Listeners or
+Listener.  This allows a server to hand its listening sockets to a new version of
+itself, which begins accepting connections before the old version drains and
+exits.
+
+names is optional; if given, it must have the same length as listeners.  cmd must
+not have any ExtraFiles of its own, and must not have been started.  The
+listeners must be *net.TCPListener or *net.UnixListener, or otherwise provide a
+File method.
+
+Export duplicates each listener's descriptor into cmd.ExtraFiles.  The caller
+should close cmd.ExtraFiles once the child has started.  A Unix listener removes
+its socket file when it is closed, unless SetUnlinkOnClose(false) is called first.
+
+Export does not set LISTEN_PID, since the child's pid is not known until it has
+started.  Listeners accepts sockets without it, but sd_listen_fds(3), and other
+implementations which require LISTEN_PID, ignore them.  Such a child can be run
+through a shell which sets it before replacing itself with the child, as in
+exec.Command("sh", "-c", `LISTEN_PID=$$ exec "$0" "$@"`, path, args...).
+
+Without LISTEN_PID to tie them to the child, the LISTEN_ variables are inherited
+by any process the child starts in turn, and Listeners in that process would
+also try to use the sockets.  A child which starts processes of its own should
+unset the variables once it has its listeners.
+*/
+func Export(cmd *exec.Cmd, listeners []net.Listener, names []string) error {
+	if len(cmd.ExtraFiles) > 0 {
+		return errors.New("socket_activation: cmd already has ExtraFiles")
+	}
+	if names != nil && len(names) != len(listeners) {
+		return fmt.Errorf("socket_activation: %d names given for %d listeners", len(names), len(listeners))
+	}
+
+	files := make([]*os.File, 0, len(listeners))
+	for _, lis := range listeners {
+		fl, ok := lis.(fileListener)
+		if !ok {
+			closeFiles(files)
+			return fmt.Errorf("socket_activation: cannot export listener of type %T", lis)
+		}
+
+		f, err := fl.File()
+		if err != nil {
+			closeFiles(files)
+			return err
+		}
+		files = append(files, f)
+	}
+
+	env := cmd.Env
+	if env == nil {
+		env = os.Environ()
+	}
+	cmd.Env = []string{}
+	for _, kv := range env {
+		if !strings.HasPrefix(kv, "LISTEN_PID=") && !strings.HasPrefix(kv, "LISTEN_FDS=") && !strings.HasPrefix(kv, "LISTEN_FDNAMES=") {
+			cmd.Env = append(cmd.Env, kv)
+		}
+	}
+	cmd.Env = append(cmd.Env, "LISTEN_FDS="+strconv.Itoa(len(files)))
+	if names != nil {
+		cmd.Env = append(cmd.Env, "LISTEN_FDNAMES="+strings.Join(names, ":"))
+	}
+
+	cmd.ExtraFiles = files
+	return nil
+}
+
+func closeFiles(files []*os.File) {
+	for _, f := range files {
+		f.Close()
+	}
+}
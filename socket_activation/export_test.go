@@ -0,0 +1,84 @@
+package socket_activation
+
+import (
+	"crypto/tls"
+	"net"
+	"os"
+	"os/exec"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Export", func() {
+	var (
+		listeners []net.Listener
+		cmd       *exec.Cmd
+	)
+
+	BeforeEach(func() {
+		listeners = nil
+		for i := 0; i < 2; i++ {
+			lis, err := net.Listen("tcp", "127.0.0.1:0")
+			Expect(err).NotTo(HaveOccurred())
+			listeners = append(listeners, lis)
+		}
+
+		cmd = exec.Command("true")
+		cmd.Env = []string{"HOME=/home/ifrit", "LISTEN_PID=1", "LISTEN_FDS=7"}
+	})
+
+	AfterEach(func() {
+		for _, lis := range listeners {
+			lis.Close()
+		}
+		closeFiles(cmd.ExtraFiles)
+	})
+
+	It("passes the listeners to the child as systemd would", func() {
+		err := Export(cmd, listeners, []string{"first", "second"})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(cmd.Env).To(ConsistOf("HOME=/home/ifrit", "LISTEN_FDS=2", "LISTEN_FDNAMES=first:second"))
+		Expect(cmd.ExtraFiles).To(HaveLen(2))
+		for i, f := range cmd.ExtraFiles {
+			lis, err := net.FileListener(f)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(lis.Addr().String()).To(Equal(listeners[i].Addr().String()))
+			lis.Close()
+		}
+	})
+
+	It("does not name the sockets when no names are given", func() {
+		err := Export(cmd, listeners, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cmd.Env).To(ConsistOf("HOME=/home/ifrit", "LISTEN_FDS=2"))
+	})
+
+	It("inherits the parent's environment when cmd has none", func() {
+		cmd.Env = nil
+		err := Export(cmd, listeners, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cmd.Env).To(ContainElement("PATH=" + os.Getenv("PATH")))
+	})
+
+	It("fails when the names do not match the listeners", func() {
+		err := Export(cmd, listeners, []string{"first"})
+		Expect(err).To(HaveOccurred())
+		Expect(cmd.ExtraFiles).To(BeEmpty())
+	})
+
+	It("fails when cmd already has ExtraFiles", func() {
+		cmd.ExtraFiles = []*os.File{os.Stdin}
+		err := Export(cmd, listeners, nil)
+		Expect(err).To(HaveOccurred())
+		cmd.ExtraFiles = nil
+	})
+
+	It("fails when a listener has no file", func() {
+		listeners[1] = tls.NewListener(listeners[1], &tls.Config{})
+		err := Export(cmd, listeners, nil)
+		Expect(err).To(MatchError(ContainSubstring("cannot export listener")))
+		Expect(cmd.ExtraFiles).To(BeEmpty())
+	})
+})
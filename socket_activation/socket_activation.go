@@ -0,0 +1,110 @@
+/*
+The socket_activation package retrieves listening sockets passed to the process
+by systemd socket activation, as described in sd_listen_fds(3).
+*/
+package socket_activation
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ErrNoSockets is returned when the process was not passed any sockets.
+var ErrNoSockets = errors.New("socket_activation: no sockets were passed to this process")
+
+// listenFdsStart is the first file descriptor passed by systemd, SD_LISTEN_FDS_START.
+var listenFdsStart = 3
+
+var (
+	filesLock sync.Mutex
+	files     = map[int]*os.File{}
+)
+
+/*
+Listeners returns a listener for each socket passed to the process, in the order
+they were passed.  The names of the sockets, as configured by FileDescriptorName=,
+are returned alongside the listeners.
+
+Listeners may be called more than once; each call returns new listeners for the
+same sockets, so a restarted Runner can listen again after closing its previous
+listener.
+*/
+func Listeners() ([]net.Listener, []string, error) {
+	pid := os.Getenv("LISTEN_PID")
+	if pid != "" && pid != strconv.Itoa(os.Getpid()) {
+		return nil, nil, ErrNoSockets
+	}
+
+	count, err := strconv.Atoi(os.Getenv("LISTEN_FDS"))
+	if err != nil || count < 1 {
+		return nil, nil, ErrNoSockets
+	}
+
+	names := make([]string, count)
+	if fdNames := os.Getenv("LISTEN_FDNAMES"); fdNames != "" {
+		copy(names, strings.Split(fdNames, ":"))
+	}
+
+	listeners := make([]net.Listener, 0, count)
+	for i := 0; i < count; i++ {
+		lis, err := net.FileListener(file(listenFdsStart + i))
+		if err != nil {
+			for _, opened := range listeners {
+				opened.Close()
+			}
+			return nil, nil, err
+		}
+		listeners = append(listeners, lis)
+	}
+
+	return listeners, names, nil
+}
+
+/*
+Listener returns a listener for the socket named name.  If name is empty, it
+returns a listener for the first socket passed to the process.
+*/
+func Listener(name string) (net.Listener, error) {
+	listeners, names, err := Listeners()
+	if err != nil {
+		return nil, err
+	}
+
+	found := -1
+	for i := range listeners {
+		if name == "" || names[i] == name {
+			found = i
+			break
+		}
+	}
+
+	for i, lis := range listeners {
+		if i != found {
+			lis.Close()
+		}
+	}
+
+	if found == -1 {
+		return nil, fmt.Errorf("socket_activation: no socket named %q was passed to this process", name)
+	}
+	return listeners[found], nil
+}
+
+// file returns the file for fd.  Files are retained, so that the descriptors
+// passed by systemd are not closed when a file is garbage collected.
+func file(fd int) *os.File {
+	filesLock.Lock()
+	defer filesLock.Unlock()
+
+	f, ok := files[fd]
+	if !ok {
+		f = os.NewFile(uintptr(fd), "LISTEN_FD_"+strconv.Itoa(fd))
+		files[fd] = f
+	}
+	return f
+}
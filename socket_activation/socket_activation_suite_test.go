@@ -0,0 +1,13 @@
+package socket_activation
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestSocketActivation(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Socket Activation Suite")
+}
@@ -0,0 +1,121 @@
+package socket_activation
+
+import (
+	"net"
+	"os"
+	"strconv"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"golang.org/x/sys/unix"
+)
+
+var _ = Describe("Socket Activation", func() {
+	var (
+		passed      []net.Listener
+		originalEnv map[string]string
+	)
+
+	// pass simulates systemd passing the given listeners to the process by
+	// duplicating them onto consecutive descriptors.
+	pass := func(names string, addresses ...string) {
+		start := 100 + GinkgoParallelNode()*10
+		for i, address := range addresses {
+			lis, err := net.Listen("tcp", address)
+			Expect(err).NotTo(HaveOccurred())
+			passed = append(passed, lis)
+
+			f, err := lis.(*net.TCPListener).File()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(unix.Dup2(int(f.Fd()), start+i)).To(Succeed())
+			f.Close()
+		}
+
+		listenFdsStart = start
+		os.Setenv("LISTEN_PID", strconv.Itoa(os.Getpid()))
+		os.Setenv("LISTEN_FDS", strconv.Itoa(len(addresses)))
+		os.Setenv("LISTEN_FDNAMES", names)
+	}
+
+	BeforeEach(func() {
+		passed = nil
+		originalEnv = map[string]string{}
+		for _, key := range []string{"LISTEN_PID", "LISTEN_FDS", "LISTEN_FDNAMES"} {
+			originalEnv[key] = os.Getenv(key)
+			os.Unsetenv(key)
+		}
+	})
+
+	AfterEach(func() {
+		for _, lis := range passed {
+			lis.Close()
+		}
+		for key, value := range originalEnv {
+			os.Setenv(key, value)
+		}
+	})
+
+	Describe("Listeners", func() {
+		It("fails when no sockets were passed", func() {
+			_, _, err := Listeners()
+			Expect(err).To(Equal(ErrNoSockets))
+		})
+
+		It("fails when the sockets were passed to another process", func() {
+			pass("", "127.0.0.1:0")
+			os.Setenv("LISTEN_PID", strconv.Itoa(os.Getpid()+1))
+
+			_, _, err := Listeners()
+			Expect(err).To(Equal(ErrNoSockets))
+		})
+
+		It("returns a listener for each passed socket, with its name", func() {
+			pass("first:second", "127.0.0.1:0", "127.0.0.1:0")
+
+			listeners, names, err := Listeners()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(names).To(Equal([]string{"first", "second"}))
+			Expect(listeners).To(HaveLen(2))
+			for i, lis := range listeners {
+				Expect(lis.Addr().String()).To(Equal(passed[i].Addr().String()))
+				lis.Close()
+			}
+		})
+
+		It("can be called again after the listeners are closed", func() {
+			pass("", "127.0.0.1:0")
+
+			for i := 0; i < 2; i++ {
+				listeners, _, err := Listeners()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(listeners[0].Addr().String()).To(Equal(passed[0].Addr().String()))
+				listeners[0].Close()
+			}
+		})
+	})
+
+	Describe("Listener", func() {
+		BeforeEach(func() {
+			pass("first:second", "127.0.0.1:0", "127.0.0.1:0")
+		})
+
+		It("returns the first socket when no name is given", func() {
+			lis, err := Listener("")
+			Expect(err).NotTo(HaveOccurred())
+			defer lis.Close()
+			Expect(lis.Addr().String()).To(Equal(passed[0].Addr().String()))
+		})
+
+		It("returns the socket with the given name", func() {
+			lis, err := Listener("second")
+			Expect(err).NotTo(HaveOccurred())
+			defer lis.Close()
+			Expect(lis.Addr().String()).To(Equal(passed[1].Addr().String()))
+		})
+
+		It("fails when no socket has the given name", func() {
+			_, err := Listener("third")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,106 @@
+package ifrit
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+	"time"
+)
+
+/*
+A Stopper stops Processes, with a signal which asks them to stop, and another
+which insists that they do.
+*/
+type Stopper struct {
+	StopSignal os.Signal
+	KillSignal os.Signal
+}
+
+/*
+DefaultStopper is used by Stop, Kill and StopAndWait.
+*/
+var DefaultStopper = Stopper{
+	StopSignal: syscall.SIGTERM,
+	KillSignal: os.Kill,
+}
+
+/*
+A StopTimeoutError is returned by StopAndWait when a Process did not exit within
+the timeout and had to be killed.  Err is the error the Process exited with.
+*/
+type StopTimeoutError struct {
+	Timeout time.Duration
+	Err     error
+}
+
+func (e StopTimeoutError) Error() string {
+	return fmt.Sprintf("process did not stop within %s and was killed", e.Timeout)
+}
+
+func (e StopTimeoutError) Unwrap() error {
+	return e.Err
+}
+
+/*
+Stop sends p the stop signal.  It does not block.
+*/
+func (s Stopper) Stop(p Process) {
+	stop(p, s.StopSignal)
+}
+
+/*
+Kill sends p the kill signal.  It does not block.
+*/
+func (s Stopper) Kill(p Process) {
+	stop(p, s.KillSignal)
+}
+
+func stop(p Process, signal os.Signal) {
+	if p, ok := p.(*process); ok {
+		p.stop(signal)
+		return
+	}
+	p.Signal(signal)
+}
+
+/*
+StopAndWait sends p the stop signal, and returns the error it exits with.  If p
+has not exited within timeout it is sent the kill signal, and StopAndWait
+returns a StopTimeoutError once it exits.
+*/
+func (s Stopper) StopAndWait(p Process, timeout time.Duration) error {
+	s.Stop(p)
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	exited := p.Wait()
+	select {
+	case err := <-exited:
+		return err
+	case <-timer.C:
+		s.Kill(p)
+		return StopTimeoutError{Timeout: timeout, Err: <-exited}
+	}
+}
+
+/*
+Stop sends p the stop signal of DefaultStopper.
+*/
+func Stop(p Process) {
+	DefaultStopper.Stop(p)
+}
+
+/*
+Kill sends p the kill signal of DefaultStopper.
+*/
+func Kill(p Process) {
+	DefaultStopper.Kill(p)
+}
+
+/*
+StopAndWait stops p using DefaultStopper, see Stopper.StopAndWait.
+*/
+func StopAndWait(p Process, timeout time.Duration) error {
+	return DefaultStopper.StopAndWait(p, timeout)
+}
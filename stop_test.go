@@ -0,0 +1,58 @@
+package ifrit_test
+
+import (
+	"os"
+	"syscall"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/tedsuo/ifrit"
+	"github.com/tedsuo/ifrit/test_helpers"
+)
+
+var _ = Describe("Stopping processes", func() {
+	var recorder *test_helpers.SignalRecoder
+
+	BeforeEach(func() {
+		recorder = test_helpers.NewSignalRecorder(syscall.SIGTERM)
+	})
+
+	It("sends the default stop and kill signals", func() {
+		ignoring := test_helpers.NewSignalRecorder()
+		process := ifrit.Invoke(ignoring)
+		ifrit.Stop(process)
+		Eventually(ignoring.ReceivedSignals).Should(Equal([]os.Signal{syscall.SIGTERM}))
+
+		ifrit.Kill(process)
+		Eventually(process.Wait()).Should(Receive(BeNil()))
+		Ω(ignoring.ReceivedSignals()).Should(Equal([]os.Signal{syscall.SIGTERM, os.Kill}))
+	})
+
+	Describe("StopAndWait", func() {
+		It("returns once the process stops", func() {
+			process := ifrit.Invoke(recorder)
+			Ω(ifrit.StopAndWait(process, time.Second)).Should(Succeed())
+			Ω(recorder.ReceivedSignals()).Should(Equal([]os.Signal{syscall.SIGTERM}))
+		})
+
+		It("kills the process if it does not stop in time", func() {
+			ignoring := test_helpers.NewSignalRecorder()
+			process := ifrit.Invoke(ignoring)
+
+			err := ifrit.StopAndWait(process, 50*time.Millisecond)
+			Ω(err).Should(Equal(ifrit.StopTimeoutError{Timeout: 50 * time.Millisecond}))
+			Ω(ignoring.ReceivedSignals()).Should(Equal([]os.Signal{syscall.SIGTERM, os.Kill}))
+		})
+
+		It("uses the configured signals", func() {
+			stopper := ifrit.Stopper{StopSignal: syscall.SIGUSR1, KillSignal: syscall.SIGUSR2}
+			usr := test_helpers.NewSignalRecorder(syscall.SIGUSR2)
+			process := ifrit.Invoke(usr)
+
+			err := stopper.StopAndWait(process, 50*time.Millisecond)
+			Ω(err).Should(Equal(ifrit.StopTimeoutError{Timeout: 50 * time.Millisecond}))
+			Ω(usr.ReceivedSignals()).Should(Equal([]os.Signal{syscall.SIGUSR1, syscall.SIGUSR2}))
+		})
+	})
+})
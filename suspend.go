@@ -0,0 +1,130 @@
+package ifrit
+
+import (
+	"errors"
+	"os"
+	"sync"
+	"syscall"
+)
+
+/*
+ErrNotPausable is returned by Suspender.Pause and Suspender.Resume when the
+Runner does not implement Pausable.
+*/
+var ErrNotPausable = errors.New("runner does not support pausing")
+
+/*
+A Pausable Runner can stop dispatching work without exiting, and later carry
+on.  Pause and Resume are called concurrently with Run.
+*/
+type Pausable interface {
+	Runner
+	Pause() error
+	Resume() error
+}
+
+/*
+A Suspender is a Runner which runs a Pausable Runner, pausing it on SIGTSTP and
+resuming it on SIGCONT, much as the operating system does for programs.  These
+signals are not delivered to the Runner; if the Runner is not Pausable they are
+delivered unchanged.  Errors from pausing and resuming in response to a signal
+are discarded; call Pause and Resume directly to observe them.
+*/
+type Suspender struct {
+	runner Runner
+	lock   sync.Mutex
+	paused bool
+}
+
+/*
+Suspendable returns a Suspender which runs r.
+*/
+func Suspendable(r Runner) *Suspender {
+	return &Suspender{runner: r}
+}
+
+/*
+Pause pauses the Runner, if it is not already paused.
+*/
+func (s *Suspender) Pause() error {
+	pausable, ok := s.runner.(Pausable)
+	if !ok {
+		return ErrNotPausable
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.paused {
+		return nil
+	}
+
+	err := pausable.Pause()
+	if err == nil {
+		s.paused = true
+	}
+	return err
+}
+
+/*
+Resume resumes the Runner, if it is paused.
+*/
+func (s *Suspender) Resume() error {
+	pausable, ok := s.runner.(Pausable)
+	if !ok {
+		return ErrNotPausable
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if !s.paused {
+		return nil
+	}
+
+	err := pausable.Resume()
+	if err == nil {
+		s.paused = false
+	}
+	return err
+}
+
+/*
+Paused reports whether the Runner is paused.
+*/
+func (s *Suspender) Paused() bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.paused
+}
+
+func (s *Suspender) Run(signals <-chan os.Signal, ready chan<- struct{}) error {
+	_, pausable := s.runner.(Pausable)
+
+	innerSignals := make(chan os.Signal)
+	errs := make(chan error, 1)
+	go func() {
+		errs <- s.runner.Run(innerSignals, ready)
+	}()
+
+	for {
+		select {
+		case signal := <-signals:
+			if pausable && signal == syscall.SIGTSTP {
+				s.Pause()
+				continue
+			}
+			if pausable && signal == syscall.SIGCONT {
+				s.Resume()
+				continue
+			}
+
+			select {
+			case innerSignals <- signal:
+			case err := <-errs:
+				return err
+			}
+
+		case err := <-errs:
+			return err
+		}
+	}
+}
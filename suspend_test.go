@@ -0,0 +1,107 @@
+package ifrit_test
+
+import (
+	"errors"
+	"os"
+	"sync"
+	"syscall"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/tedsuo/ifrit"
+	"github.com/tedsuo/ifrit/test_helpers"
+)
+
+type pausableRunner struct {
+	*test_helpers.SignalRecoder
+
+	lock     sync.Mutex
+	calls    []string
+	pauseErr error
+}
+
+func (r *pausableRunner) Pause() error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.calls = append(r.calls, "pause")
+	return r.pauseErr
+}
+
+func (r *pausableRunner) Resume() error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.calls = append(r.calls, "resume")
+	return nil
+}
+
+func (r *pausableRunner) Calls() []string {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return append([]string{}, r.calls...)
+}
+
+var _ = Describe("Suspender", func() {
+	var runner *pausableRunner
+	var suspender *ifrit.Suspender
+	var process ifrit.Process
+
+	BeforeEach(func() {
+		runner = &pausableRunner{SignalRecoder: test_helpers.NewSignalRecorder()}
+		suspender = ifrit.Suspendable(runner)
+		process = ifrit.Invoke(suspender)
+	})
+
+	AfterEach(func() {
+		process.Signal(os.Kill)
+		Eventually(process.Wait()).Should(Receive())
+	})
+
+	It("pauses and resumes the runner", func() {
+		Ω(suspender.Pause()).Should(Succeed())
+		Ω(suspender.Pause()).Should(Succeed())
+		Ω(suspender.Paused()).Should(BeTrue())
+
+		Ω(suspender.Resume()).Should(Succeed())
+		Ω(suspender.Paused()).Should(BeFalse())
+		Ω(runner.Calls()).Should(Equal([]string{"pause", "resume"}))
+	})
+
+	It("pauses and resumes on SIGTSTP and SIGCONT without delivering them", func() {
+		process.Signal(syscall.SIGTSTP)
+		Eventually(suspender.Paused).Should(BeTrue())
+
+		process.Signal(syscall.SIGCONT)
+		Eventually(suspender.Paused).Should(BeFalse())
+
+		process.Signal(syscall.SIGHUP)
+		Eventually(runner.ReceivedSignals).Should(Equal([]os.Signal{syscall.SIGHUP}))
+		Ω(runner.Calls()).Should(Equal([]string{"pause", "resume"}))
+	})
+
+	It("stays running when pausing fails", func() {
+		runner.pauseErr = errors.New("busy")
+		Ω(suspender.Pause()).Should(MatchError("busy"))
+		Ω(suspender.Paused()).Should(BeFalse())
+	})
+
+	Context("when the runner is not pausable", func() {
+		var recorder *test_helpers.SignalRecoder
+
+		BeforeEach(func() {
+			process.Signal(os.Kill)
+			Eventually(process.Wait()).Should(Receive())
+
+			recorder = test_helpers.NewSignalRecorder()
+			suspender = ifrit.Suspendable(recorder)
+			process = ifrit.Invoke(suspender)
+		})
+
+		It("delivers SIGTSTP and SIGCONT unchanged", func() {
+			Ω(suspender.Pause()).Should(Equal(ifrit.ErrNotPausable))
+			Ω(suspender.Resume()).Should(Equal(ifrit.ErrNotPausable))
+
+			process.Signal(syscall.SIGTSTP)
+			Eventually(recorder.ReceivedSignals).Should(Equal([]os.Signal{syscall.SIGTSTP}))
+		})
+	})
+})
@@ -0,0 +1,157 @@
+package ifrit
+
+import (
+	"errors"
+	"os"
+	"sync/atomic"
+)
+
+/*
+ErrSwapExited is returned by Swapper.Swap when the replacement Runner exits
+cleanly before it becomes ready.
+*/
+var ErrSwapExited = errors.New("replacement exited before it was ready")
+
+/*
+ErrSwapperAlreadyRun is returned by Swapper.Run when the Swapper has been run
+before, whether or not that run has returned.
+*/
+var ErrSwapperAlreadyRun = errors.New("swapper has already been run")
+
+/*
+A Swapper is a Runner which runs one child Runner at a time, and can replace it
+while running.  Swap starts the replacement, waits for it to become ready, and
+only then stops the old child with the stop signal, so there is no gap in
+service.
+
+Signals sent to the Swapper are delivered to every child it is running.  The
+Swapper exits with the error of its current child once that child exits for
+any reason other than being swapped out.  A Swapper may only be run once; later
+runs return ErrSwapperAlreadyRun.
+*/
+type Swapper struct {
+	initial    Runner
+	stopSignal os.Signal
+	swaps      chan swapRequest
+	exited     chan struct{}
+	started    atomic.Bool
+}
+
+type swapRequest struct {
+	runner Runner
+	reply  chan<- error
+}
+
+/*
+NewSwapper returns a Swapper which starts by running initial, and stops
+replaced children with stopSignal.
+*/
+func NewSwapper(initial Runner, stopSignal os.Signal) *Swapper {
+	return &Swapper{
+		initial:    initial,
+		stopSignal: stopSignal,
+		swaps:      make(chan swapRequest),
+		exited:     make(chan struct{}),
+	}
+}
+
+/*
+Swap replaces the current child with r, and returns once the old child has
+exited.  If r exits before it becomes ready, the old child keeps running and
+Swap returns r's error, or ErrSwapExited.  Swaps are performed one at a time; Swap
+blocks until the Swapper is running and any earlier swap has finished.
+*/
+func (s *Swapper) Swap(r Runner) error {
+	reply := make(chan error, 1)
+
+	select {
+	case s.swaps <- swapRequest{runner: r, reply: reply}:
+	case <-s.exited:
+		return ErrProcessStopped
+	}
+
+	return <-reply
+}
+
+func (s *Swapper) Run(signals <-chan os.Signal, ready chan<- struct{}) error {
+	if !s.started.CompareAndSwap(false, true) {
+		return ErrSwapperAlreadyRun
+	}
+	defer close(s.exited)
+
+	current := Background(s.initial)
+	currentReady := current.Ready()
+	currentExit := current.Wait()
+
+	var pending, retiring Process
+	var pendingReady <-chan struct{}
+	var pendingExit, retiringExit <-chan error
+	var reply chan<- error
+	var swaps <-chan swapRequest
+	signaled := false
+
+	for {
+		select {
+		case signal := <-signals:
+			signaled = true
+			swaps = nil
+			for _, p := range []Process{current, pending, retiring} {
+				if p != nil {
+					p.Signal(signal)
+				}
+			}
+
+		case <-currentReady:
+			currentReady = nil
+			close(ready)
+			if !signaled {
+				swaps = s.swaps
+			}
+
+		case request := <-swaps:
+			swaps = nil
+			reply = request.reply
+			pending = Background(request.runner)
+			pendingReady = pending.Ready()
+			pendingExit = pending.Wait()
+
+		case <-pendingReady:
+			retiring, retiringExit = current, currentExit
+			current, currentExit = pending, pendingExit
+			pending, pendingReady, pendingExit = nil, nil, nil
+			retiring.Signal(s.stopSignal)
+
+		case err := <-pendingExit:
+			pending, pendingReady, pendingExit = nil, nil, nil
+			if err == nil {
+				err = ErrSwapExited
+			}
+			reply <- err
+			reply = nil
+			if !signaled {
+				swaps = s.swaps
+			}
+
+		case <-retiringExit:
+			retiring, retiringExit = nil, nil
+			reply <- nil
+			reply = nil
+			if !signaled {
+				swaps = s.swaps
+			}
+
+		case err := <-currentExit:
+			if pending != nil {
+				pending.Signal(s.stopSignal)
+				<-pendingExit
+			}
+			if retiring != nil {
+				<-retiringExit
+			}
+			if reply != nil {
+				reply <- ErrProcessStopped
+			}
+			return err
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package ifrit_test
+
+import (
+	"errors"
+	"os"
+	"syscall"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/tedsuo/ifrit"
+	"github.com/tedsuo/ifrit/test_helpers"
+)
+
+var _ = Describe("Swapper", func() {
+	var old *test_helpers.SignalRecoder
+	var swapper *ifrit.Swapper
+	var process ifrit.Process
+
+	BeforeEach(func() {
+		old = test_helpers.NewSignalRecorder(syscall.SIGTERM, os.Kill)
+		swapper = ifrit.NewSwapper(old, syscall.SIGTERM)
+		process = ifrit.Invoke(swapper)
+	})
+
+	AfterEach(func() {
+		process.Signal(os.Kill)
+		Eventually(process.Wait()).Should(Receive())
+	})
+
+	It("replaces the child once the replacement is ready", func() {
+		release := make(chan struct{})
+		replacement := test_helpers.NewSignalRecorder(os.Kill)
+		slowStart := ifrit.RunFunc(func(signals <-chan os.Signal, ready chan<- struct{}) error {
+			<-release
+			return replacement.Run(signals, ready)
+		})
+
+		swapped := make(chan error, 1)
+		go func() {
+			swapped <- swapper.Swap(slowStart)
+		}()
+
+		Consistently(old.ReceivedSignals).Should(BeEmpty())
+		close(release)
+
+		Eventually(swapped).Should(Receive(BeNil()))
+		Ω(old.ReceivedSignals()).Should(Equal([]os.Signal{syscall.SIGTERM}))
+		Consistently(process.Wait()).ShouldNot(Receive())
+
+		process.Signal(syscall.SIGHUP)
+		Eventually(replacement.ReceivedSignals).Should(Equal([]os.Signal{syscall.SIGHUP}))
+		Ω(old.ReceivedSignals()).Should(Equal([]os.Signal{syscall.SIGTERM}))
+	})
+
+	It("keeps the old child when the replacement fails to start", func() {
+		startErr := errors.New("failed to start")
+		failing := ifrit.RunFunc(func(signals <-chan os.Signal, ready chan<- struct{}) error {
+			return startErr
+		})
+		Ω(swapper.Swap(failing)).Should(Equal(startErr))
+
+		clean := ifrit.RunFunc(func(signals <-chan os.Signal, ready chan<- struct{}) error {
+			return nil
+		})
+		Ω(swapper.Swap(clean)).Should(Equal(ifrit.ErrSwapExited))
+
+		Ω(old.ReceivedSignals()).Should(BeEmpty())
+		process.Signal(syscall.SIGHUP)
+		Eventually(old.ReceivedSignals).Should(Equal([]os.Signal{syscall.SIGHUP}))
+	})
+
+	It("exits with the error of its current child", func() {
+		process.Signal(syscall.SIGTERM)
+		Eventually(process.Wait()).Should(Receive(BeNil()))
+		Ω(swapper.Swap(test_helpers.NewSignalRecorder())).Should(Equal(ifrit.ErrProcessStopped))
+	})
+
+	It("may only be run once", func() {
+		Ω(<-ifrit.Invoke(swapper).Wait()).Should(Equal(ifrit.ErrSwapperAlreadyRun))
+
+		process.Signal(syscall.SIGTERM)
+		Eventually(process.Wait()).Should(Receive(BeNil()))
+		Ω(<-ifrit.Invoke(swapper).Wait()).Should(Equal(ifrit.ErrSwapperAlreadyRun))
+	})
+})
@@ -0,0 +1,77 @@
+package ifrit
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/tedsuo/ifrit/internal/signalqueue"
+)
+
+/*
+A DeadlineExceededError is returned by a Runner returned by WithTimeout when
+the Runner it wraps was still running at the deadline.  Err is the error the
+Runner exited with once it was stopped.  It matches context.DeadlineExceeded
+with errors.Is.
+*/
+type DeadlineExceededError struct {
+	Timeout time.Duration
+	Err     error
+}
+
+func (e DeadlineExceededError) Error() string {
+	return fmt.Sprintf("runner did not finish within %s", e.Timeout)
+}
+
+func (e DeadlineExceededError) Unwrap() error {
+	return e.Err
+}
+
+func (e DeadlineExceededError) Is(target error) bool {
+	return target == context.DeadlineExceeded
+}
+
+/*
+WithTimeout returns a Runner which runs r, and sends it the stop signal of
+DefaultStopper if it is still running timeout after it started.  Once r exits,
+the Runner returns a DeadlineExceededError.  If r exits before the deadline,
+its error is returned as-is.  This suits batch-style work run inside
+long-lived groups.
+*/
+func WithTimeout(r Runner, timeout time.Duration) Runner {
+	return RunFunc(func(signals <-chan os.Signal, ready chan<- struct{}) error {
+		innerSignals := make(chan os.Signal)
+		errs := make(chan error, 1)
+		go func() {
+			errs <- r.Run(innerSignals, ready)
+		}()
+
+		done := make(chan struct{})
+		defer close(done)
+		forward := signalqueue.Forward(innerSignals, done).Send
+
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		deadline := timer.C
+		exceeded := false
+
+		for {
+			select {
+			case signal := <-signals:
+				forward(signal)
+
+			case <-deadline:
+				deadline = nil
+				exceeded = true
+				forward(DefaultStopper.StopSignal)
+
+			case err := <-errs:
+				if exceeded {
+					return DeadlineExceededError{Timeout: timeout, Err: err}
+				}
+				return err
+			}
+		}
+	})
+}
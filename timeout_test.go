@@ -0,0 +1,82 @@
+package ifrit_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"syscall"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/tedsuo/ifrit"
+	"github.com/tedsuo/ifrit/test_helpers"
+)
+
+var _ = Describe("WithTimeout", func() {
+	It("returns the runner's error when it finishes in time", func() {
+		p := ifrit.Invoke(ifrit.WithTimeout(test_helpers.NoReadyRunner, time.Second))
+		Ω(<-p.Wait()).Should(Equal(test_helpers.NoReadyExitedNormally))
+	})
+
+	It("stops the runner at the deadline", func() {
+		recorder := test_helpers.NewSignalRecorder(syscall.SIGTERM)
+		p := ifrit.Invoke(ifrit.WithTimeout(recorder, 50*time.Millisecond))
+
+		var err error
+		Eventually(p.Wait()).Should(Receive(&err))
+		Ω(err).Should(Equal(ifrit.DeadlineExceededError{Timeout: 50 * time.Millisecond}))
+		Ω(errors.Is(err, context.DeadlineExceeded)).Should(BeTrue())
+		Ω(recorder.ReceivedSignals()).Should(Equal([]os.Signal{syscall.SIGTERM}))
+	})
+
+	It("wraps the error the runner exits with after the deadline", func() {
+		p := ifrit.Invoke(ifrit.WithTimeout(make(test_helpers.PingChan), 50*time.Millisecond))
+
+		var err error
+		Eventually(p.Wait()).Should(Receive(&err))
+		Ω(errors.Is(err, test_helpers.PingerExitedFromSignal)).Should(BeTrue())
+	})
+
+	It("forwards signals received before the deadline", func() {
+		recorder := test_helpers.NewSignalRecorder()
+		p := ifrit.Invoke(ifrit.WithTimeout(recorder, time.Second))
+
+		p.Signal(os.Interrupt)
+		Eventually(p.Wait()).Should(Receive(BeNil()))
+		Ω(recorder.ReceivedSignals()).Should(Equal([]os.Signal{os.Interrupt}))
+	})
+
+	It("forwards signals in the order they were received", func() {
+		release := make(chan struct{})
+		received := make(chan os.Signal, 10)
+		slow := ifrit.RunFunc(func(signals <-chan os.Signal, ready chan<- struct{}) error {
+			close(ready)
+			<-release
+			for signal := range signals {
+				received <- signal
+				if signal == os.Interrupt {
+					return nil
+				}
+			}
+			return nil
+		})
+
+		signals := make(chan os.Signal)
+		errs := make(chan error, 1)
+		go func() {
+			errs <- ifrit.WithTimeout(slow, time.Second).Run(signals, make(chan struct{}))
+		}()
+
+		sent := []os.Signal{syscall.SIGHUP, syscall.SIGUSR1, syscall.SIGUSR2, os.Interrupt}
+		for _, signal := range sent {
+			signals <- signal
+		}
+		close(release)
+
+		Eventually(errs).Should(Receive(BeNil()))
+		for _, signal := range sent {
+			Ω(received).Should(Receive(Equal(signal)))
+		}
+	})
+})
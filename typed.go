@@ -0,0 +1,112 @@
+package ifrit
+
+import "os"
+
+/*
+A RunnerOf is a Runner which publishes a value, such as the address it is bound
+to or a client for the service it runs, when it becomes ready.  Rather than
+closing a ready channel, it sends the value on ready exactly once.  Its other
+responsibilities are those of a Runner.
+*/
+type RunnerOf[T any] interface {
+	Run(signals <-chan os.Signal, ready chan<- T) error
+}
+
+/*
+The RunFuncOf type is an adapter to allow the use of ordinary functions as
+RunnersOf.
+*/
+type RunFuncOf[T any] func(signals <-chan os.Signal, ready chan<- T) error
+
+func (r RunFuncOf[T]) Run(signals <-chan os.Signal, ready chan<- T) error {
+	return r(signals, ready)
+}
+
+/*
+A ProcessOf is a Process whose Runner publishes a value when it becomes ready.
+*/
+type ProcessOf[T any] interface {
+	Process
+
+	// ReadyValue returns the value the Runner published.  It returns the zero
+	// value until Ready is closed.
+	ReadyValue() T
+}
+
+type processOf[T any] struct {
+	Process
+	value T
+}
+
+func (p *processOf[T]) ReadyValue() T {
+	select {
+	case <-p.Ready():
+		return p.value
+	default:
+		var zero T
+		return zero
+	}
+}
+
+/*
+InvokeOf executes a RunnerOf and returns a ProcessOf once the RunnerOf is ready,
+as Invoke does for a Runner.
+*/
+func InvokeOf[T any](r RunnerOf[T]) ProcessOf[T] {
+	p := BackgroundOf(r)
+
+	select {
+	case <-p.Ready():
+	case <-p.Wait():
+	}
+
+	return p
+}
+
+/*
+BackgroundOf executes a RunnerOf and returns a ProcessOf immediately, without
+waiting.
+*/
+func BackgroundOf[T any](r RunnerOf[T]) ProcessOf[T] {
+	p := &processOf[T]{}
+	p.Process = Background(RunFunc(func(signals <-chan os.Signal, ready chan<- struct{}) error {
+		return runOf(r, signals, ready, &p.value)
+	}))
+	return p
+}
+
+/*
+Untyped returns a Runner which runs r and discards the value it publishes, so
+that it can be used wherever a Runner is expected, such as in a group.
+*/
+func Untyped[T any](r RunnerOf[T]) Runner {
+	return RunFunc(func(signals <-chan os.Signal, ready chan<- struct{}) error {
+		var value T
+		return runOf(r, signals, ready, &value)
+	})
+}
+
+// runOf runs r, storing the value it publishes in value before closing ready.
+func runOf[T any](r RunnerOf[T], signals <-chan os.Signal, ready chan<- struct{}, value *T) error {
+	values := make(chan T, 1)
+	errs := make(chan error, 1)
+	go func() {
+		errs <- r.Run(signals, values)
+	}()
+
+	select {
+	case v := <-values:
+		*value = v
+		close(ready)
+		return <-errs
+
+	case err := <-errs:
+		select {
+		case v := <-values:
+			*value = v
+			close(ready)
+		default:
+		}
+		return err
+	}
+}
@@ -0,0 +1,67 @@
+package ifrit_test
+
+import (
+	"errors"
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/tedsuo/ifrit"
+)
+
+var _ = Describe("RunnerOf", func() {
+	var exitErr = errors.New("exited")
+
+	addressRunner := ifrit.RunFuncOf[string](func(signals <-chan os.Signal, ready chan<- string) error {
+		ready <- "127.0.0.1:8080"
+		<-signals
+		return exitErr
+	})
+
+	It("publishes the runner's value once it is ready", func() {
+		process := ifrit.InvokeOf[string](addressRunner)
+		Ω(process.Ready()).Should(BeClosed())
+		Ω(process.ReadyValue()).Should(Equal("127.0.0.1:8080"))
+
+		process.Signal(os.Interrupt)
+		Eventually(process.Wait()).Should(Receive(Equal(exitErr)))
+		Ω(process.ReadyValue()).Should(Equal("127.0.0.1:8080"))
+	})
+
+	It("returns the zero value until the runner is ready", func() {
+		publish := make(chan struct{})
+		runner := ifrit.RunFuncOf[int](func(signals <-chan os.Signal, ready chan<- int) error {
+			<-publish
+			ready <- 42
+			<-signals
+			return nil
+		})
+
+		process := ifrit.BackgroundOf[int](runner)
+		Consistently(process.ReadyValue).Should(BeZero())
+
+		close(publish)
+		Eventually(process.ReadyValue).Should(Equal(42))
+		process.Signal(os.Interrupt)
+		Eventually(process.Wait()).Should(Receive(BeNil()))
+	})
+
+	It("waits normally for a runner which exits without becoming ready", func() {
+		runner := ifrit.RunFuncOf[string](func(signals <-chan os.Signal, ready chan<- string) error {
+			return exitErr
+		})
+
+		process := ifrit.InvokeOf[string](runner)
+		Eventually(process.Wait()).Should(Receive(Equal(exitErr)))
+		Ω(process.Ready()).ShouldNot(BeClosed())
+		Ω(process.ReadyValue()).Should(BeEmpty())
+	})
+
+	It("can be used as an untyped Runner", func() {
+		process := ifrit.Invoke(ifrit.Untyped[string](addressRunner))
+		Ω(process.Ready()).Should(BeClosed())
+
+		process.Signal(os.Interrupt)
+		Eventually(process.Wait()).Should(Receive(Equal(exitErr)))
+	})
+})
@@ -0,0 +1,47 @@
+package ifrit
+
+import (
+	"os"
+	"time"
+)
+
+/*
+WatchReady returns a Runner which runs r, and calls onSlow if r has not become
+ready within threshold, so that Runners stuck starting up can be detected.  r
+is not signaled or killed; onSlow only reports that it is slow.  onSlow is
+called at most once, from the goroutine running the Runner.
+*/
+func WatchReady(r Runner, threshold time.Duration, onSlow func()) Runner {
+	return RunFunc(func(signals <-chan os.Signal, ready chan<- struct{}) error {
+		innerReady := make(chan struct{})
+		errs := make(chan error, 1)
+		go func() {
+			errs <- r.Run(signals, innerReady)
+		}()
+
+		timer := time.NewTimer(threshold)
+		defer timer.Stop()
+		slow := timer.C
+
+		for {
+			select {
+			case <-innerReady:
+				innerReady = nil
+				slow = nil
+				close(ready)
+
+			case <-slow:
+				slow = nil
+				onSlow()
+
+			case err := <-errs:
+				select {
+				case <-innerReady:
+					close(ready)
+				default:
+				}
+				return err
+			}
+		}
+	})
+}
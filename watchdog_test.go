@@ -0,0 +1,59 @@
+package ifrit_test
+
+import (
+	"os"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/tedsuo/ifrit"
+	"github.com/tedsuo/ifrit/test_helpers"
+)
+
+var _ = Describe("WatchReady", func() {
+	var slow chan struct{}
+
+	BeforeEach(func() {
+		slow = make(chan struct{}, 1)
+	})
+
+	onSlow := func() {
+		slow <- struct{}{}
+	}
+
+	It("does not report a runner which becomes ready in time", func() {
+		p := ifrit.Invoke(ifrit.WatchReady(make(test_helpers.PingChan), 50*time.Millisecond, onSlow))
+		Ω(p.Ready()).Should(BeClosed())
+		Consistently(slow, 100*time.Millisecond).ShouldNot(Receive())
+
+		p.Signal(os.Kill)
+		Eventually(p.Wait()).Should(Receive(Equal(test_helpers.PingerExitedFromSignal)))
+	})
+
+	It("reports a runner which is slow to become ready, without stopping it", func() {
+		release := make(chan struct{})
+		slowStart := ifrit.RunFunc(func(signals <-chan os.Signal, ready chan<- struct{}) error {
+			<-release
+			close(ready)
+			<-signals
+			return nil
+		})
+
+		p := ifrit.Background(ifrit.WatchReady(slowStart, 50*time.Millisecond, onSlow))
+		Eventually(slow).Should(Receive())
+		Ω(p.Ready()).ShouldNot(BeClosed())
+
+		close(release)
+		Eventually(p.Ready()).Should(BeClosed())
+		Consistently(slow).ShouldNot(Receive())
+
+		p.Signal(os.Interrupt)
+		Eventually(p.Wait()).Should(Receive(BeNil()))
+	})
+
+	It("does not report a runner which exits before the threshold", func() {
+		p := ifrit.Invoke(ifrit.WatchReady(test_helpers.NoReadyRunner, 50*time.Millisecond, onSlow))
+		Ω(<-p.Wait()).Should(Equal(test_helpers.NoReadyExitedNormally))
+		Consistently(slow, 100*time.Millisecond).ShouldNot(Receive())
+	})
+})